@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+)
+
+// liveSpans 실시간 보기에 보여 줄 이동 구간
+var liveSpans = []time.Duration{time.Second, 10 * time.Second, time.Minute}
+
+// rollingSlot 1초 동안의 레이턴시와 에러 수
+type rollingSlot struct {
+	second int64
+	hist   *histogram.Histogram
+	errors int64
+}
+
+// rolling 최근 1분을 1초 칸으로 나눠 보관하는 링 버퍼. 이동 구간은 칸을 합쳐서 구한다.
+// 아직 채워지는 중인 현재 초는 이동 구간에 넣지 않는다.
+type rolling struct {
+	slots []rollingSlot
+}
+
+func newRolling() *rolling {
+	r := &rolling{slots: make([]rollingSlot, int(liveSpans[len(liveSpans)-1]/time.Second)+1)}
+	for i := range r.slots {
+		r.slots[i] = rollingSlot{second: -1, hist: histogram.New(time.Microsecond, time.Minute, 2)}
+	}
+	return r
+}
+
+// slot now 가 속한 칸. 오래된 칸은 비우고 다시 쓴다.
+func (r *rolling) slot(now time.Time) *rollingSlot {
+	sec := now.Unix()
+	s := &r.slots[int(sec%int64(len(r.slots)))]
+	if s.second != sec {
+		s.second = sec
+		s.hist.Reset()
+		s.errors = 0
+	}
+	return s
+}
+
+func (r *rolling) record(latency time.Duration, now time.Time) {
+	r.slot(now).hist.Record(latency)
+}
+
+func (r *rolling) recordError(now time.Time) {
+	r.slot(now).errors++
+}
+
+// summary now 직전의 완료된 span 동안의 요약. 처리량은 span 으로 나눈 값이다.
+func (r *rolling) summary(now time.Time, span time.Duration) report.Summary {
+	merged := histogram.New(time.Microsecond, time.Minute, 2)
+	var errors int64
+	current := now.Unix()
+	for sec := current - int64(span/time.Second); sec < current; sec++ {
+		s := &r.slots[int(sec%int64(len(r.slots)))]
+		if s.second != sec {
+			continue
+		}
+		merged.Merge(s.hist)
+		errors += s.errors
+	}
+	summary := report.NewSummary(spanName(span), merged, span)
+	summary.Histogram = nil
+	summary.Errors = errors
+	return summary
+}
+
+// spanName "1s", "10s", "1m" 처럼 짧은 구간 이름
+func spanName(span time.Duration) string {
+	if span >= time.Minute && span%time.Minute == 0 {
+		return fmt.Sprintf("%dm", span/time.Minute)
+	}
+	return span.String()
+}
+
+// liveStatus /live 가 돌려주는 JSON
+type liveStatus struct {
+	Run           string           `json:"run,omitempty"`
+	Time          time.Time        `json:"time"`
+	Records       int              `json:"records"`
+	TotalRequests int              `json:"total_requests"`
+	Clients       int              `json:"clients"`
+	Windows       []report.Summary `json:"windows"`
+}
+
+func (a *aggregator) liveStatus() liveStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	status := liveStatus{Run: a.name, Time: now, Records: a.current, TotalRequests: a.totalRequests, Clients: len(a.clients)}
+	for _, span := range liveSpans {
+		status.Windows = append(status.Windows, a.live.summary(now, span))
+	}
+	return status
+}
+
+// serveLive 최근 1초/10초/1분 동안의 백분위수, RPS, 에러 수를 JSON 으로 돌려주는 핸들러
+func (a *aggregator) serveLive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a.liveStatus())
+}
+
+// printLive 이동 구간 요약을 표로 출력한다. clear 이면 화면을 지우고 맨 위에 다시 그린다.
+func printLive(w io.Writer, status liveStatus, clear bool) {
+	if clear {
+		fmt.Fprint(w, "\033[H\033[2J")
+	}
+	fmt.Fprintf(w, "%s  run %s  records %d/%d  clients %d\n",
+		status.Time.Format("15:04:05"), status.Run, status.Records, status.TotalRequests, status.Clients)
+	fmt.Fprintf(w, "%-6s | %-7s | %-6s | %-7s | %-7s | %-7s | %-7s | %-7s | %-7s\n",
+		"Window", "RPS", "Errors", "Mean", "P50", "P90", "P99", "P99.9", "Max")
+	for _, s := range status.Windows {
+		fmt.Fprintf(w, "%-6s | %-7.1f | %-6d | %-7.1f | %-7.1f | %-7.1f | %-7.1f | %-7.1f | %-7.1f\n",
+			s.Name, s.Throughput, s.Errors, ms(s.Mean), ms(s.P50), ms(s.P90), ms(s.P99), ms(s.P999), ms(s.Max))
+	}
+}
+
+// watchLive interval 마다 가장 최근에 시작한 실행이 아직 실행 중이면 printLive 로 실시간 보기를 갱신한다.
+// 터미널이면 화면을 지우고 다시 그린다.
+func (m *runs) watchLive(interval time.Duration) {
+	clear := isTerminal(os.Stdout)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		a, err := m.lookup("")
+		if err != nil {
+			continue
+		}
+		a.mu.Lock()
+		running := a.state == runRunning
+		a.mu.Unlock()
+		if running {
+			printLive(os.Stdout, a.liveStatus(), clear)
+		}
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLive(t *testing.T) {
+	a := newAggregator(1000)
+	clock := time.Unix(1000, 0)
+	a.now = func() time.Time { return clock }
+	server := httptest.NewServer(http.HandlerFunc(a.recordLatency))
+	defer server.Close()
+
+	// 10초 동안 매초 10ms 레이턴시 10 개, 마지막 1초는 100ms 레이턴시 10 개와 에러 2 개
+	for sec := 0; sec < 10; sec++ {
+		latency := 10 * time.Millisecond
+		if sec == 9 {
+			latency = 100 * time.Millisecond
+			post(t, server.URL, LatencyData{ClientID: "client-1", Error: "Unavailable"})
+			post(t, server.URL, LatencyData{ClientID: "client-1", Error: "Unavailable"})
+		}
+		for i := 0; i < 10; i++ {
+			post(t, server.URL, LatencyData{ClientID: "client-1", Latency: latency})
+		}
+		clock = clock.Add(time.Second)
+	}
+
+	status := a.liveStatus()
+	if status.Records != 102 || len(status.Windows) != 3 {
+		t.Fatalf("status = %+v", status)
+	}
+	last, tenSeconds := status.Windows[0], status.Windows[1]
+	if last.Name != "1s" || last.Count != 10 || last.Errors != 2 || last.Throughput != 10 || last.P50 < 99*time.Millisecond {
+		t.Errorf("1s window = %+v, want the 100ms second with 2 errors", last)
+	}
+	if tenSeconds.Count != 100 || tenSeconds.Throughput != 10 || tenSeconds.P50 > 11*time.Millisecond || tenSeconds.P99 < 99*time.Millisecond {
+		t.Errorf("10s window = %+v", tenSeconds)
+	}
+
+	// 1분 넘게 지나면 오래된 칸은 구간에서 빠진다.
+	clock = clock.Add(2 * time.Minute)
+	if s := a.liveStatus().Windows[2]; s.Count != 0 || s.Errors != 0 {
+		t.Errorf("1m window after two idle minutes = %+v, want empty", s)
+	}
+}
+
+func TestServeLive(t *testing.T) {
+	a := newAggregator(10)
+	server := httptest.NewServer(http.HandlerFunc(a.serveLive))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var status liveStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.TotalRequests != 10 || len(status.Windows) != 3 || status.Windows[2].Name != "1m" {
+		t.Errorf("GET /live = %+v", status)
+	}
+
+	var out bytes.Buffer
+	printLive(&out, status, false)
+	if !strings.Contains(out.String(), "records 0/10") || strings.Contains(out.String(), "\033") {
+		t.Errorf("printLive output:\n%q", out.String())
+	}
+}
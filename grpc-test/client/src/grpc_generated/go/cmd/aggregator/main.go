@@ -0,0 +1,618 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/collector"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/store"
+)
+
+type LatencyData struct {
+	ClientID string        `json:"client_id"`
+	Latency  time.Duration `json:"latency"`
+	// Error 가 있으면 실패한 요청이고 값은 gRPC 상태 코드 이름이다. 레이턴시 대신 코드별 에러 수에 더한다.
+	Error string `json:"error,omitempty"`
+	// Model, Version 이 없으면 집계 서버 설정의 모델로 본다.
+	Model   string `json:"model,omitempty"`
+	Version string `json:"version,omitempty"`
+	// InFlight 클라이언트가 기록을 보낼 때 처리 중이던 요청 수
+	InFlight int64 `json:"in_flight,omitempty"`
+	// BatchSize, Concurrency, LoadMode 클라이언트가 부하를 준 조건. 보고서의 실행 조건이 된다.
+	BatchSize   int    `json:"batch_size,omitempty"`
+	Concurrency int    `json:"concurrency,omitempty"`
+	LoadMode    string `json:"load_mode,omitempty"`
+	// RunID 기록을 넣을 실행. 없으면 가장 최근에 시작한 실행에 넣는다.
+	RunID string `json:"run_id,omitempty"`
+	// At 클라이언트에서 요청이 끝난 시각. 없으면 받은 시각을 쓴다.
+	At time.Time `json:"at"`
+}
+
+// clientStats 클라이언트 하나의 레이턴시 히스토그램, 첫/마지막 성공 시각, 상태 코드별 실패 수
+type clientStats struct {
+	hist   *histogram.Histogram
+	first  time.Time
+	last   time.Time
+	errors map[string]int64
+
+	// 아래는 클라이언트별 통계에만 쓴다.
+	model       string
+	version     string
+	inFlight    int64
+	batchSize   int
+	concurrency int
+	loadMode    string
+	// windows aggregator.windows 와 같은 시간 구간의 이 클라이언트 기록
+	windows []*clientStats
+}
+
+func newClientStats() *clientStats {
+	return &clientStats{hist: histogram.NewLatency()}
+}
+
+// newWindowStats 시간 구간용. 구간이 많아질 수 있으므로 유효 숫자 2 자리 (약 20KB) 로 기록한다.
+func newWindowStats() *clientStats {
+	return &clientStats{hist: histogram.New(time.Microsecond, time.Minute, 2)}
+}
+
+func (s *clientStats) record(latency time.Duration, now time.Time) {
+	if s.hist.Count() == 0 {
+		s.first = now
+	}
+	s.last = now
+	s.hist.Record(latency)
+}
+
+func (s *clientStats) recordError(code string) {
+	if s.errors == nil {
+		s.errors = make(map[string]int64)
+	}
+	s.errors[code]++
+}
+
+// errorCount 모든 상태 코드의 실패 수
+func (s *clientStats) errorCount() int64 {
+	var n int64
+	for _, c := range s.errors {
+		n += c
+	}
+	return n
+}
+
+// merge other 의 기록을 s 에 더한다.
+func (s *clientStats) merge(other *clientStats) {
+	for code, n := range other.errors {
+		if s.errors == nil {
+			s.errors = make(map[string]int64)
+		}
+		s.errors[code] += n
+	}
+	if other.hist.Count() == 0 {
+		return
+	}
+	if s.hist.Count() == 0 || other.first.Before(s.first) {
+		s.first = other.first
+	}
+	if other.last.After(s.last) {
+		s.last = other.last
+	}
+	s.hist.Merge(other.hist)
+}
+
+// throughput 첫 기록부터 마지막 기록까지 초당 기록 수
+func (s *clientStats) throughput() float64 {
+	elapsed := s.last.Sub(s.first).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.hist.Count()) / elapsed
+}
+
+// 실행 상태. created 에서 start 로 running 이 되고, 끝나면 stopped 가 된다.
+const (
+	runCreated = "created"
+	runRunning = "running"
+	runStopped = "stopped"
+)
+
+// aggregator 실행 하나. 클라이언트별·시간 구간별 레이턴시를 히스토그램으로 모은다. 성공과 실패를 합쳐 totalRequests 개가
+// 모이거나 첫 기록부터 duration 이 지나거나 stop 을 부르면 done 을 닫는다. 클라이언트가 죽어도 duration 이 있으면 끝난다.
+// 요청 수와 상관없이 클라이언트와 시간 구간마다 히스토그램 하나만큼의 메모리를 쓰고,
+// 시간 구간은 maxWindows 개를 넘지 않으므로 실행이 길어져도 메모리가 늘지 않는다.
+type aggregator struct {
+	mu            sync.Mutex
+	name          string
+	state         string
+	created       time.Time
+	stopped       time.Time
+	clients       map[string]*clientStats
+	windows       []*clientStats
+	window        time.Duration
+	live          *rolling
+	model         string
+	version       string
+	started       time.Time
+	totalRequests int
+	duration      time.Duration
+	current       int
+	seen          collector.Seen
+	samples       reservoir
+	done          chan struct{}
+	now           func() time.Time
+
+	// reports 결과를 출력하고 보고서를 쓴 뒤 닫는다. 경로는 reportPaths 에 남는다.
+	reports     chan struct{}
+	reportPaths []string
+}
+
+// maxWindows 실행 하나의 시간 구간 수 상한. 넘으면 구간 길이를 두 배로 늘리고 이웃한 두 구간을 합치므로
+// 구간 히스토그램은 maxWindows × (약 20KB + 클라이언트 수 × 약 3KB) 를 넘지 않는다.
+// 예를 들어 10 초 구간으로 24 시간을 돌리면 구간은 1280 초 길이의 68 개가 된다.
+const maxWindows = 120
+
+// maxRecordAge 받은 시각보다 이만큼 넘게 이른 Record.At 은 믿지 않는다. 배치 주기 (FLUSH_INTERVAL) 와
+// Sender 의 재시도를 합친 것보다 넉넉히 길다.
+const maxRecordAge = time.Minute
+
+// newAggregator 바로 기록을 받는 (running) 실행
+func newAggregator(totalRequests int) *aggregator {
+	return &aggregator{
+		state:         runRunning,
+		created:       time.Now(),
+		reports:       make(chan struct{}),
+		clients:       make(map[string]*clientStats),
+		window:        10 * time.Second,
+		live:          newRolling(),
+		samples:       reservoir{max: maxSamples},
+		totalRequests: totalRequests,
+		done:          make(chan struct{}),
+		now:           time.Now,
+	}
+}
+
+// recordLatency 수집된 레이턴시 데이터를 저장하는 핸들러
+func (a *aggregator) recordLatency(w http.ResponseWriter, r *http.Request) {
+	var data LatencyData
+	err := json.NewDecoder(r.Body).Decode(&data)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	a.recordData(w, data)
+}
+
+// recordData 기록 하나를 저장하고 응답을 쓴다. 실행 중이 아니면 409 를 돌려준다.
+func (a *aggregator) recordData(w http.ResponseWriter, data LatencyData) {
+	a.mu.Lock()
+	if a.state != runRunning {
+		a.mu.Unlock()
+		http.Error(w, fmt.Sprintf("Run %s is %s", a.name, a.state), http.StatusConflict)
+		return
+	}
+	a.record(data, a.now())
+	a.mu.Unlock()
+
+	if data.Error != "" {
+		fmt.Fprintf(w, "Error recorded for client: %s", data.ClientID)
+		return
+	}
+	fmt.Fprintf(w, "Latency recorded for client: %s", data.ClientID)
+}
+
+// recordBatch 클라이언트가 모아 보낸 배치 (gzip 가능) 를 저장하는 핸들러. 이미 받은 배치는 다시 세지 않고 200 을 돌려준다.
+// 배치의 기록은 클라이언트에서 요청이 끝난 시각 (Record.At) 의 시간 구간에 들어간다.
+func (a *aggregator) recordBatch(w http.ResponseWriter, r *http.Request) {
+	batch, err := collector.ReadBatch(r)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	a.recordBatchData(w, batch)
+}
+
+// recordBatchData 배치를 저장하고 응답을 쓴다. 실행 중이 아니면 받았다고 기억하지 않고 409 를 돌려준다.
+// 배치 도중에 totalRequests 를 채워 실행이 끝나면 남은 기록은 버리고, 버린 수를 응답에 적는다.
+// 배치는 받은 것으로 기억하므로 클라이언트가 다시 보내도 끝난 실행에 더하지 않는다.
+func (a *aggregator) recordBatchData(w http.ResponseWriter, batch *collector.Batch) {
+	a.mu.Lock()
+	if a.state != runRunning {
+		a.mu.Unlock()
+		http.Error(w, fmt.Sprintf("Run %s is %s", a.name, a.state), http.StatusConflict)
+		return
+	}
+	if !a.seen.Add(batch) {
+		a.mu.Unlock()
+		fmt.Fprintf(w, "Batch %d already recorded for client: %s", batch.Seq, batch.ClientID)
+		return
+	}
+	now := a.now()
+	recorded := 0
+	for _, rec := range batch.Records {
+		if a.state != runRunning {
+			break
+		}
+		a.record(LatencyData{
+			ClientID:    batch.ClientID,
+			Latency:     rec.Latency,
+			Error:       rec.Error,
+			Model:       batch.Model,
+			Version:     batch.Version,
+			InFlight:    batch.InFlight,
+			BatchSize:   batch.BatchSize,
+			Concurrency: batch.Concurrency,
+			LoadMode:    batch.LoadMode,
+			At:          rec.At,
+		}, now)
+		recorded++
+	}
+	name := a.name
+	a.mu.Unlock()
+
+	if dropped := len(batch.Records) - recorded; dropped > 0 {
+		log.Printf("Run %s finished in batch %d from client %s, dropped %d records", name, batch.Seq, batch.ClientID, dropped)
+		fmt.Fprintf(w, "Batch %d recorded for client: %s, dropped %d records after run %s finished", batch.Seq, batch.ClientID, dropped, name)
+		return
+	}
+	fmt.Fprintf(w, "Batch %d recorded for client: %s", batch.Seq, batch.ClientID)
+}
+
+// record 기록 하나를 클라이언트, 시간 구간, 실시간 보기에 더한다. a.mu 를 잡은 채로 호출한다.
+// 시간 구간과 처리량은 클라이언트가 요청을 끝낸 시각 (data.At) 으로, 실시간 보기는 받은 시각 now 로 센다.
+// 실행 시작은 클라이언트 시계와 상관없이 첫 기록을 받은 시각이다. data.At 이 now 보다 늦거나
+// maxRecordAge 보다 오래됐으면 클라이언트 시계가 어긋난 것으로 보고 now 를, 실행 시작보다 이르면 시작 시각을 쓴다.
+func (a *aggregator) record(data LatencyData, now time.Time) {
+	at := now
+	if !data.At.IsZero() && data.At.Before(now) && now.Sub(data.At) <= maxRecordAge {
+		at = data.At
+	}
+	if a.started.IsZero() {
+		a.started = now
+		if a.duration > 0 {
+			time.AfterFunc(a.duration, func() {
+				a.mu.Lock()
+				a.finish()
+				a.mu.Unlock()
+			})
+		}
+	}
+	stats := a.client(data.ClientID)
+	if data.Model != "" {
+		stats.model, stats.version = data.Model, data.Version
+	}
+	stats.inFlight = data.InFlight
+	if data.BatchSize > 0 {
+		stats.batchSize, stats.concurrency, stats.loadMode = data.BatchSize, data.Concurrency, data.LoadMode
+	}
+	if at.Before(a.started) {
+		at = a.started
+	}
+	i := int(at.Sub(a.started) / a.window)
+	for i >= maxWindows {
+		a.widenWindows()
+		i = int(at.Sub(a.started) / a.window)
+	}
+	for len(a.windows) <= i {
+		a.windows = append(a.windows, newWindowStats())
+	}
+	for len(stats.windows) <= i {
+		stats.windows = append(stats.windows, newCellStats())
+	}
+	sample := report.Sample{Offset: at.Sub(a.started), Client: data.ClientID, Error: data.Error}
+	if data.Error != "" {
+		stats.recordError(data.Error)
+		stats.windows[i].recordError(data.Error)
+		a.windows[i].recordError(data.Error)
+		a.live.recordError(now)
+	} else {
+		sample.Latency = data.Latency
+		stats.record(data.Latency, at)
+		stats.windows[i].record(data.Latency, at)
+		a.windows[i].record(data.Latency, at)
+		a.live.record(data.Latency, now)
+	}
+	a.samples.add(sample)
+	a.current++
+	if a.totalRequests > 0 && a.current >= a.totalRequests {
+		a.finish()
+	}
+}
+
+// widenWindows 구간 길이를 두 배로 늘리고 이웃한 두 구간씩 합친다. a.mu 를 잡은 채로 호출한다.
+func (a *aggregator) widenWindows() {
+	a.window *= 2
+	a.windows = mergePairs(a.windows)
+	for _, stats := range a.clients {
+		stats.windows = mergePairs(stats.windows)
+	}
+}
+
+// mergePairs windows 의 2j, 2j+1 번째 구간을 합쳐 j 번째 구간으로 만든다. windows 를 덮어쓴다.
+func mergePairs(windows []*clientStats) []*clientStats {
+	merged := windows[:(len(windows)+1)/2]
+	for j := range merged {
+		w := windows[2*j]
+		if 2*j+1 < len(windows) {
+			w.merge(windows[2*j+1])
+		}
+		merged[j] = w
+	}
+	clear(windows[len(merged):])
+	return merged
+}
+
+// client clientID 의 통계. 처음 보는 클라이언트면 만든다. a.mu 를 잡은 채로 호출한다.
+func (a *aggregator) client(clientID string) *clientStats {
+	stats, ok := a.clients[clientID]
+	if !ok {
+		stats = newClientStats()
+		stats.model, stats.version = a.model, a.version
+		a.clients[clientID] = stats
+	}
+	return stats
+}
+
+// finish 실행을 멈추고 done 을 한 번만 닫는다. a.mu 를 잡은 채로 호출한다.
+func (a *aggregator) finish() {
+	if a.state != runStopped {
+		a.state = runStopped
+		a.stopped = time.Now()
+		close(a.done)
+	}
+}
+
+// total 모든 클라이언트의 히스토그램을 합친 결과
+func (a *aggregator) total() *clientStats {
+	total := newClientStats()
+	for _, stats := range a.clients {
+		total.merge(stats)
+	}
+	return total
+}
+
+// report 지금까지 모은 결과로 보고서를 만든다. 처리량은 첫 기록부터 마지막 기록까지로 계산하고,
+// 시간 구간의 처리량은 구간 길이로 계산한다.
+func (a *aggregator) report(meta report.Metadata) *report.Report {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	total := a.total()
+	if meta.StartedAt.IsZero() {
+		meta.StartedAt = a.started
+	}
+	if meta.FinishedAt.IsZero() {
+		meta.FinishedAt = total.last
+	}
+	a.fillLoadConditions(&meta)
+	r := &report.Report{
+		Metadata: meta,
+		Total:    report.NewSummary("ALL", total.hist, total.last.Sub(total.first)).WithErrors(total.errors),
+	}
+	for _, id := range a.clientIDs() {
+		stats := a.clients[id]
+		r.Clients = append(r.Clients, report.NewSummary(id, stats.hist, stats.last.Sub(stats.first)).WithErrors(stats.errors))
+		cells := make([]report.Cell, len(a.windows))
+		for j, w := range stats.windows {
+			cells[j] = w.cell()
+		}
+		r.ClientWindows = append(r.ClientWindows, cells)
+	}
+	r.Samples = a.samples.sorted()
+	for i, stats := range a.windows {
+		start := a.started.Add(time.Duration(i) * a.window)
+		r.Windows = append(r.Windows, report.Window{
+			Start:   start,
+			End:     start.Add(a.window),
+			Summary: report.NewSummary("", stats.hist, a.window).WithErrors(stats.errors),
+		})
+	}
+	return r
+}
+
+// fillLoadConditions meta 에 비어 있는 부하 조건을 클라이언트가 보낸 값으로 채운다. 동시 사용자 수는
+// 모든 클라이언트의 합이고, 배치 크기와 부하 방식은 클라이언트마다 다르면 비워 둔다. a.mu 를 잡은 채로 호출한다.
+func (a *aggregator) fillLoadConditions(meta *report.Metadata) {
+	sizes := make(map[int]bool)
+	modes := make(map[string]bool)
+	concurrency := 0
+	for _, stats := range a.clients {
+		if stats.batchSize == 0 {
+			continue
+		}
+		sizes[stats.batchSize] = true
+		modes[stats.loadMode] = true
+		concurrency += stats.concurrency
+	}
+	if meta.BatchSize == 0 && len(sizes) == 1 {
+		for size := range sizes {
+			meta.BatchSize = size
+		}
+	}
+	if meta.Concurrency == 0 {
+		meta.Concurrency = concurrency
+	}
+	if meta.LoadMode == "" && len(modes) == 1 {
+		for mode := range modes {
+			meta.LoadMode = mode
+		}
+	}
+}
+
+// clientIDs 클라이언트 ID 를 정렬해서 돌려준다. a.mu 를 잡은 채로 호출한다.
+func (a *aggregator) clientIDs() []string {
+	ids := make([]string, 0, len(a.clients))
+	for id := range a.clients {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// showResults 수집된 결과를 읽기 쉽게 출력하는 함수. 레이턴시는 성공한 요청의 ms 단위 값이다.
+// 실패가 있으면 클라이언트별 상태 코드 분포를 덧붙인다.
+func (a *aggregator) showResults(w io.Writer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	line := "------------------------------------------------------------------------------------------------------------------------------------"
+	fmt.Fprintln(w, "Final Results:")
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "%-10s | %-7s | %-7s | %-6s | %-7s | %-7s | %-7s | %-7s | %-7s | %-7s | %-7s | %-7s | %-7s\n",
+		"ClientID", "Count", "Errors", "Err%", "Mean", "StdDev", "P50", "P90", "P95", "P99", "P99.9", "Max", "RPS")
+	fmt.Fprintln(w, line)
+
+	ids := a.clientIDs()
+	for _, id := range ids {
+		printStats(w, id, a.clients[id])
+	}
+	fmt.Fprintln(w, line)
+	total := a.total()
+	printStats(w, "ALL", total)
+	fmt.Fprintln(w, line)
+
+	if len(total.errors) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Errors by code:")
+	for _, id := range append(ids, "ALL") {
+		stats := total
+		if id != "ALL" {
+			stats = a.clients[id]
+		}
+		if len(stats.errors) == 0 {
+			continue
+		}
+		var counts []string
+		for _, code := range errorCodes(stats.errors) {
+			counts = append(counts, fmt.Sprintf("%s=%d", code, stats.errors[code]))
+		}
+		fmt.Fprintf(w, "%-10s | %s\n", id, strings.Join(counts, ", "))
+	}
+}
+
+func printStats(w io.Writer, name string, stats *clientStats) {
+	h := stats.hist
+	errors := stats.errorCount()
+	errRate := 0.0
+	if requests := h.Count() + errors; requests > 0 {
+		errRate = 100 * float64(errors) / float64(requests)
+	}
+	fmt.Fprintf(w, "%-10s | %-7d | %-7d | %-6.1f | %-7.1f | %-7.1f | %-7.1f | %-7.1f | %-7.1f | %-7.1f | %-7.1f | %-7.1f | %-7.1f\n",
+		name, h.Count(), errors, errRate, ms(h.Mean()), ms(h.StdDev()),
+		ms(h.Percentile(50)), ms(h.Percentile(90)), ms(h.Percentile(95)),
+		ms(h.Percentile(99)), ms(h.Percentile(99.9)), ms(h.Max()), stats.throughput())
+}
+
+// errorCodes errors 의 상태 코드를 정렬해서 돌려준다.
+func errorCodes(errors map[string]int64) []string {
+	codes := make([]string, 0, len(errors))
+	for code := range errors {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// newReport 실행 조건과 Triton 서버 정보를 채운 보고서. 서버에 접속하지 못해도 서버 정보만 빠진 보고서를 만든다.
+func newReport(a *aggregator, cfg *config.Config) *report.Report {
+	meta := report.Metadata{
+		RunName:      a.name,
+		ServerURL:    cfg.Server.URL,
+		ModelName:    a.model,
+		ModelVersion: a.version,
+	}
+	if conn, err := cfg.Dial(); err != nil {
+		log.Printf("Couldn't fetch server metadata: %v", err)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.Timeout)
+		if err := meta.FetchServerInfo(ctx, triton.NewGRPCInferenceServiceClient(conn)); err != nil {
+			log.Printf("Couldn't fetch server metadata: %v", err)
+		}
+		cancel()
+		conn.Close()
+	}
+	return a.report(meta)
+}
+
+// saveReport r 을 dir 에 파일로 쓰고 dbPath 의 데이터베이스에 보관한 뒤 쓴 파일 경로와 보관한 ID 를 돌려준다.
+// dir 이나 dbPath 가 비었으면 그쪽은 건너뛴다. 보관하지 못했으면 ID 는 비어 있다.
+func saveReport(r *report.Report, dir, dbPath string) (paths []string, id string) {
+	if dir != "" {
+		var err error
+		if paths, err = r.WriteFiles(dir); err != nil {
+			log.Printf("Couldn't write reports: %v", err)
+		}
+		for _, path := range paths {
+			fmt.Printf("Report written to %s\n", path)
+		}
+	}
+	if dbPath != "" {
+		var err error
+		if id, err = saveResult(r, dbPath); err != nil {
+			log.Printf("Couldn't save results: %v", err)
+		} else {
+			fmt.Printf("Results saved to %s as %s\n", dbPath, id)
+		}
+	}
+	return paths, id
+}
+
+// saveResult 데이터베이스는 results 도구가 읽을 수 있도록 저장하는 동안만 연다.
+func saveResult(r *report.Report, dbPath string) (string, error) {
+	db, err := store.Open(dbPath, dbTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+	return db.Save(r)
+}
+
+func main() {
+	cfg := config.MustLoad(config.ServerFlags, config.ModelFlags, config.LoadFlags, config.AggregatorFlags)
+	m := newRuns(cfg)
+	m.routes(http.DefaultServeMux)
+
+	// TOTAL_REQUESTS 나 COLLECT_DURATION 이 있으면 실행 하나를 바로 시작하고, 끝나면 종료한다.
+	// 없으면 POST /runs 로 실행을 만들고 시작할 때까지 기다리며 계속 떠 있다.
+	var single *aggregator
+	if cfg.Aggregator.TotalRequests > 0 || cfg.Aggregator.Duration > 0 {
+		a, err := m.create(runSpec{Name: "default", TotalRequests: cfg.Aggregator.TotalRequests})
+		if err != nil {
+			log.Fatal(err)
+		}
+		a.duration = cfg.Aggregator.Duration
+		if single, err = m.start(a.name); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if cfg.Aggregator.LiveInterval > 0 {
+		go m.watchLive(cfg.Aggregator.LiveInterval)
+	}
+
+	fmt.Printf("Starting latency collection server on %s...\n", cfg.Aggregator.Listen)
+	go func() {
+		log.Fatal(http.ListenAndServe(cfg.Aggregator.Listen, nil))
+	}()
+
+	if single == nil {
+		fmt.Println("Waiting for runs: POST /runs to create one.")
+		select {}
+	}
+	<-single.reports
+	fmt.Println("Server has finished collecting data and displayed results.")
+}
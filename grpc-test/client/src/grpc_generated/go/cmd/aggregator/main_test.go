@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/collector"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+)
+
+func post(t *testing.T, url string, data LatencyData) {
+	t.Helper()
+	body, _ := json.Marshal(data)
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST %v: %s", data, resp.Status)
+	}
+}
+
+func TestRecordLatency(t *testing.T) {
+	a := newAggregator(200)
+	a.window = 500 * time.Millisecond
+	clock := time.Unix(0, 0)
+	a.now = func() time.Time {
+		clock = clock.Add(10 * time.Millisecond)
+		return clock
+	}
+	server := httptest.NewServer(http.HandlerFunc(a.recordLatency))
+	defer server.Close()
+
+	for i := 1; i <= 100; i++ {
+		post(t, server.URL, LatencyData{ClientID: "client-1", Latency: time.Duration(i) * time.Millisecond})
+		post(t, server.URL, LatencyData{ClientID: "client-2", Latency: time.Duration(100+i) * time.Millisecond})
+	}
+	select {
+	case <-a.done:
+	default:
+		t.Fatal("done is still open after TOTAL_REQUESTS records")
+	}
+
+	total := a.total()
+	if got := total.hist.Count(); got != 200 {
+		t.Errorf("total count = %d, want 200", got)
+	}
+	if p := total.hist.Percentile(50); p < 99*time.Millisecond || p > 101*time.Millisecond {
+		t.Errorf("total p50 = %v, want about 100ms", p)
+	}
+	// 199 번 사이에 10ms 씩: 200 / 1.99s
+	if rps := total.throughput(); rps < 100 || rps > 101 {
+		t.Errorf("total throughput = %v, want about 100.5", rps)
+	}
+
+	// 10ms 씩 200 번: 0.5s 구간 4 개에 50 개씩
+	r := a.report(report.Metadata{ModelName: "vitpose_ensemble"})
+	if len(r.Windows) != 4 || r.Windows[3].Count != 50 || r.Windows[3].Throughput != 100 {
+		t.Errorf("windows = %+v, want 4 windows of 50 records", r.Windows)
+	}
+	if len(r.Clients) != 2 || r.Clients[0].Name != "client-1" || r.Total.Count != 200 {
+		t.Errorf("clients = %+v, total = %+v", r.Clients, r.Total)
+	}
+	if r.Metadata.StartedAt.IsZero() || r.Metadata.FinishedAt.Sub(r.Metadata.StartedAt) != 1990*time.Millisecond {
+		t.Errorf("metadata = %+v, want the first and last record times", r.Metadata)
+	}
+	// client-2 는 client-1 보다 100ms 느리므로 히트맵에서 구간마다 더 진하다.
+	if len(r.ClientWindows) != 2 || len(r.ClientWindows[1]) != 4 || r.ClientWindows[1][0].Count != 25 ||
+		r.ClientWindows[1][0].P50 <= r.ClientWindows[0][0].P99 {
+		t.Errorf("client windows = %+v", r.ClientWindows)
+	}
+	if len(r.Samples) != 200 || r.Samples[0].Offset != 0 || r.Samples[199].Offset != 1990*time.Millisecond {
+		t.Errorf("%d samples, want all 200 in time order", len(r.Samples))
+	}
+
+	var out bytes.Buffer
+	a.showResults(&out)
+	for _, want := range []string{"client-1", "client-2", "ALL", "P99.9"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("results do not mention %s:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRecordErrors(t *testing.T) {
+	a := newAggregator(6)
+	server := httptest.NewServer(http.HandlerFunc(a.recordLatency))
+	defer server.Close()
+
+	post(t, server.URL, LatencyData{ClientID: "client-1", Latency: 10 * time.Millisecond})
+	post(t, server.URL, LatencyData{ClientID: "client-1", Error: "Unavailable"})
+	post(t, server.URL, LatencyData{ClientID: "client-1", Error: "DeadlineExceeded"})
+	post(t, server.URL, LatencyData{ClientID: "client-2", Error: "Unavailable"})
+	post(t, server.URL, LatencyData{ClientID: "client-2", Error: "Unavailable"})
+	select {
+	case <-a.done:
+		t.Fatal("done closed before TOTAL_REQUESTS attempts")
+	default:
+	}
+	// 실패도 요청 수에 들어가므로 성공이 하나뿐이어도 끝난다.
+	post(t, server.URL, LatencyData{ClientID: "client-2", Error: "ResourceExhausted"})
+	select {
+	case <-a.done:
+	default:
+		t.Fatal("done is still open after TOTAL_REQUESTS attempts")
+	}
+
+	r := a.report(report.Metadata{})
+	if r.Total.Count != 1 || r.Total.Errors != 5 || r.Total.ErrorCodes["Unavailable"] != 3 {
+		t.Errorf("total = %+v, want 1 success and 5 errors", r.Total)
+	}
+	c1, c2 := r.Clients[0], r.Clients[1]
+	if c1.Errors != 2 || c1.ErrorCodes["DeadlineExceeded"] != 1 || c1.ErrorRate() < 0.66 || c1.ErrorRate() > 0.67 {
+		t.Errorf("client-1 = %+v, want 2 of 3 requests failed", c1)
+	}
+	if c2.Count != 0 || c2.Errors != 3 || c2.ErrorRate() != 1 {
+		t.Errorf("client-2 = %+v, want only errors", c2)
+	}
+	if len(r.Windows) != 1 || r.Windows[0].Errors != 5 {
+		t.Errorf("windows = %+v, want the errors in the first window", r.Windows)
+	}
+
+	var out bytes.Buffer
+	a.showResults(&out)
+	for _, want := range []string{"Errors by code:", "client-2   | ResourceExhausted=1, Unavailable=2", "ALL        | DeadlineExceeded=1, ResourceExhausted=1, Unavailable=3"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("results do not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestReservoir(t *testing.T) {
+	r := reservoir{max: 100}
+	for i := 0; i < 10000; i++ {
+		r.add(report.Sample{Offset: time.Duration(i)})
+	}
+	samples := r.sorted()
+	if len(samples) != 100 || r.seen != 10000 {
+		t.Fatalf("%d samples of %d, want 100 of 10000", len(samples), r.seen)
+	}
+	// 고르게 뽑았다면 앞 절반과 뒤 절반에서 비슷하게 나온다.
+	early := 0
+	for _, s := range samples {
+		if s.Offset < 5000 {
+			early++
+		}
+	}
+	if early < 25 || early > 75 {
+		t.Errorf("%d of 100 samples from the first half", early)
+	}
+}
+
+func TestCollectDuration(t *testing.T) {
+	a := newAggregator(0)
+	a.duration = 50 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(a.recordLatency))
+	defer server.Close()
+
+	post(t, server.URL, LatencyData{ClientID: "client-1", Error: "Unavailable"})
+	select {
+	case <-a.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("done is still open after the collection duration")
+	}
+	// 끝난 실행은 기록을 받지 않는다.
+	body, _ := json.Marshal(LatencyData{ClientID: "client-1", Latency: time.Millisecond})
+	resp, err := http.Post(server.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict || a.current != 1 {
+		t.Errorf("record after the run finished: status %s, %d records, want 409 and 1", resp.Status, a.current)
+	}
+}
+
+func TestRecordBatch(t *testing.T) {
+	a := newAggregator(5)
+	server := httptest.NewServer(http.HandlerFunc(a.recordBatch))
+	defer server.Close()
+
+	s := collector.NewSender(server.URL, "client-1")
+	s.Model, s.Version = "vitpose", "1"
+	s.Add(collector.Record{Latency: 10 * time.Millisecond})
+	s.Add(collector.Record{Latency: 20 * time.Millisecond})
+	s.Add(collector.Record{Error: "DeadlineExceeded"})
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 같은 배치를 다시 보내도 한 번만 센다.
+	batch := collector.Batch{ClientID: "client-2", Session: "s", Seq: 1, Records: []collector.Record{{Latency: time.Millisecond}}}
+	for i := 0; i < 2; i++ {
+		body, _ := json.Marshal(batch)
+		resp, err := http.Post(server.URL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("POST batch: %s", resp.Status)
+		}
+	}
+
+	if a.current != 4 {
+		t.Errorf("recorded %d requests, want 4", a.current)
+	}
+	c1 := a.clients["client-1"]
+	if c1.hist.Count() != 2 || c1.errors["DeadlineExceeded"] != 1 || c1.model != "vitpose" {
+		t.Errorf("client-1 = %+v", c1)
+	}
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"client_id": "client-3"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("batch without seq: status = %s, want 400", resp.Status)
+	}
+}
+
+func TestRecordBatchClientTime(t *testing.T) {
+	a := newAggregator(0)
+	a.window = 10 * time.Second
+	start := time.Unix(1000, 0)
+	now := start
+	a.now = func() time.Time { return now }
+	server := httptest.NewServer(http.HandlerFunc(a.recordBatch))
+	defer server.Close()
+	send := func(batch collector.Batch) {
+		body, _ := json.Marshal(batch)
+		resp, err := http.Post(server.URL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	// 실행 시작은 첫 배치의 기록 시각이 아니라 집계 서버가 받은 시각이다.
+	send(collector.Batch{ClientID: "client-1", Session: "s", Seq: 1, Records: []collector.Record{
+		{Latency: time.Millisecond, At: start.Add(-time.Hour)},
+	}})
+	if !a.started.Equal(start) {
+		t.Errorf("started = %v, want the arrival time %v", a.started, start)
+	}
+
+	// 배치는 25 초에 한꺼번에 도착하지만 기록은 클라이언트에서 끝난 시각의 구간에 들어간다.
+	now = start.Add(25 * time.Second)
+	send(collector.Batch{ClientID: "client-1", Session: "s", Seq: 2, Records: []collector.Record{
+		{Latency: time.Millisecond, At: start.Add(2 * time.Second)},
+		{Latency: time.Millisecond, At: start.Add(12 * time.Second)},
+		{Latency: time.Millisecond, At: start.Add(-time.Second)},    // 실행 시작보다 이르면 첫 구간
+		{Latency: time.Millisecond, At: start.Add(time.Hour)},       // 받은 시각보다 늦으면 받은 시각
+		{Latency: time.Millisecond, At: start.Add(-24 * time.Hour)}, // 하루 늦은 시계는 받은 시각
+		{Latency: time.Millisecond},                                 // 시각이 없으면 받은 시각
+	}})
+
+	var counts []int64
+	for _, w := range a.windows {
+		counts = append(counts, w.hist.Count())
+	}
+	if want := []int64{3, 1, 3}; !slices.Equal(counts, want) {
+		t.Errorf("window counts = %v, want %v", counts, want)
+	}
+	var offsets []time.Duration
+	for _, s := range a.samples.sorted() {
+		offsets = append(offsets, s.Offset)
+	}
+	if want := []time.Duration{0, 0, 2 * time.Second, 12 * time.Second, 25 * time.Second, 25 * time.Second, 25 * time.Second}; !slices.Equal(offsets, want) {
+		t.Errorf("sample offsets = %v, want %v", offsets, want)
+	}
+}
+
+func TestWindowLimit(t *testing.T) {
+	a := newAggregator(0)
+	a.window = time.Second
+	start := time.Unix(1000, 0)
+	// 1 초 구간으로 1000 초를 기록하면 120, 240, 480, 960 초에 구간을 합쳐 16 초 구간 63 개가 된다.
+	const seconds = 1000
+	for s := 0; s < seconds; s++ {
+		now := start.Add(time.Duration(s) * time.Second)
+		a.record(LatencyData{ClientID: "client-1", Latency: time.Millisecond, At: now}, now)
+		if s%2 == 0 {
+			a.record(LatencyData{ClientID: "client-2", Error: "Unavailable", At: now}, now)
+		}
+	}
+	if a.window != 16*time.Second || len(a.windows) != 63 {
+		t.Fatalf("%d windows of %v, want 63 windows of 16s", len(a.windows), a.window)
+	}
+	if got := len(a.clients["client-1"].windows); got != len(a.windows) {
+		t.Errorf("client-1 has %d windows, want %d", got, len(a.windows))
+	}
+	var requests, errors int64
+	for i, w := range a.windows {
+		requests += w.hist.Count()
+		errors += w.errorCount()
+		if i < len(a.windows)-1 && w.hist.Count() != 16 {
+			t.Errorf("window %d has %d requests, want 16", i, w.hist.Count())
+		}
+	}
+	if requests != seconds || errors != seconds/2 {
+		t.Errorf("windows hold %d requests and %d errors, want %d and %d", requests, errors, seconds, seconds/2)
+	}
+	if got := a.clients["client-2"].windows[0].errorCount(); got != 8 {
+		t.Errorf("client-2 window 0 has %d errors, want 8", got)
+	}
+}
+
+func TestReportLoadConditions(t *testing.T) {
+	a := newAggregator(0)
+	now := time.Unix(1000, 0)
+	record := func(client string, batchSize, concurrency int, mode string) {
+		a.record(LatencyData{ClientID: client, Latency: time.Millisecond, BatchSize: batchSize, Concurrency: concurrency, LoadMode: mode}, now)
+	}
+	// 집계 서버의 설정이 아니라 클라이언트가 보낸 조건을 쓰고, 동시 사용자 수는 클라이언트의 합이다.
+	record("client-1", 4, 2, "closed")
+	record("client-2", 4, 3, "closed")
+	record("client-3", 0, 0, "") // 조건을 보내지 않는 클라이언트
+	meta := a.report(report.Metadata{}).Metadata
+	if meta.BatchSize != 4 || meta.Concurrency != 5 || meta.LoadMode != "closed" {
+		t.Errorf("metadata = batch %d, concurrency %d, mode %q, want 4, 5, closed", meta.BatchSize, meta.Concurrency, meta.LoadMode)
+	}
+
+	// 클라이언트마다 배치 크기나 부하 방식이 다르면 하나로 적지 않는다.
+	record("client-4", 8, 0, "open")
+	meta = a.report(report.Metadata{}).Metadata
+	if meta.BatchSize != 0 || meta.Concurrency != 5 || meta.LoadMode != "" {
+		t.Errorf("metadata = batch %d, concurrency %d, mode %q, want 0, 5, empty", meta.BatchSize, meta.Concurrency, meta.LoadMode)
+	}
+}
+
+func TestRecordBatchAcrossTotal(t *testing.T) {
+	a := newAggregator(3)
+	server := httptest.NewServer(http.HandlerFunc(a.recordBatch))
+	defer server.Close()
+
+	// 5 개 중 3 번째 기록에서 실행이 끝나므로 나머지 2 개는 버린다.
+	batch := collector.Batch{ClientID: "client-1", Session: "s", Seq: 1}
+	for i := 0; i < 5; i++ {
+		batch.Records = append(batch.Records, collector.Record{Latency: time.Duration(i+1) * time.Millisecond})
+	}
+	body, _ := json.Marshal(batch)
+	resp, err := http.Post(server.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(msg), "dropped 2 records") {
+		t.Errorf("POST batch: %s %q, want 200 and 2 dropped records", resp.Status, msg)
+	}
+	select {
+	case <-a.done:
+	default:
+		t.Fatal("run is still open after totalRequests records")
+	}
+	if a.current != 3 || a.clients["client-1"].hist.Count() != 3 {
+		t.Errorf("recorded %d requests (%d in client-1), want 3", a.current, a.clients["client-1"].hist.Count())
+	}
+	if got := a.clients["client-1"].hist.Max(); got != 3*time.Millisecond {
+		t.Errorf("max latency = %v, want the first 3 records only", got)
+	}
+}
+
+func TestRecordLatencyBadPayload(t *testing.T) {
+	a := newAggregator(1)
+	server := httptest.NewServer(http.HandlerFunc(a.recordLatency))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader("{"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %s, want 400", resp.Status)
+	}
+	if len(a.clients) != 0 {
+		t.Errorf("bad payload was recorded: %v", a.clients)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metricBuckets /metrics 히스토그램의 le 경계 (초). 수백 ms 대의 배치 추론에 맞췄다.
+var metricBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.15, 0.2, 0.3, 0.5, 0.75, 1, 2.5, 5, 10}
+
+// serveMetrics 클라이언트가 관측한 레이턴시를 Prometheus 텍스트 형식으로 내보내는 핸들러.
+// 레이블은 client_id, model, version 이며 Triton 의 nv_inference_* 지표와 같은 대시보드에 쓸 수 있다.
+func (a *aggregator) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	a.writeMetrics(w)
+}
+
+func (a *aggregator) writeMetrics(w io.Writer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ids := a.clientIDs()
+
+	const latency = "vitpose_client_request_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Client-observed inference latency.\n# TYPE %s histogram\n", latency, latency)
+	for _, id := range ids {
+		stats := a.clients[id]
+		h := stats.hist
+		labels := stats.labels(id)
+		for _, le := range metricBuckets {
+			n := h.CountAtOrBelow(time.Duration(le * float64(time.Second)))
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", latency, labels, formatFloat(le), n)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", latency, labels, h.Count())
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", latency, labels, formatFloat(h.Sum().Seconds()))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", latency, labels, h.Count())
+	}
+
+	const requests = "vitpose_client_requests_total"
+	fmt.Fprintf(w, "# HELP %s Inference requests reported by load clients, including failures.\n# TYPE %s counter\n", requests, requests)
+	for _, id := range ids {
+		stats := a.clients[id]
+		fmt.Fprintf(w, "%s{%s} %d\n", requests, stats.labels(id), stats.hist.Count()+stats.errorCount())
+	}
+
+	const errors = "vitpose_client_errors_total"
+	fmt.Fprintf(w, "# HELP %s Failed inference requests reported by load clients.\n# TYPE %s counter\n", errors, errors)
+	for _, id := range ids {
+		stats := a.clients[id]
+		for _, code := range errorCodes(stats.errors) {
+			fmt.Fprintf(w, "%s{%s,code=\"%s\"} %d\n", errors, stats.labels(id), escapeLabel(code), stats.errors[code])
+		}
+	}
+
+	const inFlight = "vitpose_client_in_flight_requests"
+	fmt.Fprintf(w, "# HELP %s Requests in flight on the load client at its last report.\n# TYPE %s gauge\n", inFlight, inFlight)
+	for _, id := range ids {
+		stats := a.clients[id]
+		fmt.Fprintf(w, "%s{%s} %d\n", inFlight, stats.labels(id), stats.inFlight)
+	}
+
+	const clients = "vitpose_aggregator_clients"
+	fmt.Fprintf(w, "# HELP %s Load clients that have reported to the aggregator.\n# TYPE %s gauge\n", clients, clients)
+	fmt.Fprintf(w, "%s %d\n", clients, len(ids))
+}
+
+func (s *clientStats) labels(id string) string {
+	return fmt.Sprintf("client_id=\"%s\",model=\"%s\",version=\"%s\"", escapeLabel(id), escapeLabel(s.model), escapeLabel(s.version))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
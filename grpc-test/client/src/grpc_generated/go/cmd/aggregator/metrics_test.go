@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	a := newAggregator(100)
+	a.model = "vitpose_ensemble"
+	record := httptest.NewServer(http.HandlerFunc(a.recordLatency))
+	defer record.Close()
+	metrics := httptest.NewServer(http.HandlerFunc(a.serveMetrics))
+	defer metrics.Close()
+
+	for _, latency := range []time.Duration{4 * time.Millisecond, 40 * time.Millisecond, 400 * time.Millisecond} {
+		post(t, record.URL, LatencyData{ClientID: "client-1", Latency: latency, Model: "vitpose", Version: "1", InFlight: 3})
+	}
+	post(t, record.URL, LatencyData{ClientID: "client-1", Error: "Unavailable", Model: "vitpose", Version: "1"})
+	post(t, record.URL, LatencyData{ClientID: `client-"2"`, Latency: 20 * time.Second})
+
+	resp, err := http.Get(metrics.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	out := string(body)
+
+	for _, want := range []string{
+		"# TYPE vitpose_client_request_duration_seconds histogram",
+		`vitpose_client_request_duration_seconds_bucket{client_id="client-1",model="vitpose",version="1",le="0.005"} 1`,
+		`vitpose_client_request_duration_seconds_bucket{client_id="client-1",model="vitpose",version="1",le="0.05"} 2`,
+		`vitpose_client_request_duration_seconds_bucket{client_id="client-1",model="vitpose",version="1",le="+Inf"} 3`,
+		`vitpose_client_request_duration_seconds_sum{client_id="client-1",model="vitpose",version="1"} 0.444`,
+		`vitpose_client_request_duration_seconds_bucket{client_id="client-\"2\"",model="vitpose_ensemble",version="",le="10"} 0`,
+		`vitpose_client_requests_total{client_id="client-1",model="vitpose",version="1"} 4`,
+		`vitpose_client_errors_total{client_id="client-1",model="vitpose",version="1",code="Unavailable"} 1`,
+		`vitpose_client_in_flight_requests{client_id="client-1",model="vitpose",version="1"} 0`,
+		"vitpose_aggregator_clients 2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("/metrics does not contain\n%s\n\n%s", want, out)
+		}
+	}
+}
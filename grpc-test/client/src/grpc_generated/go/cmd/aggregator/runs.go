@@ -0,0 +1,537 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/collector"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/store"
+)
+
+var (
+	errRunNotFound = errors.New("run not found")
+	errRunState    = errors.New("run is in the wrong state")
+	errRunExists   = errors.New("run already exists")
+	errNoDB        = errors.New("no results database: start the aggregator with -db")
+	errNoResults   = errors.New("no results have been saved yet")
+)
+
+// runName 실행 이름은 보고서 파일 이름에도 쓰이므로 경로에 안전한 문자만 받는다.
+var runName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// runSpec POST /runs 로 만드는 실행의 설정. 비어 있는 값은 집계 서버 설정을 따른다.
+type runSpec struct {
+	// Name 이 없으면 만든 시각으로 이름을 붙인다.
+	Name          string `json:"name"`
+	TotalRequests int    `json:"total_requests,omitempty"`
+	// Duration, Window 는 "60s" 처럼 쓴다. Duration 이 없고 TotalRequests 도 없으면 stop 할 때까지 받는다.
+	Duration string `json:"duration,omitempty"`
+	Window   string `json:"window,omitempty"`
+	Model    string `json:"model,omitempty"`
+	Version  string `json:"version,omitempty"`
+	// Start 가 true 이면 만들자마자 시작한다.
+	Start bool `json:"start,omitempty"`
+}
+
+// runStatus GET /runs 와 /runs/{name} 이 돌려주는 실행 상태
+type runStatus struct {
+	Name          string     `json:"name"`
+	State         string     `json:"state"`
+	Model         string     `json:"model,omitempty"`
+	Version       string     `json:"version,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	FirstRecordAt *time.Time `json:"first_record_at,omitempty"`
+	StoppedAt     *time.Time `json:"stopped_at,omitempty"`
+	Records       int        `json:"records"`
+	TotalRequests int        `json:"total_requests,omitempty"`
+	Duration      string     `json:"duration,omitempty"`
+	Clients       []string   `json:"clients"`
+	Reports       []string   `json:"reports,omitempty"`
+}
+
+func (a *aggregator) status() runStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := runStatus{
+		Name:          a.name,
+		State:         a.state,
+		Model:         a.model,
+		Version:       a.version,
+		CreatedAt:     a.created,
+		Records:       a.current,
+		TotalRequests: a.totalRequests,
+		Clients:       a.clientIDs(),
+		Reports:       a.reportPaths,
+	}
+	if a.duration > 0 {
+		s.Duration = a.duration.String()
+	}
+	if !a.started.IsZero() {
+		started := a.started
+		s.FirstRecordAt = &started
+	}
+	if !a.stopped.IsZero() {
+		stopped := a.stopped
+		s.StoppedAt = &stopped
+	}
+	return s
+}
+
+// runs 이름 붙은 실행들. 집계 서버 하나를 띄워 둔 채로 여러 벤치마크를 차례로 또는 동시에 받는다.
+// 클라이언트는 기록에 run_id 를 붙여 실행을 고르고, run_id 가 없으면 가장 최근에 시작한 실행에 넣는다.
+type runs struct {
+	mu      sync.Mutex
+	cfg     *config.Config
+	runs    map[string]*aggregator
+	current *aggregator
+
+	// dbPath 가 있으면 끝난 실행을 이 데이터베이스에 보관하고 /results 로 조회한다.
+	dbPath string
+
+	// finished 실행이 끝나면 부르는 함수. 결과를 출력하고 보고서를 쓴 뒤 경로와 데이터베이스에 보관한 ID 를 돌려준다.
+	finished func(a *aggregator) (paths []string, resultID string)
+}
+
+func newRuns(cfg *config.Config) *runs {
+	m := &runs{cfg: cfg, dbPath: cfg.Aggregator.DBPath, runs: make(map[string]*aggregator)}
+	m.finished = func(a *aggregator) ([]string, string) {
+		fmt.Printf("Run %s finished.\n", a.name)
+		a.showResults(os.Stdout)
+		if cfg.Aggregator.ReportDir == "" && m.dbPath == "" {
+			return nil, ""
+		}
+		return saveReport(newReport(a, cfg), cfg.Aggregator.ReportDir, m.dbPath)
+	}
+	return m
+}
+
+// create spec 으로 created 상태의 실행을 만든다. spec.Start 는 여기서 보지 않는다.
+func (m *runs) create(spec runSpec) (*aggregator, error) {
+	a := newAggregator(spec.TotalRequests)
+	a.state = runCreated
+	a.window = m.cfg.Aggregator.Window
+	a.model, a.version = m.cfg.Model.Name, m.cfg.Model.Version
+	if spec.TotalRequests < 0 {
+		return nil, fmt.Errorf("invalid total_requests %d", spec.TotalRequests)
+	}
+	if spec.Duration != "" {
+		d, err := time.ParseDuration(spec.Duration)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid duration %q", spec.Duration)
+		}
+		a.duration = d
+	}
+	if spec.Window != "" {
+		d, err := time.ParseDuration(spec.Window)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid window %q", spec.Window)
+		}
+		a.window = d
+	}
+	if spec.Model != "" {
+		a.model, a.version = spec.Model, spec.Version
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if spec.Name == "" {
+		spec.Name = a.created.Format("run-20060102-150405")
+		for i := 2; m.runs[spec.Name] != nil; i++ {
+			spec.Name = fmt.Sprintf("%s-%d", a.created.Format("run-20060102-150405"), i)
+		}
+	}
+	if !runName.MatchString(spec.Name) {
+		return nil, fmt.Errorf("invalid run name %q: use letters, digits, '.', '_' and '-'", spec.Name)
+	}
+	if m.runs[spec.Name] != nil {
+		return nil, fmt.Errorf("%w: %s", errRunExists, spec.Name)
+	}
+	a.name = spec.Name
+	m.runs[spec.Name] = a
+	return a, nil
+}
+
+// get 이름이 name 인 실행
+func (m *runs) get(name string) (*aggregator, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a := m.runs[name]
+	if a == nil {
+		return nil, fmt.Errorf("%w: %s", errRunNotFound, name)
+	}
+	return a, nil
+}
+
+// lookup 기록을 넣을 실행. runID 가 없으면 가장 최근에 시작한 실행이다.
+func (m *runs) lookup(runID string) (*aggregator, error) {
+	if runID != "" {
+		return m.get(runID)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.current == nil {
+		return nil, fmt.Errorf("%w: no run has been started", errRunNotFound)
+	}
+	return m.current, nil
+}
+
+// start created 상태의 실행을 시작한다. 실행이 끝나면 finished 를 불러 결과를 남기고,
+// 데이터베이스에 보관했으면 evict 로 목록에서 뺀다.
+func (m *runs) start(name string) (*aggregator, error) {
+	a, err := m.get(name)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	if a.state != runCreated {
+		defer a.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s is %s", errRunState, name, a.state)
+	}
+	a.state = runRunning
+	a.mu.Unlock()
+
+	m.mu.Lock()
+	m.current = a
+	m.mu.Unlock()
+	go func() {
+		<-a.done
+		paths, resultID := m.finished(a)
+		a.mu.Lock()
+		a.reportPaths = paths
+		a.mu.Unlock()
+		close(a.reports)
+		if resultID != "" {
+			m.evict(a, resultID)
+		}
+	}()
+	return a, nil
+}
+
+// evict 데이터베이스에 보관한 실행을 목록에서 빼서 끝난 실행의 히스토그램이 메모리에 쌓이지 않게 한다.
+// 빠진 실행의 결과는 /results/{resultID} 로 조회한다. 그사이 reset 으로 바뀐 실행은 그대로 둔다.
+func (m *runs) evict(a *aggregator, resultID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.runs[a.name] == a {
+		delete(m.runs, a.name)
+		fmt.Printf("Run %s evicted, results at /results/%s\n", a.name, resultID)
+	}
+	if m.current == a {
+		m.current = nil
+	}
+}
+
+// stop 실행 중인 실행을 끝낸다.
+func (m *runs) stop(name string) (*aggregator, error) {
+	a, err := m.get(name)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.state != runRunning {
+		return nil, fmt.Errorf("%w: %s is %s", errRunState, name, a.state)
+	}
+	a.finish()
+	return a, nil
+}
+
+// reset 실행 중이 아닌 실행의 기록을 지우고 같은 설정의 created 상태로 되돌린다.
+func (m *runs) reset(name string) (*aggregator, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old := m.runs[name]
+	if old == nil {
+		return nil, fmt.Errorf("%w: %s", errRunNotFound, name)
+	}
+	old.mu.Lock()
+	state := old.state
+	old.mu.Unlock()
+	if state == runRunning {
+		return nil, fmt.Errorf("%w: %s is running, stop it first", errRunState, name)
+	}
+
+	a := newAggregator(old.totalRequests)
+	a.state = runCreated
+	a.name = old.name
+	a.window, a.duration = old.window, old.duration
+	a.model, a.version = old.model, old.version
+	m.runs[name] = a
+	if m.current == old {
+		m.current = nil
+	}
+	return a, nil
+}
+
+// list 만든 순서대로의 실행들
+func (m *runs) list() []*aggregator {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]*aggregator, 0, len(m.runs))
+	for _, a := range m.runs {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].created.Equal(list[j].created) {
+			return list[i].created.Before(list[j].created)
+		}
+		return list[i].name < list[j].name
+	})
+	return list
+}
+
+// routes 실행 관리 API 와 기록 수집 경로를 mux 에 등록한다.
+//
+//	POST /runs                     실행 만들기 (runSpec, "start": true 면 바로 시작)
+//	GET  /runs                     실행 목록
+//	GET  /runs/{name}              실행 상태
+//	POST /runs/{name}/start        시작
+//	POST /runs/{name}/stop         멈추고 결과 남기기
+//	POST /runs/{name}/reset        기록을 지우고 created 로 되돌리기
+//	POST /runs/{name}/clients      클라이언트 등록 ({"client_id": ...})
+//	GET  /runs/{name}/report       지금까지의 보고서 (JSON)
+//	GET  /runs/{name}/report.html  지금까지의 보고서 (차트를 넣은 HTML)
+//	GET  /runs/{name}/live         실시간 보기 (JSON)
+//	GET  /runs/{name}/metrics      Prometheus 지표
+//	GET  /results                  보관한 실행 목록 (-db 가 있을 때, ?model=&version=&batch=&since=&until=)
+//	GET  /results/{id}             보관한 실행의 보고서 (JSON)
+//
+// /record-latency, /record-batch, /live, /metrics 는 run_id 가 가리키는 실행이나 가장 최근에 시작한 실행을 쓴다.
+// -db 로 보관한 실행은 끝나면 /runs 에서 빠지고 /results 로만 조회한다.
+func (m *runs) routes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /runs", m.createRun)
+	mux.HandleFunc("GET /runs", m.listRuns)
+	mux.HandleFunc("GET /runs/{name}", m.runHandler(func(w http.ResponseWriter, r *http.Request, a *aggregator) {
+		writeJSON(w, http.StatusOK, a.status())
+	}))
+	mux.HandleFunc("POST /runs/{name}/start", m.action(m.start))
+	mux.HandleFunc("POST /runs/{name}/stop", m.action(m.stop))
+	mux.HandleFunc("POST /runs/{name}/reset", m.action(m.reset))
+	mux.HandleFunc("POST /runs/{name}/clients", m.runHandler(registerClient))
+	mux.HandleFunc("GET /runs/{name}/report", m.runHandler(func(w http.ResponseWriter, r *http.Request, a *aggregator) {
+		w.Header().Set("Content-Type", "application/json")
+		a.report(report.Metadata{RunName: a.name, ModelName: a.model, ModelVersion: a.version}).WriteJSON(w)
+	}))
+	mux.HandleFunc("GET /runs/{name}/report.html", m.runHandler(func(w http.ResponseWriter, r *http.Request, a *aggregator) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		a.report(report.Metadata{RunName: a.name, ModelName: a.model, ModelVersion: a.version}).WriteHTML(w)
+	}))
+	mux.HandleFunc("GET /runs/{name}/live", m.runHandler(func(w http.ResponseWriter, r *http.Request, a *aggregator) {
+		a.serveLive(w, r)
+	}))
+	mux.HandleFunc("GET /runs/{name}/metrics", m.runHandler(func(w http.ResponseWriter, r *http.Request, a *aggregator) {
+		a.serveMetrics(w, r)
+	}))
+
+	mux.HandleFunc("GET /results", m.listResults)
+	mux.HandleFunc("GET /results/{id}", m.getResult)
+
+	mux.HandleFunc("/record-latency", m.recordLatency)
+	mux.HandleFunc(collector.BatchPath, m.recordBatch)
+	mux.HandleFunc("/live", func(w http.ResponseWriter, r *http.Request) {
+		a, err := m.lookup("")
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		a.serveLive(w, r)
+	})
+	// 실행이 없어도 Prometheus 수집이 실패하지 않도록 빈 응답을 돌려준다.
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		if a, err := m.lookup(""); err == nil {
+			a.serveMetrics(w, r)
+		}
+	})
+}
+
+func (m *runs) createRun(w http.ResponseWriter, r *http.Request) {
+	var spec runSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	a, err := m.create(spec)
+	if err == nil && spec.Start {
+		a, err = m.start(a.name)
+	}
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, a.status())
+}
+
+func (m *runs) listRuns(w http.ResponseWriter, r *http.Request) {
+	statuses := []runStatus{}
+	for _, a := range m.list() {
+		statuses = append(statuses, a.status())
+	}
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// runHandler 경로의 {name} 실행을 찾아 h 에 넘기는 핸들러
+func (m *runs) runHandler(h func(w http.ResponseWriter, r *http.Request, a *aggregator)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, err := m.get(r.PathValue("name"))
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		h(w, r, a)
+	}
+}
+
+// action start, stop, reset 을 부르고 바뀐 상태를 돌려주는 핸들러
+func (m *runs) action(f func(name string) (*aggregator, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, err := f(r.PathValue("name"))
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, a.status())
+	}
+}
+
+// registerClient 클라이언트를 실행에 등록한다. 등록한 클라이언트는 기록이 없어도 결과에 나온다.
+func registerClient(w http.ResponseWriter, r *http.Request, a *aggregator) {
+	var body struct {
+		ClientID string `json:"client_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.ClientID == "" {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	a.mu.Lock()
+	if a.state == runStopped {
+		a.mu.Unlock()
+		http.Error(w, fmt.Sprintf("Run %s is %s", a.name, a.state), http.StatusConflict)
+		return
+	}
+	a.client(body.ClientID)
+	a.mu.Unlock()
+	fmt.Fprintf(w, "Client %s registered for run: %s", body.ClientID, a.name)
+}
+
+// recordLatency 기록 하나를 run_id 의 실행에 넣는 핸들러
+func (m *runs) recordLatency(w http.ResponseWriter, r *http.Request) {
+	var data LatencyData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	a, err := m.lookup(data.RunID)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	a.recordData(w, data)
+}
+
+// recordBatch 배치를 run_id 의 실행에 넣는 핸들러
+func (m *runs) recordBatch(w http.ResponseWriter, r *http.Request) {
+	batch, err := collector.ReadBatch(r)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	a, err := m.lookup(batch.RunID)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	a.recordBatchData(w, batch)
+}
+
+// listResults 보관한 실행 가운데 쿼리 조건에 맞는 것을 오래된 것부터 돌려준다.
+func (m *runs) listResults(w http.ResponseWriter, r *http.Request) {
+	f, err := store.FilterFromQuery(r.URL.Query())
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	var entries []store.Entry
+	err = m.readDB(func(db *store.Store) (err error) {
+		entries, err = db.List(f)
+		return err
+	})
+	if err != nil {
+		dbError(w, err)
+		return
+	}
+	if entries == nil {
+		entries = []store.Entry{}
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// getResult 보관한 실행 하나의 보고서를 히스토그램까지 돌려준다.
+func (m *runs) getResult(w http.ResponseWriter, r *http.Request) {
+	var rep *report.Report
+	err := m.readDB(func(db *store.Store) (err error) {
+		rep, err = db.Get(r.PathValue("id"))
+		return err
+	})
+	if err != nil {
+		dbError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	rep.WriteJSON(w)
+}
+
+// readDB 데이터베이스를 읽기 전용으로 열어 f 를 부른다. 아직 저장한 실행이 없어 파일이 없으면
+// 만들지 않고 errNoResults 를 돌려준다. 파일은 끝난 실행을 보관할 때만 만든다.
+func (m *runs) readDB(f func(db *store.Store) error) error {
+	if m.dbPath == "" {
+		return errNoDB
+	}
+	db, err := store.OpenReadOnly(m.dbPath, dbTimeout)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s does not exist", errNoResults, m.dbPath)
+	}
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return f(db)
+}
+
+// httpError err 의 종류에 맞는 상태 코드로 응답한다.
+func httpError(w http.ResponseWriter, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, errRunNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, errRunState), errors.Is(err, errRunExists):
+		code = http.StatusConflict
+	}
+	http.Error(w, err.Error(), code)
+}
+
+// dbError 없는 실행이나 데이터베이스는 404, 그 밖의 데이터베이스 에러는 500 으로 응답한다.
+func dbError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	if errors.Is(err, store.ErrNotFound) || errors.Is(err, errNoDB) || errors.Is(err, errNoResults) {
+		code = http.StatusNotFound
+	}
+	http.Error(w, err.Error(), code)
+}
+
+// dbTimeout 다른 프로세스가 데이터베이스에 쓰고 있을 때 기다리는 시간
+const dbTimeout = 5 * time.Second
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
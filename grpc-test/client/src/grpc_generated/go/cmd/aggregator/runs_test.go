@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/store"
+)
+
+func newRunsServer(t *testing.T) (*runs, *httptest.Server, chan string) {
+	t.Helper()
+	m := newRuns(config.Default())
+	finished := make(chan string, 10)
+	m.finished = func(a *aggregator) ([]string, string) {
+		finished <- a.name
+		return []string{a.name + ".json"}, ""
+	}
+	mux := http.NewServeMux()
+	m.routes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return m, server, finished
+}
+
+// call method path 에 body 를 JSON 으로 보내고 상태 코드를 확인한 뒤 응답을 out 에 읽는다.
+func call(t *testing.T, method, url string, body any, wantCode int, out any) {
+	t.Helper()
+	var r io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		r = bytes.NewReader(data)
+	}
+	req, _ := http.NewRequest(method, url, r)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != wantCode {
+		t.Fatalf("%s %s: %s %s, want %d", method, url, resp.Status, data, wantCode)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s %s: %v in %s", method, url, err, data)
+		}
+	}
+}
+
+func TestRunLifecycle(t *testing.T) {
+	_, server, finished := newRunsServer(t)
+	url := server.URL
+
+	var status runStatus
+	call(t, "POST", url+"/runs", runSpec{Name: "batch-4", Model: "vitpose", Version: "1"}, http.StatusCreated, &status)
+	if status.State != runCreated || status.Model != "vitpose" {
+		t.Fatalf("created run = %+v", status)
+	}
+	call(t, "POST", url+"/runs", runSpec{Name: "batch-4"}, http.StatusConflict, nil)
+	call(t, "POST", url+"/runs", runSpec{Name: "../etc"}, http.StatusBadRequest, nil)
+
+	// 시작 전에는 등록만 되고 기록은 받지 않는다.
+	call(t, "POST", url+"/runs/batch-4/clients", map[string]string{"client_id": "client-1"}, http.StatusOK, nil)
+	call(t, "POST", url+"/record-latency", LatencyData{ClientID: "client-1", RunID: "batch-4", Latency: time.Millisecond}, http.StatusConflict, nil)
+
+	call(t, "POST", url+"/runs/batch-4/start", nil, http.StatusOK, &status)
+	if status.State != runRunning || len(status.Clients) != 1 {
+		t.Fatalf("started run = %+v", status)
+	}
+	call(t, "POST", url+"/runs/batch-4/start", nil, http.StatusConflict, nil)
+	call(t, "POST", url+"/runs/batch-4/reset", nil, http.StatusConflict, nil)
+
+	call(t, "POST", url+"/runs", runSpec{Name: "batch-8", Start: true, TotalRequests: 1}, http.StatusCreated, &status)
+	// run_id 가 없는 기록은 가장 최근에 시작한 실행 (batch-8) 에 들어간다.
+	call(t, "POST", url+"/record-latency", LatencyData{ClientID: "client-2", Latency: time.Millisecond}, http.StatusOK, nil)
+	for i := 0; i < 3; i++ {
+		call(t, "POST", url+"/record-latency", LatencyData{ClientID: "client-1", RunID: "batch-4", Latency: 10 * time.Millisecond}, http.StatusOK, nil)
+	}
+	call(t, "POST", url+"/record-latency", LatencyData{ClientID: "client-1", RunID: "nope"}, http.StatusNotFound, nil)
+
+	// batch-8 은 TOTAL_REQUESTS 1 개로 스스로 끝난다.
+	if name := <-finished; name != "batch-8" {
+		t.Errorf("finished %s first, want batch-8", name)
+	}
+	call(t, "POST", url+"/runs/batch-4/stop", nil, http.StatusOK, &status)
+	if name := <-finished; name != "batch-4" {
+		t.Errorf("finished %s, want batch-4", name)
+	}
+
+	var list []runStatus
+	call(t, "GET", url+"/runs", nil, http.StatusOK, &list)
+	if len(list) != 2 || list[0].Name != "batch-4" || list[0].Records != 3 || list[1].Records != 1 || list[0].State != runStopped {
+		t.Errorf("runs = %+v", list)
+	}
+	call(t, "GET", url+"/runs/batch-4", nil, http.StatusOK, &status)
+	if len(status.Reports) != 1 || status.StoppedAt == nil || status.FirstRecordAt == nil {
+		t.Errorf("stopped run = %+v, want report paths and times", status)
+	}
+
+	var r report.Report
+	call(t, "GET", url+"/runs/batch-4/report", nil, http.StatusOK, &r)
+	if r.Metadata.RunName != "batch-4" || r.Total.Count != 3 || len(r.Clients) != 1 {
+		t.Errorf("report = %+v", r)
+	}
+
+	call(t, "POST", url+"/runs/batch-4/reset", nil, http.StatusOK, &status)
+	if status.State != runCreated || status.Records != 0 || len(status.Clients) != 0 || status.Model != "vitpose" {
+		t.Errorf("reset run = %+v", status)
+	}
+	call(t, "GET", url+"/runs/missing", nil, http.StatusNotFound, nil)
+}
+
+func TestRunsMetricsWithoutRun(t *testing.T) {
+	_, server, _ := newRunsServer(t)
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/metrics without a run: %s, want 200", resp.Status)
+	}
+	resp, err = http.Get(server.URL + "/live")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || !strings.Contains(string(body), "no run") {
+		t.Errorf("/live without a run: %s %s, want 404", resp.Status, body)
+	}
+}
+
+func TestResults(t *testing.T) {
+	m, server, _ := newRunsServer(t)
+	call(t, "GET", server.URL+"/results", nil, http.StatusNotFound, nil)
+
+	m.dbPath = filepath.Join(t.TempDir(), "results.db")
+	// 아직 아무것도 저장하지 않았으면 404 이고, 조회만으로는 데이터베이스 파일을 만들지 않는다.
+	call(t, "GET", server.URL+"/results", nil, http.StatusNotFound, nil)
+	call(t, "GET", server.URL+"/results/missing", nil, http.StatusNotFound, nil)
+	if _, err := os.Stat(m.dbPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("GET /results created the database: %v", err)
+	}
+
+	started := time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC)
+	for _, batch := range []int{4, 8} {
+		a := newAggregator(0)
+		a.name = fmt.Sprintf("batch-%d", batch)
+		a.record(LatencyData{ClientID: "client-1", Latency: 20 * time.Millisecond}, started)
+		r := a.report(report.Metadata{RunName: a.name, StartedAt: started, ModelName: "vitpose_ensemble", BatchSize: batch})
+		if paths, id := saveReport(r, "", m.dbPath); paths != nil || id == "" {
+			t.Errorf("saveReport without a directory = %v, %q, want no files and an ID", paths, id)
+		}
+	}
+
+	var entries []store.Entry
+	call(t, "GET", server.URL+"/results?batch=8&since=2024-10-01", nil, http.StatusOK, &entries)
+	if len(entries) != 1 || entries[0].ID != "20241001T090000Z-batch-8" || entries[0].Total.Count != 1 {
+		t.Fatalf("results = %+v", entries)
+	}
+	var r report.Report
+	call(t, "GET", server.URL+"/results/"+entries[0].ID, nil, http.StatusOK, &r)
+	if r.Metadata.BatchSize != 8 || len(r.Clients) != 1 {
+		t.Errorf("result = %+v", r)
+	}
+	call(t, "GET", server.URL+"/results/missing", nil, http.StatusNotFound, nil)
+	call(t, "GET", server.URL+"/results?since=yesterday", nil, http.StatusBadRequest, nil)
+}
+
+func TestEvictPersistedRun(t *testing.T) {
+	m, server, finished := newRunsServer(t)
+	m.finished = func(a *aggregator) ([]string, string) {
+		finished <- a.name
+		return nil, "20241001T090000Z-" + a.name
+	}
+	url := server.URL
+
+	call(t, "POST", url+"/runs", runSpec{Name: "batch-4", Start: true, TotalRequests: 1}, http.StatusCreated, nil)
+	call(t, "POST", url+"/record-latency", LatencyData{ClientID: "client-1", Latency: time.Millisecond}, http.StatusOK, nil)
+	<-finished
+
+	// evict 는 finished 가 돌아오고 reports 를 닫은 뒤에 부르므로 잠시 기다린다.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := m.get("batch-4"); err != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("persisted run is still in the run list")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	call(t, "GET", url+"/runs/batch-4", nil, http.StatusNotFound, nil)
+	var list []runStatus
+	call(t, "GET", url+"/runs", nil, http.StatusOK, &list)
+	if len(list) != 0 {
+		t.Errorf("runs = %+v, want the persisted run evicted", list)
+	}
+	// 가장 최근 실행이 빠졌으므로 run_id 없는 기록은 받을 실행이 없다.
+	call(t, "POST", url+"/record-latency", LatencyData{ClientID: "client-1", Latency: time.Millisecond}, http.StatusNotFound, nil)
+	// 같은 이름으로 다시 만들 수 있다.
+	call(t, "POST", url+"/runs", runSpec{Name: "batch-4"}, http.StatusCreated, nil)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+)
+
+// maxSamples HTML 보고서의 산점도에 쓸 요청 수. 보고서 JSON 이 수백 KB 를 넘지 않게 한다.
+const maxSamples = 5000
+
+// reservoir 기록 가운데 max 개를 고르게 뽑아 둔다 (reservoir sampling). 요청 수와 상관없이 max 개만큼의 메모리를 쓴다.
+type reservoir struct {
+	max     int
+	seen    int64
+	samples []report.Sample
+}
+
+func (r *reservoir) add(s report.Sample) {
+	r.seen++
+	if len(r.samples) < r.max {
+		r.samples = append(r.samples, s)
+		return
+	}
+	if i := rand.Int64N(r.seen); i < int64(r.max) {
+		r.samples[i] = s
+	}
+}
+
+// sorted 뽑은 기록을 시간순으로 복사해 돌려준다.
+func (r *reservoir) sorted() []report.Sample {
+	samples := append([]report.Sample(nil), r.samples...)
+	slices.SortFunc(samples, func(a, b report.Sample) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+	return samples
+}
+
+// newCellStats 클라이언트별 시간 구간용. 클라이언트 수 × 구간 수만큼 생기므로 유효 숫자 1 자리 (약 3KB) 로 기록한다.
+func newCellStats() *clientStats {
+	return &clientStats{hist: histogram.New(time.Microsecond, time.Minute, 1)}
+}
+
+func (s *clientStats) cell() report.Cell {
+	return report.Cell{
+		Count:  s.hist.Count(),
+		Errors: s.errorCount(),
+		P50:    s.hist.Percentile(50),
+		P99:    s.hist.Percentile(99),
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/loadtest"
+)
+
+func main() {
+	cfg := config.MustLoad(config.ServerFlags, config.ModelFlags, config.LoadFlags)
+	if err := loadtest.Standalone(context.Background(), cfg, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/collector"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/loadtest"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/preprocess"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+	"google.golang.org/grpc/status"
+)
+
+// inFlight 처리 중인 추론 요청 수. 집계 서버의 in-flight 게이지로 보낸다.
+var inFlight atomic.Int64
+
+// runID 기록을 넣을 집계 서버의 실행 (RUN_ID). 비어 있으면 가장 최근에 시작한 실행이다.
+var runID string
+
+// loadConditions 집계 서버가 보고서의 실행 조건으로 쓰도록 결과와 함께 보내는 부하 조건
+var loadConditions struct {
+	batchSize   int
+	concurrency int
+	mode        string
+}
+
+// errInferFailed ModelInferRequest 가 실패를 이미 기록했음을 loadtest.Runner 에 알린다.
+var errInferFailed = errors.New("client: inference failed")
+
+// sender FLUSH_INTERVAL 이 있으면 결과를 모았다가 배치로 보낸다. nil 이면 결과마다 sendLatencyData 로 보낸다.
+var sender *collector.Sender
+
+// ModelInferRequest batch 로 추론을 한 번 수행하고 (nil 이면 난수 배치) 레이턴시를 집계 서버로 보낸다.
+// 커넥션은 config.LoadRetryPolicy 를 쓰므로 RETRY_INFER 가 없으면 다시 시도하지 않고, 레이턴시는 RPC 한 번의 시간이다.
+// 실패하면 gRPC 상태 코드 (gRPC 에러가 아니면 Unknown) 를 집계 서버로 보내고 -1 을 반환한다.
+func ModelInferRequest(ctx context.Context, client *vitpose.Client, batch *vitpose.Batch, batchSize int, collectorURL, clientID string) time.Duration {
+	if batch == nil {
+		batch = vitpose.RandomBatch(batchSize)
+	}
+	inFlight.Add(1)
+	start := time.Now()
+	result, err := client.Infer(ctx, batch)
+	pending := inFlight.Add(-1)
+	if err != nil {
+		code := status.Code(err)
+		log.Printf("InferRequest 처리 오류 (%s): %v", code, err)
+		recordResult(collectorURL, clientID, client, time.Since(start), pending, code.String())
+		return -1
+	}
+
+	// 레이턴시 데이터를 집계 서버로 전송
+	recordResult(collectorURL, clientID, client, result.Latency, pending, "")
+
+	return result.Latency
+}
+
+// recordResult 요청 하나의 결과를 sender 에 넣거나, sender 가 없으면 바로 집계 서버로 보낸다.
+func recordResult(url, clientID string, client *vitpose.Client, latency time.Duration, inFlight int64, code string) {
+	if sender != nil {
+		sender.Add(collector.Record{Latency: latency, Error: code})
+		return
+	}
+	sendLatencyData(url, clientID, client, latency, inFlight, code)
+}
+
+// sendLatencyData 요청 하나의 결과를 집계 서버로 보낸다. code 가 비어 있지 않으면 실패한 요청이다.
+func sendLatencyData(url, clientID string, client *vitpose.Client, latency time.Duration, inFlight int64, code string) {
+	data := map[string]interface{}{
+		"client_id":   clientID,
+		"latency":     latency,
+		"model":       client.ModelName(),
+		"version":     client.ModelVersion(),
+		"in_flight":   inFlight,
+		"batch_size":  loadConditions.batchSize,
+		"concurrency": loadConditions.concurrency,
+		"load_mode":   loadConditions.mode,
+	}
+	if code != "" {
+		data["error"] = code
+	}
+	if runID != "" {
+		data["run_id"] = runID
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Error marshaling latency data: %v", err)
+		return
+	}
+
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Printf("Error sending latency data to collector: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Non-OK response from collector: %s", resp.Status)
+	}
+}
+
+// defaultClientID CLIENT_ID 가 없을 때 쓸 클라이언트 ID. docker-compose 의 복제본은 컨테이너마다
+// 호스트 이름이 다르므로 집계 서버에서 따로 보인다. 호스트 이름을 모르면 프로세스 ID 를 쓴다.
+func defaultClientID() string {
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return fmt.Sprintf("client-%d", os.Getpid())
+}
+
+func main() {
+	cfg := config.MustLoad(config.ServerFlags, config.ModelFlags, config.LoadFlags)
+	if cfg.Load.ClientID == "" {
+		cfg.Load.ClientID = defaultClientID()
+	}
+
+	retryPolicy := cfg.LoadRetryPolicy()
+	conn, err := cfg.Dial(retryPolicy.DialOption())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := vitpose.NewClient(triton.NewGRPCInferenceServiceClient(conn), cfg.VitposeOptions()...)
+
+	var batch *vitpose.Batch
+	if cfg.Load.ImagePath != "" {
+		batch, err = preprocess.LoadBatch(cfg.Load.ImagePath, cfg.Model.BatchSize)
+		if err != nil {
+			log.Fatalf("Couldn't load image %s: %v", cfg.Load.ImagePath, err)
+		}
+	}
+
+	// 동시 사용자 수가 정해지지 않은 open-loop 와 구간별 프로필은 concurrency 를 보내지 않는다.
+	loadConditions.batchSize, loadConditions.mode = cfg.Model.BatchSize, cfg.Load.Mode
+	if cfg.Load.Mode != config.OpenLoop && len(cfg.Load.Stages) == 0 {
+		loadConditions.concurrency = cfg.Load.Concurrency
+	}
+
+	if runID = cfg.Load.RunID; runID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := collector.Register(ctx, cfg.Load.CollectorURL, runID, cfg.Load.ClientID)
+		cancel()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if cfg.Load.FlushInterval > 0 {
+		batchURL, err := collector.BatchURL(cfg.Load.CollectorURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sender = collector.NewSender(batchURL, cfg.Load.ClientID)
+		sender.RunID = runID
+		sender.Model, sender.Version = client.ModelName(), client.ModelVersion()
+		sender.InFlight = inFlight.Load
+		sender.BatchSize, sender.Concurrency, sender.LoadMode = loadConditions.batchSize, loadConditions.concurrency, loadConditions.mode
+		ctx, cancel := context.WithCancel(context.Background())
+		go sender.Run(ctx, cfg.Load.FlushInterval, func(err error) {
+			log.Printf("Error sending latency data to collector: %v", err)
+		})
+		// 테스트가 끝나면 남은 결과와 보내지 못한 배치를 마저 보낸다.
+		defer func() {
+			cancel()
+			if err := sender.Flush(context.Background()); err != nil {
+				log.Printf("Error sending latency data to collector: %v", err)
+			}
+			if n := sender.Dropped(); n > 0 {
+				log.Printf("Dropped %d results the collector rejected or couldn't keep up with", n)
+			}
+		}()
+	}
+
+	// -mode, -rps, -profile, -c 에 따라 cmd/client-all 과 같은 방식으로 부하를 주고, 요청마다 결과를 집계 서버로 보낸다.
+	if batch == nil {
+		batch = vitpose.RandomBatch(cfg.Model.BatchSize)
+	}
+	runner := &loadtest.Runner{
+		Config: cfg,
+		Client: client,
+		Batch:  batch,
+		Out:    os.Stdout,
+		Request: func(ctx context.Context) error {
+			if ModelInferRequest(ctx, client, batch, cfg.Model.BatchSize, cfg.Load.CollectorURL, cfg.Load.ClientID) < 0 {
+				return errInferFailed
+			}
+			return nil
+		},
+	}
+	if err := runner.Run(context.Background()); err != nil {
+		log.Print(err)
+	}
+	fmt.Println("테스트가 완료되었습니다.")
+	fmt.Println("재시도:")
+	retryPolicy.Metrics.WriteText(os.Stdout)
+}
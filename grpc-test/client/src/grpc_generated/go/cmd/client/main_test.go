@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+	"google.golang.org/grpc/codes"
+)
+
+func TestModelInferRequest(t *testing.T) {
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository("../../../../../../../pose_model_zoo"); err != nil {
+		t.Fatal(err)
+	}
+	server.Start()
+	defer server.Close()
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := vitpose.NewClient(triton.NewGRPCInferenceServiceClient(conn))
+
+	var mu sync.Mutex
+	var records []LatencyRecord
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var record LatencyRecord
+		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		records = append(records, record)
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	if latency := ModelInferRequest(context.Background(), client, nil, 2, collector.URL, "client-1"); latency < 0 {
+		t.Fatalf("ModelInferRequest failed")
+	}
+	server.SetErrorRate(1, codes.Unavailable)
+	if latency := ModelInferRequest(context.Background(), client, nil, 2, collector.URL, "client-1"); latency != -1 {
+		t.Fatalf("ModelInferRequest = %v, want -1 on error", latency)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(records) != 2 || records[0].ClientID != "client-1" || records[0].Latency <= 0 || records[0].Error != "" {
+		t.Fatalf("collector got %+v, want a success and a failure for client-1", records)
+	}
+	if records[1].Error != "Unavailable" {
+		t.Errorf("failure recorded with error %q, want Unavailable", records[1].Error)
+	}
+}
+
+// LatencyRecord 집계 서버가 받는 JSON
+type LatencyRecord struct {
+	ClientID string `json:"client_id"`
+	Latency  int64  `json:"latency"`
+	Error    string `json:"error"`
+}
+
+func TestDefaultClientID(t *testing.T) {
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skip(err)
+	}
+	if got := defaultClientID(); got != host {
+		t.Errorf("defaultClientID() = %q, want the hostname %q", got, host)
+	}
+}
@@ -0,0 +1,64 @@
+// compare 는 aggregator 가 저장한 두 JSON 보고서를 비교하고,
+// 허용치를 넘는 회귀가 있으면 1 로 종료한다.
+//
+//	compare [-threshold p99=10,throughput=5] [-significant=false] base.json candidate.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+)
+
+// run 종료 코드를 돌려준다. 0 은 통과, 1 은 회귀, 2 는 잘못된 사용이다.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	threshold := fs.String("threshold", "p99=10", "Allowed worsening per metric in percent, e.g. p99=10,mean=5,throughput=5. Metrics: p50, p90, p95, p99, p999, mean, throughput.")
+	significant := fs.Bool("significant", true, "Only fail on changes that are statistically significant.")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: compare [flags] base.json candidate.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	thresholds, err := report.ParseThresholds(*threshold)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	base, err := report.Load(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	candidate, err := report.Load(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	comparison := report.Compare(base, candidate)
+	comparison.Write(stdout)
+	regressions := comparison.Regressions(thresholds, *significant)
+	if len(regressions) == 0 {
+		fmt.Fprintln(stdout, "PASS")
+		return 0
+	}
+	for _, r := range regressions {
+		fmt.Fprintf(stdout, "REGRESSION: %s\n", r)
+	}
+	return 1
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+)
+
+func writeReport(t *testing.T, dir string, started time.Time, scale float64) string {
+	t.Helper()
+	h := histogram.NewLatency()
+	for i := 0; i < 5000; i++ {
+		h.Record(time.Duration(scale * float64(20*time.Millisecond+time.Duration(i%500)*20*time.Microsecond)))
+	}
+	r := &report.Report{
+		Metadata: report.Metadata{StartedAt: started, ModelName: "vitpose_ensemble"},
+		Total:    report.NewSummary("ALL", h, 50*time.Second),
+	}
+	paths, err := r.WriteFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return paths[0]
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	started := time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC)
+	base := writeReport(t, dir, started, 1)
+	same := writeReport(t, dir, started.Add(time.Hour), 1)
+	slower := writeReport(t, dir, started.Add(2*time.Hour), 1.15)
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+		out  string
+	}{
+		{"same", []string{base, same}, 0, "PASS"},
+		{"p99 regression", []string{base, slower}, 1, "REGRESSION: p99 worsened by 15.0% (limit 10.0%)"},
+		{"looser threshold", []string{"-threshold", "p99=20", base, slower}, 0, "PASS"},
+		{"missing file", []string{base, filepath.Join(dir, "missing.json")}, 2, ""},
+		{"bad threshold", []string{"-threshold", "p99", base, slower}, 2, ""},
+		{"one report", []string{base}, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("exit code = %d, want %d\n%s%s", code, tt.code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.out) {
+				t.Errorf("output does not contain %q:\n%s", tt.out, stdout.String())
+			}
+		})
+	}
+}
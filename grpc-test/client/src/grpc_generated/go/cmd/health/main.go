@@ -0,0 +1,156 @@
+// health 는 Triton 서버와 모델 (앙상블이면 각 단계까지) 이 추론을 받을 수 있는지 한 번 확인한다.
+// 종료 코드가 실패 종류를 나타내므로 Kubernetes exec 프로브나 배포 스크립트에서 그대로 쓸 수 있다.
+//
+//	health [-u localhost:8001] [-m vitpose_ensemble] [-x 1] [-server-only] [-o text|json]
+//
+// 종료 코드: 0 정상, 1 접속 실패, 2 잘못된 사용, 3 live 아님, 4 ready 아님, 5 모델 ready 아님,
+// 6 앙상블 단계 ready 아님, 7 메타데이터 조회 실패.
+//
+// watch 는 멈출 때까지 상태를 지켜보며 바뀔 때마다 한 줄씩 쓰고, NOT_SERVING 이 되면 웹훅과 명령으로 알린다.
+//
+//	health watch [-interval 5s] [-webhook URL,...] [-exec 'command'] [-on-recover] [-server-only] [-o text|json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/health"
+)
+
+// run 종료 코드를 돌려준다. 값은 health.Status 이고 2 는 잘못된 사용이다. watch 는 ctx 가 끝날 때까지 돈다.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "watch" {
+		return runWatch(ctx, args[1:], stdout, stderr)
+	}
+	fs := flag.NewFlagSet("health", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("o", "text", "Output format: text or json.")
+	serverOnly := fs.Bool("server-only", false, "Check only the server, not the model.")
+	cfg, err := config.Load(fs, args, config.ServerFlags, config.ModelFlags)
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "health: unknown output format %q\n", *output)
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintf(stderr, "health: unexpected arguments %q\n", fs.Args())
+		return 2
+	}
+
+	model := cfg.Model.Name
+	if *serverOnly {
+		model = ""
+	}
+	var r *health.Report
+	// 일시적인 Unavailable 로 프로브가 실패하지 않도록 retry 정책으로 다시 시도한다.
+	conn, err := cfg.Dial(cfg.RetryPolicy().DialOption())
+	if err != nil {
+		r = &health.Report{Server: cfg.Server.URL, Status: health.Unreachable, ExitCode: int(health.Unreachable), Error: err.Error()}
+	} else {
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(ctx, cfg.Server.Timeout)
+		defer cancel()
+		r = health.Check(ctx, conn, cfg.Server.URL, model, cfg.Model.Version)
+	}
+
+	if *output == "json" {
+		err = r.WriteJSON(stdout)
+	} else {
+		err = r.WriteText(stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+	}
+	return r.ExitCode
+}
+
+// runWatch SIGINT 나 SIGTERM 으로 멈추면 0 을 돌려준다.
+func runWatch(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("health watch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("o", "text", "Output format: text or json (one transition per line).")
+	serverOnly := fs.Bool("server-only", false, "Watch only the server, not the model.")
+	cfg, err := config.Load(fs, args, config.ServerFlags, config.ModelFlags, config.WatchFlags)
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "health: unknown output format %q\n", *output)
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintf(stderr, "health: unexpected arguments %q\n", fs.Args())
+		return 2
+	}
+	conn, err := cfg.Dial(cfg.RetryPolicy().DialOption())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return int(health.Unreachable)
+	}
+	defer conn.Close()
+
+	// 전이는 감시 고루틴에서, Hook 실패는 Hook 고루틴에서 쓰므로 출력을 묶는다.
+	var mu sync.Mutex
+	alerter := &health.Alerter{
+		OnRecover: cfg.Watch.OnRecover,
+		Logf: func(format string, args ...any) {
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Fprintf(stderr, format+"\n", args...)
+		},
+	}
+	for _, url := range cfg.Watch.Webhooks {
+		alerter.Hooks = append(alerter.Hooks, health.Webhook{URL: url})
+	}
+	if cfg.Watch.Command != "" {
+		alerter.Hooks = append(alerter.Hooks, health.Command(cfg.Watch.Command))
+	}
+	defer alerter.Wait()
+
+	model := cfg.Model.Name
+	if *serverOnly {
+		model = ""
+	}
+	w := health.NewWatcher(conn, cfg.Server.URL, model, cfg.Model.Version, cfg.Watch.Interval)
+	w.Timeout = cfg.Server.Timeout
+	enc := json.NewEncoder(stdout)
+	w.OnTransition = func(t health.Transition) {
+		mu.Lock()
+		if *output == "json" {
+			enc.Encode(t)
+		} else {
+			fmt.Fprintln(stdout, t)
+		}
+		mu.Unlock()
+		alerter.Notify(t)
+	}
+	if err := w.Run(ctx); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+)
+
+func TestRun(t *testing.T) {
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository("../../../../../../../pose_model_zoo"); err != nil {
+		t.Fatal(err)
+	}
+	addr, err := server.StartTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	server.SetModelReady("postprocess", false)
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+		out  string
+	}{
+		{"step not ready", []string{"-u", addr}, 6, "STEP NOT READY (exit 6)"},
+		{"json", []string{"-u", addr, "-o", "json"}, 6, `"status": "step_not_ready"`},
+		{"server only", []string{"-u", addr, "-server-only"}, 0, "OK (exit 0)"},
+		{"other model", []string{"-u", addr, "-m", "vitpose"}, 0, "model vitpose: ready"},
+		{"bad format", []string{"-u", addr, "-o", "yaml"}, 2, ""},
+		{"unreachable", []string{"-u", "127.0.0.1:1", "-timeout", "1s"}, 1, "UNREACHABLE (exit 1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(context.Background(), tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("exit code = %d, want %d\n%s%s", code, tt.code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.out) {
+				t.Errorf("output does not contain %q:\n%s", tt.out, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunWatch(t *testing.T) {
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository("../../../../../../../pose_model_zoo"); err != nil {
+		t.Fatal(err)
+	}
+	addr, err := server.StartTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	server.SetModelReady("postprocess", false)
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		server.SetModelReady("postprocess", true)
+	}()
+
+	out := filepath.Join(t.TempDir(), "alerts")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	args := []string{"watch", "-u", addr, "-interval", "20ms", "-exec", `echo "$HEALTH_STATE $HEALTH_STATUS" >> ` + out}
+	if code := run(ctx, args, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, want 0\n%s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "UNKNOWN -> NOT_SERVING: ensemble step postprocess of vitpose_ensemble is not ready (poll)") ||
+		!strings.Contains(lines[1], "NOT_SERVING -> SERVING after ") {
+		t.Errorf("output:\n%s", stdout.String())
+	}
+	if b, err := os.ReadFile(out); err != nil || string(b) != "NOT_SERVING step_not_ready\n" {
+		t.Errorf("alerts = %q, %v", b, err)
+	}
+
+	if code := run(ctx, []string{"watch", "-interval", "0s"}, &stdout, &stderr); code != 2 {
+		t.Errorf("invalid interval: exit code = %d, want 2", code)
+	}
+}
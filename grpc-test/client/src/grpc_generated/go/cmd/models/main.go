@@ -0,0 +1,219 @@
+// models 는 컨테이너를 다시 띄우지 않고 Triton 의 모델을 올리고 내린다. 새 model.plan 을 저장소에 넣은 뒤
+// models load 로 다시 올리면 된다. Triton 은 --model-control-mode=explicit 으로 떠 있어야 한다.
+//
+//	models list   [-u localhost:8001] [-ready] [-json]
+//	models load   [-u ...] [-version 1,2] [-override '{"max_batch_size": 8}' | -override @override.json] [-repo pose_model_zoo] [-no-deps] NAME
+//	models unload [-u ...] [-deps] [-force] NAME
+//	models deps   [-u ...] [-repo pose_model_zoo] NAME
+//
+// 앙상블을 올리면 단계 모델 (vitpose, postprocess) 을 먼저 올리고 모두 ModelReady 가 될 때까지 기다린다.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/repository"
+)
+
+const usage = `Usage: models <command> [flags]
+
+Commands:
+  list     List the models in the repository with their versions and states.
+  load     Load or reload a model, its ensemble steps first, and wait until it is ready.
+  unload   Unload a model.
+  deps     Print the models an ensemble needs, in load order.
+
+Run "models <command> -h" for the flags of a command.
+`
+
+// run 종료 코드를 돌려준다. 0 은 성공, 1 은 실패, 2 는 잘못된 사용이다.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet("models "+cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		asJSON, readyOnly, noDeps, noWait, dependents, force *bool
+		repo, version, override                              *string
+		timeout                                              *time.Duration
+		nargs                                                int
+	)
+	addTimeout := func() {
+		timeout = fs.Duration("load-timeout", 5*time.Minute, "How long to wait for the load or unload, including ModelReady.")
+	}
+	switch cmd {
+	case "list":
+		readyOnly = fs.Bool("ready", false, "Only models that are ready.")
+		asJSON = fs.Bool("json", false, "Print the index as JSON instead of a table.")
+	case "load":
+		repo = fs.String("repo", os.Getenv("MODEL_REPOSITORY"), "Local model repository with <model>/config.pbtxt, for models the server has not loaded. Env: MODEL_REPOSITORY.")
+		version = fs.String("version", "", "Load only these comma-separated versions (sets version_policy to specific).")
+		override = fs.String("override", "", "Model config JSON to use instead of config.pbtxt, or @file to read it from a file.")
+		noDeps = fs.Bool("no-deps", false, "Do not load the ensemble steps first.")
+		noWait = fs.Bool("no-wait", false, "Return once Triton accepts the load instead of waiting for ModelReady.")
+		addTimeout()
+		nargs = 1
+	case "unload":
+		dependents = fs.Bool("deps", false, "Also unload the ensemble steps.")
+		force = fs.Bool("force", false, "Unload even if a loaded ensemble uses the model.")
+		noWait = fs.Bool("no-wait", false, "Return once Triton accepts the unload.")
+		addTimeout()
+		nargs = 1
+	case "deps":
+		repo = fs.String("repo", os.Getenv("MODEL_REPOSITORY"), "Local model repository with <model>/config.pbtxt. Env: MODEL_REPOSITORY.")
+		nargs = 1
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "models: unknown command %q\n\n%s", cmd, usage)
+		return 2
+	}
+	cfg, err := config.Load(fs, args, config.ServerFlags)
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+	if fs.NArg() != nargs {
+		fmt.Fprintf(stderr, "models %s: unexpected arguments %q\n", cmd, fs.Args())
+		return 2
+	}
+	var overrideJSON string
+	if override != nil && *override != "" {
+		if overrideJSON, err = readOverride(*override); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	// 모델을 올리는 데는 REQUEST_TIMEOUT 보다 오래 걸릴 수 있으므로 전체 시간은 -load-timeout 으로 정한다.
+	policy := cfg.RetryPolicy()
+	policy.Timeout = 0
+	conn, err := cfg.Dial(policy.DialOption())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer conn.Close()
+	client := repository.NewClient(triton.NewGRPCInferenceServiceClient(conn))
+	if repo != nil {
+		client.Repo = *repo
+	}
+	d := cfg.Server.Timeout
+	if timeout != nil {
+		d = *timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	name := fs.Arg(0)
+	switch cmd {
+	case "list":
+		err = list(ctx, client, *readyOnly, *asJSON, stdout)
+	case "load":
+		err = client.Load(ctx, name, repository.LoadOptions{
+			Versions: versions(*version),
+			Config:   overrideJSON,
+			NoDeps:   *noDeps,
+			Wait:     !*noWait,
+			Loaded:   func(name string) { fmt.Fprintf(stdout, "Loaded %s\n", name) },
+		})
+	case "unload":
+		err = client.Unload(ctx, name, repository.UnloadOptions{Dependents: *dependents, Force: *force, Wait: !*noWait})
+		if err == nil {
+			fmt.Fprintf(stdout, "Unloaded %s\n", name)
+		}
+	case "deps":
+		var steps []repository.Step
+		if steps, err = client.Dependencies(ctx, name, nil); err == nil {
+			for _, step := range steps {
+				fmt.Fprintln(stdout, modelName(step.Name, step.Version))
+			}
+			fmt.Fprintln(stdout, name)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// readOverride JSON 문자열, 또는 @ 로 시작하면 그 파일의 내용
+func readOverride(v string) (string, error) {
+	if !strings.HasPrefix(v, "@") {
+		return v, nil
+	}
+	data, err := os.ReadFile(v[1:])
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// versions 쉼표로 나눈 버전 목록. 비어 있으면 nil 이다.
+func versions(v string) []string {
+	var list []string
+	for _, version := range strings.Split(v, ",") {
+		if version = strings.TrimSpace(version); version != "" {
+			list = append(list, version)
+		}
+	}
+	return list
+}
+
+func list(ctx context.Context, client *repository.Client, readyOnly, asJSON bool, w io.Writer) error {
+	models, err := client.Index(ctx, readyOnly)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		if models == nil {
+			models = []repository.Model{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(models)
+	}
+	if len(models) == 0 {
+		fmt.Fprintln(w, "No models.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Model\tVersion\tState\tReason")
+	for _, m := range models {
+		version := m.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.Name, version, m.State, m.Reason)
+	}
+	return tw.Flush()
+}
+
+func modelName(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + ":" + version
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
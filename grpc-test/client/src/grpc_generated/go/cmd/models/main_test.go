@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+)
+
+func TestRun(t *testing.T) {
+	const repo = "../../../../../../../pose_model_zoo"
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository(repo); err != nil {
+		t.Fatal(err)
+	}
+	addr, err := server.StartTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+		out  string
+	}{
+		{"list", []string{"list", "-u", addr}, 0, "vitpose_ensemble  1        READY"},
+		{"deps", []string{"deps", "-u", addr, "vitpose_ensemble"}, 0, "vitpose:1\npostprocess:1\nvitpose_ensemble\n"},
+		{"step in use", []string{"unload", "-u", addr, "postprocess"}, 1, ""},
+		{"unload all", []string{"unload", "-u", addr, "-deps", "vitpose_ensemble"}, 0, "Unloaded vitpose_ensemble"},
+		{"ready only", []string{"list", "-u", addr, "-ready"}, 0, "No models."},
+		{"load", []string{"load", "-u", addr, "-repo", repo, "vitpose_ensemble"}, 0, "Loaded vitpose\nLoaded postprocess\nLoaded vitpose_ensemble\n"},
+		{"override", []string{"load", "-u", addr, "-override", `{"platform": "tensorrt_plan", "max_batch_size": 8}`, "vitpose"}, 0, "Loaded vitpose"},
+		{"json", []string{"list", "-u", addr, "-json"}, 0, `"state": "READY"`},
+		{"bad override", []string{"load", "-u", addr, "-override", "@missing.json", "vitpose"}, 2, ""},
+		{"no name", []string{"load", "-u", addr}, 2, ""},
+		{"unknown command", []string{"reload"}, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("exit code = %d, want %d\n%s%s", code, tt.code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.out) {
+				t.Errorf("output does not contain %q:\n%s", tt.out, stdout.String())
+			}
+		})
+	}
+}
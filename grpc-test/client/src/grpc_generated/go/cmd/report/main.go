@@ -0,0 +1,70 @@
+// report 는 aggregator 가 저장한 JSON 보고서를 차트가 들어간 HTML 파일 하나로 바꾼다.
+// 레이턴시 산점도, 시간별 백분위, 백분위 분포, 히스토그램, 클라이언트별 히트맵, 에러 타임라인이 들어간다.
+//
+//	report [-o run.html] run.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+)
+
+// run 종료 코드를 돌려준다. 0 은 성공, 1 은 실패, 2 는 잘못된 사용이다.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("o", "", "HTML file to write. \"-\" writes to standard output. Default: the JSON path with .html.")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: report [flags] run.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	r, err := report.Load(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *output == "-" {
+		if err := r.WriteHTML(stdout); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+	path := *output
+	if path == "" {
+		path = strings.TrimSuffix(fs.Arg(0), ".json") + ".html"
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := r.WriteHTML(f); err != nil {
+		f.Close()
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Report written to %s\n", path)
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
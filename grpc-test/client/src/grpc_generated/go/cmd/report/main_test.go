@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	h := histogram.NewLatency()
+	for i := 1; i <= 100; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	path := filepath.Join(dir, "run.json")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &report.Report{Metadata: report.Metadata{ModelName: "vitpose_ensemble"}, Total: report.NewSummary("ALL", h, 10*time.Second)}
+	if err := r.WriteJSON(f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d: %s", code, stderr.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "run.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "<h2>Histogram</h2>\n<svg ") {
+		t.Errorf("run.html has no histogram:\n%s", data)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-o", "-", path}, &stdout, &stderr); code != 0 || !strings.HasPrefix(stdout.String(), "<!DOCTYPE html>") {
+		t.Errorf("-o -: exit code %d, output %.40q", code, stdout.String())
+	}
+	if code := run([]string{filepath.Join(dir, "missing.json")}, &stdout, &stderr); code != 1 {
+		t.Errorf("missing report: exit code %d, want 1", code)
+	}
+	if code := run(nil, &stdout, &stderr); code != 2 {
+		t.Errorf("no arguments: exit code %d, want 2", code)
+	}
+}
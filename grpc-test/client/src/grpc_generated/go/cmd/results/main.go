@@ -0,0 +1,265 @@
+// results 는 aggregator 가 -db (RESULTS_DB) 에 보관한 실행을 찾아보고 내보낸다.
+// model.plan 을 다시 빌드하거나 Triton 을 올린 뒤의 변화를 실행 사이에서 추적하는 데 쓴다.
+//
+//	results list   [-db results.db] [-model m] [-version v] [-batch n] [-since 2024-10-01] [-until ...] [-json]
+//	results show   [-db results.db] [-json | -html] ID
+//	results export [-db results.db] [-format csv|json] [-o file] [filters]
+//	results delete [-db results.db] ID
+//
+// 보고서 두 개는 results show -json 으로 꺼내 compare 로 비교할 수 있다.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/store"
+)
+
+const usage = `Usage: results <command> [flags]
+
+Commands:
+  list     List stored runs, oldest first.
+  show     Print a stored run as Markdown, as JSON with -json or as HTML with -html.
+  export   Write matching runs as CSV or JSON.
+  delete   Remove a stored run.
+
+Run "results <command> -h" for the flags of a command.
+`
+
+// run 종료 코드를 돌려준다. 0 은 성공, 1 은 실패, 2 는 잘못된 사용이다.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet("results "+cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dbPath := fs.String("db", envOr("RESULTS_DB", "results.db"), "Results database written by the aggregator. Env: RESULTS_DB.")
+
+	var (
+		f                  *filterFlags
+		asJSON, asHTML     *bool
+		format, outputPath *string
+		nargs              int
+	)
+	switch cmd {
+	case "list":
+		f = addFilterFlags(fs)
+		asJSON = fs.Bool("json", false, "Print the runs as JSON instead of a table.")
+	case "show":
+		asJSON = fs.Bool("json", false, "Print the full report as JSON, as accepted by compare.")
+		asHTML = fs.Bool("html", false, "Print the report as a self-contained HTML page with latency charts.")
+		nargs = 1
+	case "export":
+		f = addFilterFlags(fs)
+		format = fs.String("format", "csv", "Output format: csv (one row per run) or json (full reports).")
+		outputPath = fs.String("o", "", "File to write to. Default: standard output.")
+	case "delete":
+		nargs = 1
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "results: unknown command %q\n\n%s", cmd, usage)
+		return 2
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != nargs {
+		fmt.Fprintf(stderr, "results %s: unexpected arguments %q\n", cmd, fs.Args())
+		return 2
+	}
+	var filter store.Filter
+	if f != nil {
+		var err error
+		if filter, err = f.filter(); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+	if format != nil && *format != "csv" && *format != "json" {
+		fmt.Fprintf(stderr, "results export: unknown format %q\n", *format)
+		return 2
+	}
+
+	// delete 말고는 읽기만 하므로 aggregator 가 저장하는 중에도 읽을 수 있다.
+	open := store.OpenReadOnly
+	if cmd == "delete" {
+		if _, err := os.Stat(*dbPath); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		open = store.Open
+	}
+	db, err := open(*dbPath, 5*time.Second)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer db.Close()
+
+	switch cmd {
+	case "list":
+		err = list(db, filter, *asJSON, stdout)
+	case "show":
+		err = show(db, fs.Arg(0), *asJSON, *asHTML, stdout)
+	case "export":
+		err = export(db, filter, *format, *outputPath, stdout)
+	case "delete":
+		if err = db.Delete(fs.Arg(0)); err == nil {
+			fmt.Fprintf(stdout, "Deleted %s\n", fs.Arg(0))
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// filterFlags list 와 export 가 함께 쓰는 조건 플래그
+type filterFlags struct {
+	run, model, version, serverVersion, since, until *string
+	batch                                            *int
+}
+
+func addFilterFlags(fs *flag.FlagSet) *filterFlags {
+	return &filterFlags{
+		run:           fs.String("run", "", "Only runs with this name."),
+		model:         fs.String("model", "", "Only runs of this model."),
+		version:       fs.String("version", "", "Only runs of this model version."),
+		serverVersion: fs.String("server-version", "", "Only runs against this Triton version."),
+		batch:         fs.Int("batch", 0, "Only runs with this batch size."),
+		since:         fs.String("since", "", "Only runs started at or after this time (2006-01-02, 2006-01-02 15:04 or RFC 3339, UTC by default)."),
+		until:         fs.String("until", "", "Only runs started before this time."),
+	}
+}
+
+func (f *filterFlags) filter() (store.Filter, error) {
+	filter := store.Filter{
+		Run:           *f.run,
+		Model:         *f.model,
+		Version:       *f.version,
+		ServerVersion: *f.serverVersion,
+		BatchSize:     *f.batch,
+	}
+	var err error
+	if *f.since != "" {
+		if filter.Since, err = store.ParseTime(*f.since); err != nil {
+			return filter, err
+		}
+	}
+	if *f.until != "" {
+		if filter.Until, err = store.ParseTime(*f.until); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+func list(db *store.Store, f store.Filter, asJSON bool, w io.Writer) error {
+	entries, err := db.List(f)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		if entries == nil {
+			entries = []store.Entry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No runs.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tStarted\tModel\tBatch\tConc\tTriton\tRequests\tRPS\tP50 (ms)\tP99 (ms)\tErr%")
+	for _, e := range entries {
+		m, s := e.Metadata, e.Total
+		model := m.ModelName
+		if m.ModelVersion != "" {
+			model += ":" + m.ModelVersion
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\t%d\t%.1f\t%.2f\t%.2f\t%.2f\n",
+			e.ID, m.StartedAt.UTC().Format("2006-01-02 15:04"), model, m.BatchSize, m.Concurrency, m.ServerVersion,
+			s.Requests(), s.Throughput, ms(s.P50), ms(s.P99), 100*s.ErrorRate())
+	}
+	return tw.Flush()
+}
+
+func show(db *store.Store, id string, asJSON, asHTML bool, w io.Writer) error {
+	r, err := db.Get(id)
+	if err != nil {
+		return err
+	}
+	switch {
+	case asJSON:
+		return r.WriteJSON(w)
+	case asHTML:
+		return r.WriteHTML(w)
+	}
+	return r.WriteMarkdown(w)
+}
+
+func export(db *store.Store, f store.Filter, format, path string, stdout io.Writer) error {
+	w := stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	entries, err := db.List(f)
+	if err != nil {
+		return err
+	}
+	if format == "csv" {
+		return store.WriteCSV(w, entries)
+	}
+
+	// json 은 히스토그램까지 담은 보고서 배열이다.
+	fmt.Fprint(w, "[")
+	for i, e := range entries {
+		r, err := db.Get(e.ID)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprintf(w, "\n%s", data)
+	}
+	_, err = fmt.Fprint(w, "\n]\n")
+	return err
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
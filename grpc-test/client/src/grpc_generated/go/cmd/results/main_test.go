@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/store"
+)
+
+func writeDB(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "results.db")
+	db, err := store.Open(path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	started := time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC)
+	for i, version := range []string{"1", "2"} {
+		h := histogram.NewLatency()
+		for j := 0; j < 100; j++ {
+			h.Record(time.Duration(20+10*i) * time.Millisecond)
+		}
+		r := &report.Report{
+			Metadata: report.Metadata{
+				RunName:       "nightly",
+				StartedAt:     started.Add(time.Duration(i) * 24 * time.Hour),
+				ServerVersion: "2.46.0",
+				ModelName:     "vitpose_ensemble",
+				ModelVersion:  version,
+				BatchSize:     4,
+			},
+			Total: report.NewSummary("ALL", h, 10*time.Second),
+		}
+		if _, err := db.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	db := writeDB(t)
+	out := filepath.Join(t.TempDir(), "out.json")
+	tests := []struct {
+		name string
+		args []string
+		code int
+		out  string
+	}{
+		{"list", []string{"list", "-db", db}, 0, "20241002T090000Z-nightly  2024-10-02 09:00  vitpose_ensemble:2"},
+		{"list by version", []string{"list", "-db", db, "-version", "3"}, 0, "No runs."},
+		{"list since", []string{"list", "-db", db, "-since", "2024-10-02", "-json"}, 0, `"id": "20241002T090000Z-nightly"`},
+		{"show", []string{"show", "-db", db, "20241001T090000Z-nightly"}, 0, "vitpose_ensemble"},
+		{"show html", []string{"show", "-db", db, "-html", "20241001T090000Z-nightly"}, 0, "<h2>Percentile distribution</h2>"},
+		{"export csv", []string{"export", "-db", db, "-batch", "4"}, 0, "20241001T090000Z-nightly,nightly,"},
+		{"export json", []string{"export", "-db", db, "-format", "json", "-o", out}, 0, ""},
+		{"delete", []string{"delete", "-db", db, "20241001T090000Z-nightly"}, 0, "Deleted"},
+		{"delete again", []string{"delete", "-db", db, "20241001T090000Z-nightly"}, 1, ""},
+		{"bad since", []string{"list", "-db", db, "-since", "yesterday"}, 2, ""},
+		{"bad format", []string{"export", "-db", db, "-format", "xml"}, 2, ""},
+		{"show without id", []string{"show", "-db", db}, 2, ""},
+		{"missing db", []string{"list", "-db", filepath.Join(t.TempDir(), "none.db")}, 1, ""},
+		{"unknown command", []string{"prune"}, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("exit code = %d, want %d\n%s%s", code, tt.code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.out) {
+				t.Errorf("output does not contain %q:\n%s", tt.out, stdout.String())
+			}
+		})
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reports []report.Report
+	if err := json.Unmarshal(data, &reports); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[1].Metadata.ModelVersion != "2" || len(reports[1].Total.Histogram) == 0 {
+		t.Errorf("exported %d reports, want both with histograms", len(reports))
+	}
+}
@@ -0,0 +1,134 @@
+// rollout 은 pose_model_zoo/vitpose/2 처럼 저장소에 새 엔진 버전을 넣은 뒤, 컨테이너를 다시 띄우지 않고
+// 앙상블이 그 버전을 쓰도록 바꾼다. 새 버전을 지금 버전과 함께 올려 같은 입력으로 비교하고, 키포인트 차이와
+// 지연 시간이 허용치 안일 때만 앙상블 설정의 model_version 을 바꾼다. 바꾼 뒤 추론이 실패하면 되돌린다.
+// Triton 은 --model-control-mode=explicit 으로 떠 있어야 한다.
+//
+//	rollout [-u localhost:8001] [-ensemble vitpose_ensemble] [-step vitpose] -to 2 [-images a.jpg,b.jpg | -samples 4]
+//	        [-max-mean-drift 2] [-max-drift 8] [-max-latency-ratio 1.2] [-min-latency-samples 100] [-verify 30s] [-no-rollback] [-retire] [-dry-run] [-o text|json]
+//
+// 종료 코드: 0 바꿨거나 (-dry-run) 비교를 통과함, 1 실패, 2 잘못된 사용, 3 비교에서 떨어짐, 4 되돌림.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/preprocess"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/repository"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/rollout"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+// exitCodes Outcome 별 종료 코드
+var exitCodes = map[rollout.Outcome]int{
+	rollout.Passed:     0,
+	rollout.Switched:   0,
+	rollout.Failed:     1,
+	rollout.Rejected:   3,
+	rollout.RolledBack: 4,
+}
+
+// run 종료 코드를 돌려준다.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("rollout", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := rollout.Defaults()
+	fs.StringVar(&opts.Ensemble, "ensemble", opts.Ensemble, "Ensemble to switch.")
+	fs.StringVar(&opts.Step, "step", opts.Step, "Ensemble step that gets the new version.")
+	fs.StringVar(&opts.To, "to", "", "New version of the step. It must be in the model repository.")
+	images := fs.String("images", "", "Comma-separated images to compare the versions on. Random inputs if empty.")
+	samples := fs.Int("samples", 4, "Number of random batches when -images is empty.")
+	batchSize := fs.Int("b", 1, "Batch size of each sample.")
+	fs.IntVar(&opts.Rounds, "rounds", opts.Rounds, "How many times each sample is sent to both versions.")
+	fs.Float64Var(&opts.MaxMeanDrift, "max-mean-drift", opts.MaxMeanDrift, "Allowed mean keypoint distance between the versions in pixels.")
+	fs.Float64Var(&opts.MaxDrift, "max-drift", opts.MaxDrift, "Allowed distance of any single keypoint in pixels. 0 disables the check.")
+	fs.Float64Var(&opts.MaxLatencyRatio, "max-latency-ratio", opts.MaxLatencyRatio, "Allowed p99 latency of the new version relative to the current one.")
+	fs.IntVar(&opts.MinLatencySamples, "min-latency-samples", opts.MinLatencySamples, "Requests to the new version needed to check p99 latency; with fewer, p50 is checked instead.")
+	fs.DurationVar(&opts.Verify, "verify", opts.Verify, "How long to send requests to the ensemble after the switch before keeping it.")
+	noRollback := fs.Bool("no-rollback", false, "Keep the new version even if requests fail after the switch.")
+	fs.BoolVar(&opts.Retire, "retire", false, "Unload the old version of the step after a successful switch.")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "Only compare the versions; do not switch the ensemble.")
+	output := fs.String("o", "text", "Output format: text or json.")
+	cfg, err := config.Load(fs, args, config.ServerFlags)
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+	if opts.To == "" || fs.NArg() > 0 || (*output != "text" && *output != "json") || *samples <= 0 || *batchSize <= 0 {
+		fmt.Fprintln(stderr, "rollout: -to is required; -o must be text or json; -samples and -b must be positive")
+		return 2
+	}
+	opts.Rollback = !*noRollback
+	if opts.Samples, err = loadSamples(*images, *samples, *batchSize); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if *output == "text" {
+		opts.Logf = func(format string, args ...any) { fmt.Fprintf(stderr, format+"\n", args...) }
+	}
+
+	// 모델을 올리고 바꾼 뒤 지켜보는 데 REQUEST_TIMEOUT 보다 오래 걸리므로 전체 시간 제한은 두지 않는다.
+	policy := cfg.RetryPolicy()
+	policy.Timeout = 0
+	conn, err := cfg.Dial(policy.DialOption())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer conn.Close()
+	client := triton.NewGRPCInferenceServiceClient(conn)
+	result, err := rollout.New(client, repository.NewClient(client), opts).Run(ctx)
+
+	if *output == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(result)
+	} else {
+		result.WriteText(stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return exitCodes[result.Outcome]
+}
+
+// loadSamples images 의 이미지마다 batchSize 장짜리 Batch 하나, 비어 있으면 난수 Batch n 개
+func loadSamples(images string, n, batchSize int) ([]*vitpose.Batch, error) {
+	var batches []*vitpose.Batch
+	for _, path := range strings.Split(images, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		batch, err := preprocess.LoadBatch(path, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	if len(batches) > 0 {
+		return batches, nil
+	}
+	for range n {
+		batches = append(batches, vitpose.RandomBatch(batchSize))
+	}
+	return batches, nil
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/rollout"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+)
+
+func TestRun(t *testing.T) {
+	const repo = "../../../../../../../pose_model_zoo"
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository(repo); err != nil {
+		t.Fatal(err)
+	}
+	config, err := tritontest.LoadModelConfig(repo + "/vitpose/config.pbtxt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.AddRepositoryModel(config, "1", "2", "3")
+	addr, err := server.StartTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	// 지연 시간 검사는 "slow version" 에서만 한다. 나머지는 몇 번 안 되는 요청의 p99 가 흔들려도 떨어지지 않게 끈다.
+	const noLatency = "-max-latency-ratio=1e9"
+	server.SetVersionBehavior("vitpose", "3", tritontest.VersionBehavior{Latency: 200 * time.Millisecond})
+	tests := []struct {
+		name string
+		args []string
+		code int
+		out  string
+	}{
+		{"dry run", []string{"-u", addr, "-to", "2", "-dry-run", "-samples", "1", "-rounds", "1", noLatency}, 0, "PASSED"},
+		{"missing version", []string{"-u", addr, "-to", "4", "-samples", "1"}, 1, "vitpose_ensemble: vitpose version 1 -> 4"},
+		{"slow version", []string{"-u", addr, "-to", "3", "-samples", "1", "-rounds", "1"}, 3, "REJECTED"},
+		{"no version", []string{"-u", addr}, 2, ""},
+		{"bad output", []string{"-u", addr, "-to", "2", "-o", "yaml"}, 2, ""},
+		{"missing image", []string{"-u", addr, "-to", "2", "-images", "missing.jpg"}, 2, ""},
+		{"switch", []string{"-u", addr, "-to", "2", "-samples", "1", "-rounds", "1", "-verify", "0", "-retire", noLatency}, 0, "SWITCHED"},
+		{"already switched", []string{"-u", addr, "-to", "2"}, 1, "already uses version 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tt.args, &stdout, &stderr)
+			if code != tt.code {
+				t.Fatalf("code = %d, want %d\nstdout: %s\nstderr: %s", code, tt.code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String()+stderr.String(), tt.out) {
+				t.Errorf("output = %q, want it to contain %q", stdout.String()+stderr.String(), tt.out)
+			}
+		})
+	}
+
+	var stdout bytes.Buffer
+	if code := run(context.Background(), []string{"-u", addr, "-to", "1", "-samples", "1", "-o", "json", "-dry-run", noLatency}, &stdout, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("json dry run back to version 1: code = %d", code)
+	}
+	var result rollout.Result
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil || result.Outcome != rollout.Passed || result.From != "2" || result.Comparison == nil {
+		t.Errorf("json result = %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,370 @@
+// Package collector 는 부하 테스트 클라이언트가 요청 결과를 모아 집계 서버로 보내는 배치 형식을 정의한다.
+//
+// 클라이언트는 Sender 로 결과를 모았다가 주기적으로 gzip 으로 압축한 JSON 배치 하나로 보낸다.
+// 배치마다 클라이언트 ID, 세션, 순번이 붙으므로 집계 서버는 Seen 으로 재전송된 배치를 걸러내
+// 재시도가 같은 요청을 두 번 세지 않게 한다.
+package collector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchPath 배치를 받는 집계 서버 경로. 단건 경로 (/record-latency) 옆에 있다.
+const BatchPath = "/record-batch"
+
+// Record 요청 하나의 결과
+type Record struct {
+	Latency time.Duration `json:"latency"`
+	// Error 가 있으면 실패한 요청이고 값은 gRPC 상태 코드 이름이다.
+	Error string `json:"error,omitempty"`
+	// At 클라이언트에서 요청이 끝난 시각. 배치는 모았다가 늦게 보내므로 집계 서버는 받은 시각 대신 이 시각으로 시간 구간을 나눈다.
+	At time.Time `json:"at"`
+}
+
+// Batch 클라이언트가 한 번에 보내는 결과 묶음. (ClientID, Session, Seq) 가 배치 하나를 가리킨다.
+type Batch struct {
+	ClientID string `json:"client_id"`
+	// RunID 기록을 넣을 집계 서버의 실행. 없으면 가장 최근에 시작한 실행이다.
+	RunID string `json:"run_id,omitempty"`
+	// Session 클라이언트 프로세스마다 새로 정해지므로, 같은 ID 로 다시 시작한 클라이언트의 배치를 구분한다.
+	Session string `json:"session"`
+	// Seq 세션 안에서 1 부터 1 씩 늘어나는 배치 번호
+	Seq      uint64 `json:"seq"`
+	Model    string `json:"model,omitempty"`
+	Version  string `json:"version,omitempty"`
+	InFlight int64  `json:"in_flight,omitempty"`
+	// BatchSize, Concurrency, LoadMode 클라이언트가 부하를 준 조건. 집계 서버가 보고서의 실행 조건으로 쓴다.
+	BatchSize   int      `json:"batch_size,omitempty"`
+	Concurrency int      `json:"concurrency,omitempty"`
+	LoadMode    string   `json:"load_mode,omitempty"`
+	Records     []Record `json:"records"`
+}
+
+// ReadBatch r 의 본문에서 배치를 읽는다. Content-Encoding 이 gzip 이면 압축을 푼다.
+func ReadBatch(r *http.Request) (*Batch, error) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("collector: %w", err)
+		}
+		defer zr.Close()
+		body = zr
+	}
+	var b Batch
+	if err := json.NewDecoder(body).Decode(&b); err != nil {
+		return nil, fmt.Errorf("collector: decode batch: %w", err)
+	}
+	// client_id 가 비어 있으면 ID 없이 띄운 복제본들이 집계 서버에서 한 줄로 합쳐진다.
+	// cmd/client 는 CLIENT_ID 가 없으면 호스트 이름을 쓴다.
+	if b.ClientID == "" || b.Session == "" || b.Seq == 0 {
+		return nil, fmt.Errorf("collector: batch needs a client_id, a session and a positive seq")
+	}
+	return &b, nil
+}
+
+// BatchURL 단건 수집 URL (COLLECTOR_URL) 과 같은 서버의 BatchPath
+func BatchURL(collectorURL string) (string, error) {
+	u, err := url.Parse(collectorURL)
+	if err != nil {
+		return "", fmt.Errorf("collector: %w", err)
+	}
+	return u.ResolveReference(&url.URL{Path: BatchPath}).String(), nil
+}
+
+// Register collectorURL 과 같은 서버의 runID 실행에 clientID 를 등록한다. 실행이 없거나 끝났으면 에러를 돌려준다.
+func Register(ctx context.Context, collectorURL, runID, clientID string) error {
+	u, err := url.Parse(collectorURL)
+	if err != nil {
+		return fmt.Errorf("collector: %w", err)
+	}
+	u = u.ResolveReference(&url.URL{Path: "/runs/" + url.PathEscape(runID) + "/clients"})
+	body, _ := json.Marshal(map[string]string{"client_id": clientID})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("collector: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("collector: register with run %s: %w", runID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("collector: register with run %s: %s: %s", runID, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// RejectedError 집계 서버가 배치를 4xx 로 거절했다. 끝난 실행 (409) 이나 잘못된 배치 (400) 는
+// 다시 보내도 받지 않으므로 Sender 는 거절된 배치를 버린다.
+type RejectedError struct {
+	Status  string
+	Message string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("collector rejected the batch: %s: %s", e.Status, e.Message)
+}
+
+// Sender 결과를 모았다가 Flush 할 때 배치 하나로 보낸다. 5xx 나 네트워크 오류로 보내지 못한 배치는
+// 같은 순번으로 다음 Flush 에서 다시 보내고, 4xx 로 거절된 배치는 버린다.
+type Sender struct {
+	URL      string
+	ClientID string
+	RunID    string
+	Model    string
+	Version  string
+	// InFlight 가 있으면 배치를 보낼 때의 값을 InFlight 로 싣는다.
+	InFlight func() int64
+	// BatchSize, Concurrency, LoadMode 배치마다 그대로 싣는다.
+	BatchSize   int
+	Concurrency int
+	LoadMode    string
+	// Retries 배치 하나를 보내다 실패했을 때 곧바로 다시 시도할 횟수
+	Retries int
+	// Backoff 첫 재시도까지의 대기 시간. 재시도마다 두 배로 늘어난다.
+	Backoff time.Duration
+	Client  *http.Client
+	// MaxPending 보내지 못하고 쌓아 둘 결과 수. 넘으면 새 결과를 버린다. 0 이면 제한하지 않는다.
+	MaxPending int
+
+	session string
+	mu      sync.Mutex
+	pending []Record
+
+	sendMu sync.Mutex // Flush 를 한 번에 하나씩 해서 순번 순서대로 보낸다.
+	seq    uint64
+	failed *Batch
+
+	dropped atomic.Int64
+}
+
+// NewSender url 로 clientID 의 배치를 보내는 Sender
+func NewSender(url, clientID string) *Sender {
+	session := make([]byte, 8)
+	rand.Read(session)
+	return &Sender{
+		URL:      url,
+		ClientID: clientID,
+		Retries:  2,
+		Backoff:  200 * time.Millisecond,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		// 집계 서버가 한참 내려가 있어도 클라이언트 메모리가 수 MB 를 넘지 않게 한다.
+		MaxPending: 100000,
+		session:    hex.EncodeToString(session),
+	}
+}
+
+// Add 결과 하나를 다음 배치에 넣는다. r.At 이 비어 있으면 지금 시각을 넣는다.
+// 보내지 못한 결과가 MaxPending 개 쌓여 있으면 버리고 Dropped 에 센다.
+func (s *Sender) Add(r Record) {
+	if r.At.IsZero() {
+		r.At = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.MaxPending > 0 && len(s.pending) >= s.MaxPending {
+		s.dropped.Add(1)
+		return
+	}
+	s.pending = append(s.pending, r)
+}
+
+// Dropped MaxPending 을 넘거나 집계 서버가 거절해서 버린 결과 수
+func (s *Sender) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Pending 아직 보내지 못한 결과 수
+func (s *Sender) Pending() int {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.pending)
+	if s.failed != nil {
+		n += len(s.failed.Records)
+	}
+	return n
+}
+
+// Flush 지난번에 보내지 못한 배치를 먼저 다시 보내고, 그 뒤에 모인 결과를 새 배치로 보낸다.
+// 거절된 배치는 버리고 *RejectedError 를 돌려준다.
+func (s *Sender) Flush(ctx context.Context) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	var rejected error
+	if s.failed != nil {
+		err := s.send(ctx, s.failed)
+		if err != nil && !isRejected(err) {
+			return err
+		}
+		if err != nil {
+			rejected = s.drop(s.failed, err)
+		}
+		s.failed = nil
+	}
+
+	s.mu.Lock()
+	records := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	if len(records) == 0 {
+		return rejected
+	}
+	s.seq++
+	b := &Batch{
+		ClientID:    s.ClientID,
+		RunID:       s.RunID,
+		Session:     s.session,
+		Seq:         s.seq,
+		Model:       s.Model,
+		Version:     s.Version,
+		BatchSize:   s.BatchSize,
+		Concurrency: s.Concurrency,
+		LoadMode:    s.LoadMode,
+		Records:     records,
+	}
+	if s.InFlight != nil {
+		b.InFlight = s.InFlight()
+	}
+	if err := s.send(ctx, b); err != nil {
+		if isRejected(err) {
+			return errors.Join(rejected, s.drop(b, err))
+		}
+		s.failed = b
+		return errors.Join(rejected, err)
+	}
+	return rejected
+}
+
+// drop 거절된 배치 b 의 결과를 버린 것으로 세고, 버린 수를 붙인 err 를 돌려준다.
+func (s *Sender) drop(b *Batch, err error) error {
+	s.dropped.Add(int64(len(b.Records)))
+	return fmt.Errorf("%w, dropped %d records", err, len(b.Records))
+}
+
+func isRejected(err error) bool {
+	var rejected *RejectedError
+	return errors.As(err, &rejected)
+}
+
+// Run ctx 가 끝날 때까지 interval 마다 Flush 한다. 실패는 onError 로 알린다. 남은 결과는 호출하는 쪽에서 Flush 한다.
+func (s *Sender) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// send 배치를 gzip JSON 으로 보낸다. 5xx 나 네트워크 오류면 Retries 번까지 Backoff 를 두 배씩 늘리며 다시 보내고,
+// 4xx 로 거절되면 다시 보내지 않는다.
+func (s *Sender) send(ctx context.Context, b *Batch) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return fmt.Errorf("collector: encode batch: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("collector: encode batch: %w", err)
+	}
+
+	backoff := s.Backoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = s.post(ctx, buf.Bytes()); err == nil {
+			return nil
+		}
+		if attempt >= s.Retries || isRejected(err) {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("collector: send batch %d: %w", b.Seq, ctx.Err())
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("collector: send batch %d: %w", b.Seq, err)
+}
+
+func (s *Sender) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &RejectedError{Status: resp.Status, Message: string(bytes.TrimSpace(msg))}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("collector responded %s", resp.Status)
+	}
+	return nil
+}
+
+// Seen 세션별로 받은 배치 순번을 기억한다. 동시에 쓰려면 호출하는 쪽에서 잠가야 한다.
+//
+// 세션마다 빈틈없이 받은 마지막 순번과 그보다 앞서 도착한 순번만 보관하므로, 배치가 순서대로 오면
+// 세션 수만큼의 메모리만 쓴다.
+type Seen struct {
+	sessions map[string]*seqs
+}
+
+type seqs struct {
+	next  uint64 // 아직 받지 않은 가장 작은 순번
+	ahead map[uint64]bool
+}
+
+// Add b 를 처음 받았으면 기억하고 true, 이미 받은 배치면 false 를 돌려준다.
+func (s *Seen) Add(b *Batch) bool {
+	if s.sessions == nil {
+		s.sessions = make(map[string]*seqs)
+	}
+	key := b.ClientID + "/" + b.Session
+	q, ok := s.sessions[key]
+	if !ok {
+		q = &seqs{next: 1, ahead: make(map[uint64]bool)}
+		s.sessions[key] = q
+	}
+	if b.Seq < q.next || q.ahead[b.Seq] {
+		return false
+	}
+	q.ahead[b.Seq] = true
+	for q.ahead[q.next] {
+		delete(q.ahead, q.next)
+		q.next++
+	}
+	return true
+}
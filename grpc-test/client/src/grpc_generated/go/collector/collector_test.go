@@ -0,0 +1,231 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSenderRetriesWithoutDoubleCounting(t *testing.T) {
+	var mu sync.Mutex
+	var seen Seen
+	var recorded, posts int
+	// 첫 배치는 기록한 뒤 응답을 잃어버린 것처럼 500 을 돌려준다.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ReadBatch(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		posts++
+		if seen.Add(b) {
+			recorded += len(b.Records)
+		}
+		if posts == 1 {
+			http.Error(w, "lost", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	s := NewSender(server.URL, "client-1")
+	s.Backoff = time.Millisecond
+	for i := 0; i < 3; i++ {
+		s.Add(Record{Latency: time.Duration(i+1) * time.Millisecond})
+	}
+	s.Add(Record{Error: "Unavailable"})
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s.Add(Record{Latency: time.Millisecond})
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if posts != 3 || recorded != 5 {
+		t.Errorf("posts, recorded = %d, %d, want 3, 5", posts, recorded)
+	}
+	if s.Pending() != 0 {
+		t.Errorf("Pending = %d after successful flushes", s.Pending())
+	}
+}
+
+func TestSenderKeepsFailedBatch(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	var got []*Batch
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		b, err := ReadBatch(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		got = append(got, b)
+	}))
+	defer server.Close()
+
+	s := NewSender(server.URL, "client-1")
+	s.Retries = 0
+	s.BatchSize, s.Concurrency, s.LoadMode = 4, 2, "closed"
+	s.Add(Record{Latency: time.Millisecond})
+	if err := s.Flush(context.Background()); err == nil {
+		t.Fatal("Flush succeeded while the collector was down")
+	}
+	s.Add(Record{Latency: 2 * time.Millisecond})
+	if s.Pending() != 2 {
+		t.Errorf("Pending = %d, want 2", s.Pending())
+	}
+
+	down.Store(false)
+	if err := s.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Seq != 1 || got[1].Seq != 2 || got[0].Session != got[1].Session || len(got[1].Records) != 1 {
+		t.Fatalf("batches = %+v, want seq 1 resent before seq 2", got)
+	}
+	if b := got[1]; b.BatchSize != 4 || b.Concurrency != 2 || b.LoadMode != "closed" {
+		t.Errorf("batch conditions = %d, %d, %q, want the sender's 4, 2, closed", b.BatchSize, b.Concurrency, b.LoadMode)
+	}
+	// 시각은 Add 할 때 찍으므로 다시 보낸 배치의 기록도 처음 모은 시각을 갖는다.
+	if first, second := got[0].Records[0].At, got[1].Records[0].At; first.IsZero() || first.After(second) {
+		t.Errorf("record times = %v, %v, want the times of Add", first, second)
+	}
+}
+
+func TestSenderDropsRejectedBatch(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		if code := int(status.Load()); code != http.StatusOK {
+			http.Error(w, "Run default is stopped", code)
+		}
+	}))
+	defer server.Close()
+
+	s := NewSender(server.URL, "client-1")
+	s.Retries, s.Backoff = 2, time.Millisecond
+	s.Add(Record{Latency: time.Millisecond})
+	if err := s.Flush(context.Background()); err == nil || isRejected(err) {
+		t.Fatalf("Flush error = %v, want a retryable error", err)
+	}
+	if n := posts.Load(); n != 3 {
+		t.Errorf("posted %d times on 503, want 3", n)
+	}
+
+	// 실행이 끝나 409 를 받으면 다시 보내지 않고, 보내지 못했던 배치와 새 배치를 모두 버린다.
+	status.Store(http.StatusConflict)
+	posts.Store(0)
+	s.Add(Record{Latency: time.Millisecond})
+	s.Add(Record{Latency: time.Millisecond})
+	err := s.Flush(context.Background())
+	var rejected *RejectedError
+	if !errors.As(err, &rejected) || !strings.Contains(err.Error(), "Run default is stopped") {
+		t.Fatalf("Flush error = %v, want a RejectedError", err)
+	}
+	if n := posts.Load(); n != 2 {
+		t.Errorf("posted %d times on 409, want once per batch", n)
+	}
+	if s.Pending() != 0 || s.Dropped() != 3 {
+		t.Errorf("Pending, Dropped = %d, %d, want 0, 3", s.Pending(), s.Dropped())
+	}
+	if err := s.Flush(context.Background()); err != nil || posts.Load() != 2 {
+		t.Errorf("Flush after dropping = %v with %d posts, want nothing to send", err, posts.Load())
+	}
+}
+
+func TestSenderMaxPending(t *testing.T) {
+	s := NewSender("http://127.0.0.1:0", "client-1")
+	s.MaxPending = 2
+	for i := 0; i < 5; i++ {
+		s.Add(Record{Latency: time.Millisecond})
+	}
+	if s.Pending() != 2 || s.Dropped() != 3 {
+		t.Errorf("Pending, Dropped = %d, %d, want 2, 3", s.Pending(), s.Dropped())
+	}
+}
+
+func TestSeen(t *testing.T) {
+	var s Seen
+	for _, c := range []struct {
+		session string
+		seq     uint64
+		want    bool
+	}{
+		{"a", 1, true},
+		{"a", 1, false},
+		{"a", 3, true},
+		{"a", 2, true},
+		{"a", 3, false},
+		{"a", 2, false},
+		{"b", 1, true}, // 다시 시작한 클라이언트
+	} {
+		if got := s.Add(&Batch{ClientID: "client-1", Session: c.session, Seq: c.seq}); got != c.want {
+			t.Errorf("Add(%s, %d) = %v, want %v", c.session, c.seq, got, c.want)
+		}
+	}
+}
+
+func TestReadBatch(t *testing.T) {
+	for _, c := range []struct {
+		body string
+		ok   bool
+	}{
+		{`{"client_id":"client-1","session":"a","seq":1,"records":[{"latency":1000}]}`, true},
+		{`{"session":"a","seq":1}`, false},
+		{`{"client_id":"client-1","seq":1}`, false},
+		{`{"client_id":"client-1","session":"a"}`, false},
+	} {
+		r := httptest.NewRequest(http.MethodPost, BatchPath, strings.NewReader(c.body))
+		if _, err := ReadBatch(r); (err == nil) != c.ok {
+			t.Errorf("ReadBatch(%s) error = %v, want ok %v", c.body, err, c.ok)
+		}
+	}
+}
+
+func TestBatchURL(t *testing.T) {
+	got, err := BatchURL("http://aggregator:8080/record-latency")
+	if err != nil || got != "http://aggregator:8080/record-batch" {
+		t.Errorf("BatchURL = %q, %v", got, err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	var path, clientID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ClientID string `json:"client_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		path, clientID = r.URL.Path, body.ClientID
+		if r.URL.Path != "/runs/batch-4/clients" {
+			http.Error(w, "run not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if err := Register(context.Background(), server.URL+"/record-latency", "batch-4", "client-1"); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/runs/batch-4/clients" || clientID != "client-1" {
+		t.Errorf("registered %s at %s", clientID, path)
+	}
+	if err := Register(context.Background(), server.URL+"/record-latency", "missing", "client-1"); err == nil {
+		t.Error("Register succeeded for a missing run")
+	}
+}
@@ -0,0 +1,384 @@
+// Package config 는 모든 바이너리가 공유하는 설정을 읽는다.
+//
+// 우선순위는 기본값 < 설정 파일 (YAML/JSON) < 환경 변수 < 명령행 플래그 순이다.
+// 설정 파일은 -config 플래그나 CONFIG_FILE 환경 변수로 지정한다.
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/loadgen"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/retry"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"gopkg.in/yaml.v3"
+)
+
+// Config 설정 파일의 구조. JSON 은 YAML 의 부분집합이므로 같은 파서로 읽는다.
+type Config struct {
+	Server     ServerConfig     `yaml:"server"`
+	Model      ModelConfig      `yaml:"model"`
+	Load       LoadConfig       `yaml:"load"`
+	Aggregator AggregatorConfig `yaml:"aggregator"`
+	Watch      WatchConfig      `yaml:"watch"`
+}
+
+// ServerConfig Triton 서버 접속 설정
+type ServerConfig struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout"`
+	TLS     TLSConfig     `yaml:"tls"`
+	Retry   RetryConfig   `yaml:"retry"`
+}
+
+// RetryConfig 실패한 gRPC 호출을 다시 시도하는 방법. RetryPolicy 로 retry.Policy 를 만든다.
+type RetryConfig struct {
+	// MaxAttempts 첫 시도를 포함한 최대 시도 수. 1 이면 다시 시도하지 않는다.
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	Jitter         float64       `yaml:"jitter"`
+	// AttemptTimeout 시도 하나의 제한 시간. 0 이면 Server.Timeout 만 적용된다.
+	AttemptTimeout time.Duration `yaml:"attempt_timeout"`
+	// Codes 다시 시도할 gRPC 상태 코드 이름 (예: Unavailable)
+	Codes []string `yaml:"codes"`
+	// Infer 가 false 이면 부하 테스트 (LoadRetryPolicy) 는 ModelInfer 를 다시 시도하지 않는다.
+	Infer bool `yaml:"infer"`
+}
+
+// TLSConfig Triton gRPC 접속에 쓸 TLS 설정. Enabled 가 false 이면 평문으로 접속한다.
+type TLSConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// ModelConfig 호출할 모델 설정
+type ModelConfig struct {
+	Name      string `yaml:"name"`
+	Version   string `yaml:"version"`
+	BatchSize int    `yaml:"batch_size"`
+}
+
+// 부하 생성 방식
+const (
+	// ClosedLoop 클라이언트마다 이전 응답을 받은 뒤 다음 요청을 보낸다.
+	ClosedLoop = "closed"
+	// OpenLoop 응답과 상관없이 Rate 에 맞춰 요청을 보낸다.
+	OpenLoop = "open"
+)
+
+// LoadConfig 부하 테스트 클라이언트 설정
+type LoadConfig struct {
+	Mode         string        `yaml:"mode"`
+	Rate         float64       `yaml:"rate"`
+	MaxInFlight  int           `yaml:"max_in_flight"`
+	Concurrency  int           `yaml:"concurrency"`
+	Duration     time.Duration `yaml:"duration"`
+	ImagePath    string        `yaml:"image"`
+	ClientID     string        `yaml:"client_id"`
+	CollectorURL string        `yaml:"collector_url"`
+	// RunID 가 있으면 집계 서버의 이 실행에 등록하고 기록을 넣는다.
+	RunID string `yaml:"run_id"`
+	// FlushInterval 결과를 모아 이 주기로 집계 서버에 배치로 보낸다. 0 이면 요청마다 바로 보낸다.
+	FlushInterval time.Duration `yaml:"flush_interval"`
+	// Stages 가 있으면 Duration, Rate, Concurrency 대신 구간별 값으로 부하를 준다.
+	Stages loadgen.Profile `yaml:"stages"`
+}
+
+// AggregatorConfig 레이턴시 집계 서버 설정
+type AggregatorConfig struct {
+	Listen string `yaml:"listen"`
+	// TotalRequests 성공과 실패를 합쳐 이만큼 기록되면 집계를 끝낸다.
+	TotalRequests int `yaml:"total_requests"`
+	// Duration 첫 기록부터 이만큼 지나면 TotalRequests 에 못 미쳐도 집계를 끝낸다. 0 이면 쓰지 않는다.
+	Duration  time.Duration `yaml:"duration"`
+	ReportDir string        `yaml:"report_dir"`
+	// DBPath 가 있으면 끝난 실행의 보고서를 이 bbolt 데이터베이스에도 보관한다.
+	DBPath       string        `yaml:"db_path"`
+	Window       time.Duration `yaml:"window"`
+	LiveInterval time.Duration `yaml:"live_interval"`
+}
+
+// WatchConfig health watch 설정. 상태가 NOT_SERVING 으로 바뀌면 Webhooks 에 POST 하고 Command 를 실행한다.
+type WatchConfig struct {
+	// Interval ServerReady/ModelReady 를 확인하는 주기
+	Interval time.Duration `yaml:"interval"`
+	Webhooks []string      `yaml:"webhooks"`
+	// Command sh -c 로 실행한다. 상태는 HEALTH_* 환경 변수와 표준 입력의 JSON 으로 받는다.
+	Command string `yaml:"command"`
+	// OnRecover 가 true 면 SERVING 으로 돌아올 때도 알린다.
+	OnRecover bool `yaml:"on_recover"`
+}
+
+// Default 기본 설정. 서버 주소는 로컬 Triton (docker_run.sh) 을 가리킨다.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			URL:     "localhost:8001",
+			Timeout: 10 * time.Second,
+			Retry: RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: 100 * time.Millisecond,
+				MaxBackoff:     2 * time.Second,
+				Jitter:         0.2,
+				Codes:          []string{"Unavailable", "ResourceExhausted", "Aborted"},
+			},
+		},
+		Model: ModelConfig{
+			Name:      "vitpose_ensemble",
+			BatchSize: 4,
+		},
+		Load: LoadConfig{
+			Mode:          ClosedLoop,
+			Concurrency:   1,
+			Duration:      60 * time.Second,
+			CollectorURL:  "http://aggregator:8080/record-latency",
+			FlushInterval: time.Second,
+		},
+		Aggregator: AggregatorConfig{
+			Listen:       ":8080",
+			Window:       10 * time.Second,
+			LiveInterval: 5 * time.Second,
+		},
+		Watch: WatchConfig{
+			Interval: 5 * time.Second,
+		},
+	}
+}
+
+// LoadFile path 의 YAML/JSON 파일을 c 위에 덮어쓴다. 파일에 없는 항목은 그대로 둔다.
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("config: parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load 기본값에 설정 파일, 환경 변수, args 의 플래그를 차례로 적용한 설정을 만든다.
+// groups 에 해당하는 항목만 플래그로 등록한다.
+func Load(fs *flag.FlagSet, args []string, groups ...Group) (*Config, error) {
+	c := Default()
+	if err := c.Parse(fs, args, groups...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Parse c 를 기본값으로 보고 설정 파일, 환경 변수, args 의 플래그를 차례로 적용한다.
+// 바이너리마다 기본값을 바꿔야 할 때 Default() 를 고친 뒤 호출한다.
+func (c *Config) Parse(fs *flag.FlagSet, args []string, groups ...Group) error {
+	configPath := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML/JSON config file. Env: CONFIG_FILE")
+	setFlags := make(map[string]string)
+	for _, f := range fields {
+		if !f.in(groups) {
+			continue
+		}
+		f := f
+		usage := fmt.Sprintf("%s Env: %s", f.usage, f.env)
+		if f.isBool {
+			fs.BoolFunc(f.flag, usage, func(v string) error { setFlags[f.flag] = v; return nil })
+		} else {
+			fs.Func(f.flag, usage, func(v string) error { setFlags[f.flag] = v; return nil })
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *configPath != "" {
+		if err := c.LoadFile(*configPath); err != nil {
+			return err
+		}
+	}
+	for _, f := range fields {
+		if v, ok := os.LookupEnv(f.env); ok && v != "" {
+			if err := f.set(c, v); err != nil {
+				return fmt.Errorf("config: %s=%q: %w", f.env, v, err)
+			}
+		}
+	}
+	for _, f := range fields {
+		if v, ok := setFlags[f.flag]; ok {
+			if err := f.set(c, v); err != nil {
+				return fmt.Errorf("config: -%s=%q: %w", f.flag, v, err)
+			}
+		}
+	}
+	return c.validate()
+}
+
+// MustLoad 명령행 인자로 Load 를 호출하고, 실패하면 프로그램을 종료한다.
+func MustLoad(groups ...Group) *Config {
+	return Default().MustParse(groups...)
+}
+
+// MustParse 명령행 인자로 Parse 를 호출하고, 실패하면 프로그램을 종료한다.
+func (c *Config) MustParse(groups ...Group) *Config {
+	if err := c.Parse(flag.CommandLine, os.Args[1:], groups...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	return c
+}
+
+func (c *Config) validate() error {
+	if c.Server.URL == "" {
+		return fmt.Errorf("config: server url is empty")
+	}
+	if r := c.Server.Retry; r.MaxAttempts < 1 || r.InitialBackoff < 0 || r.MaxBackoff < 0 || r.AttemptTimeout < 0 {
+		return fmt.Errorf("config: invalid retry policy %+v", r)
+	}
+	if j := c.Server.Retry.Jitter; j < 0 || j > 1 {
+		return fmt.Errorf("config: retry jitter %v is not between 0 and 1", j)
+	}
+	for _, name := range c.Server.Retry.Codes {
+		if _, err := retry.ParseCode(name); err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+	}
+	if c.Model.BatchSize <= 0 {
+		return fmt.Errorf("config: invalid batch size %d", c.Model.BatchSize)
+	}
+	switch c.Load.Mode {
+	case ClosedLoop:
+	case OpenLoop:
+		if c.Load.Rate <= 0 && len(c.Load.Stages) == 0 {
+			return fmt.Errorf("config: open-loop mode needs a positive rate, got %v", c.Load.Rate)
+		}
+	default:
+		return fmt.Errorf("config: unknown load mode %q", c.Load.Mode)
+	}
+	if len(c.Load.Stages) > 0 {
+		if err := c.Load.Stages.Validate(); err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+	}
+	if c.Load.MaxInFlight < 0 {
+		return fmt.Errorf("config: invalid max in-flight requests %d", c.Load.MaxInFlight)
+	}
+	if c.Load.Concurrency <= 0 {
+		return fmt.Errorf("config: invalid concurrency %d", c.Load.Concurrency)
+	}
+	if c.Load.Duration <= 0 {
+		return fmt.Errorf("config: invalid test duration %v", c.Load.Duration)
+	}
+	if c.Load.FlushInterval < 0 {
+		return fmt.Errorf("config: invalid flush interval %v", c.Load.FlushInterval)
+	}
+	if c.Aggregator.TotalRequests < 0 {
+		return fmt.Errorf("config: invalid total requests %d", c.Aggregator.TotalRequests)
+	}
+	if c.Aggregator.Duration < 0 {
+		return fmt.Errorf("config: invalid collection duration %v", c.Aggregator.Duration)
+	}
+	if c.Aggregator.Window <= 0 {
+		return fmt.Errorf("config: invalid report window %v", c.Aggregator.Window)
+	}
+	if c.Watch.Interval <= 0 {
+		return fmt.Errorf("config: invalid watch interval %v", c.Watch.Interval)
+	}
+	return nil
+}
+
+// DialOptions Server.TLS 에 맞는 전송 계층 옵션
+func (c *Config) DialOptions() ([]grpc.DialOption, error) {
+	if !c.Server.TLS.Enabled {
+		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, nil
+	}
+	tlsConfig := &tls.Config{
+		ServerName:         c.Server.TLS.ServerName,
+		InsecureSkipVerify: c.Server.TLS.InsecureSkipVerify,
+	}
+	if c.Server.TLS.CAFile != "" {
+		pem, err := os.ReadFile(c.Server.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("config: no certificates found in %s", c.Server.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.Server.TLS.CertFile != "" || c.Server.TLS.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.Server.TLS.CertFile, c.Server.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}, nil
+}
+
+// Dial Server.URL 로 gRPC 커넥션을 만든다.
+func (c *Config) Dial(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	dialOpts, err := c.DialOptions()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(c.Server.URL, append(dialOpts, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't connect to endpoint %s: %w", c.Server.URL, err)
+	}
+	return conn, nil
+}
+
+// RetryPolicy Server.Retry 를 retry.Policy 로 바꾼다. 전체 제한 시간은 Server.Timeout 이고 새 Metrics 가 달린다.
+// Dial 에 DialOption 으로 넘기면 그 커넥션의 모든 unary 호출에 적용된다.
+func (c *Config) RetryPolicy() *retry.Policy {
+	r := c.Server.Retry
+	p := &retry.Policy{
+		MaxAttempts:    r.MaxAttempts,
+		InitialBackoff: r.InitialBackoff,
+		MaxBackoff:     r.MaxBackoff,
+		Multiplier:     2,
+		Jitter:         r.Jitter,
+		AttemptTimeout: r.AttemptTimeout,
+		Timeout:        c.Server.Timeout,
+		Codes:          []codes.Code{},
+		Metrics:        retry.NewMetrics(),
+	}
+	for _, name := range r.Codes {
+		// validate 에서 이미 확인했다.
+		if code, err := retry.ParseCode(name); err == nil {
+			p.Codes = append(p.Codes, code)
+		}
+	}
+	return p
+}
+
+// LoadRetryPolicy 부하 테스트용 RetryPolicy. ModelInfer 를 다시 시도하면 레이턴시에 기다린 시간이 섞이고
+// 살아난 요청이 성공으로 세어져 재시도 설정에 따라 보고서의 p99 와 실패율이 달라지므로,
+// Server.Retry.Infer 가 아니면 ModelInfer 는 한 번만 보내고 헬스·저장소 같은 다른 호출만 다시 시도한다.
+func (c *Config) LoadRetryPolicy() *retry.Policy {
+	p := c.RetryPolicy()
+	if !c.Server.Retry.Infer {
+		p.Skip = []string{"ModelInfer"}
+	}
+	return p
+}
+
+// VitposeOptions Model 과 Server.Timeout 을 vitpose.Client 옵션으로 바꾼다.
+func (c *Config) VitposeOptions() []vitpose.Option {
+	return []vitpose.Option{
+		vitpose.WithModelName(c.Model.Name),
+		vitpose.WithModelVersion(c.Model.Version),
+		vitpose.WithTimeout(c.Server.Timeout),
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "staging.yaml")
+	data := []byte("server:\n  url: staging:8001\n  timeout: 3s\nmodel:\n  name: vitpose\n  batch_size: 8\n")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("MODEL_NAME", "vitpose_ensemble")
+	t.Setenv("TEST_DURATION", "10")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg, err := Load(fs, []string{"-b", "2", "-tls"}, ServerFlags, ModelFlags, LoadFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.URL != "staging:8001" || cfg.Server.Timeout != 3*time.Second {
+		t.Errorf("server = %+v, want values from the config file", cfg.Server)
+	}
+	if cfg.Model.Name != "vitpose_ensemble" {
+		t.Errorf("model name = %q, want env to override the file", cfg.Model.Name)
+	}
+	if cfg.Model.BatchSize != 2 {
+		t.Errorf("batch size = %d, want flag to override the file", cfg.Model.BatchSize)
+	}
+	if cfg.Load.Duration != 10*time.Second {
+		t.Errorf("duration = %v, want TEST_DURATION in seconds", cfg.Load.Duration)
+	}
+	if !cfg.Server.TLS.Enabled {
+		t.Error("-tls did not enable TLS")
+	}
+}
+
+func TestStages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ramp.yaml")
+	data := []byte("load:\n  mode: open\n  stages:\n    - {name: warmup, duration: 30s, value: 10}\n    - {name: ramp, duration: 2m, from: 10, value: 100, ramp: true}\n")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg, err := Load(fs, []string{"-config", path}, LoadFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Load.Stages) != 2 || cfg.Load.Stages[1].Duration != 2*time.Minute || !cfg.Load.Stages[1].Ramp {
+		t.Errorf("stages = %+v, want warmup and a 2m ramp", cfg.Load.Stages)
+	}
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg, err = Load(fs, []string{"-config", path, "-profile", "soak:50:1h"}, LoadFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Load.Stages) != 1 || cfg.Load.Stages[0].Name != "soak" {
+		t.Errorf("stages = %+v, want -profile to replace the file's stages", cfg.Load.Stages)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if _, err := Load(fs, []string{"-listen", ":9090"}, ServerFlags); err == nil {
+		t.Error("Load accepted a flag from a group that was not requested")
+	}
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	if _, err := Load(fs, []string{"-b", "0"}, ModelFlags); err == nil {
+		t.Error("Load accepted batch size 0")
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Setenv("RETRY_CODES", "Unavailable, DEADLINE_EXCEEDED")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg, err := Load(fs, []string{"-retry-attempts", "5", "-attempt-timeout", "2s", "-timeout", "30s"}, ServerFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := cfg.RetryPolicy()
+	if p.MaxAttempts != 5 || p.AttemptTimeout != 2*time.Second || p.Timeout != 30*time.Second ||
+		p.InitialBackoff != 100*time.Millisecond || len(p.Codes) != 2 || p.Codes[1] != codes.DeadlineExceeded || p.Metrics == nil {
+		t.Errorf("policy = %+v", p)
+	}
+	// 부하 테스트는 RETRY_INFER 가 없으면 ModelInfer 를 다시 시도하지 않는다.
+	if p := cfg.LoadRetryPolicy(); !slices.Equal(p.Skip, []string{"ModelInfer"}) || p.MaxAttempts != 5 {
+		t.Errorf("load policy = %+v, want ModelInfer skipped", p)
+	}
+	cfg.Server.Retry.Infer = true
+	if p := cfg.LoadRetryPolicy(); p.Skip != nil {
+		t.Errorf("load policy with RETRY_INFER skips %v", p.Skip)
+	}
+
+	for _, args := range [][]string{{"-retry-attempts", "0"}, {"-retry-jitter", "1.5"}, {"-retry-codes", "Sometimes"}} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		if _, err := Load(fs, args, ServerFlags); err == nil {
+			t.Errorf("Load accepted %q", args)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/loadgen"
+)
+
+// Group 플래그로 등록할 설정 묶음
+type Group int
+
+const (
+	// ServerFlags 서버 주소, 타임아웃, TLS
+	ServerFlags Group = iota
+	// ModelFlags 모델 이름, 버전, 배치 크기
+	ModelFlags
+	// LoadFlags 부하 테스트 동시성, 시간, 이미지, 집계 서버
+	LoadFlags
+	// AggregatorFlags 집계 서버 주소와 총 요청 수
+	AggregatorFlags
+	// WatchFlags health watch 의 확인 주기와 알림
+	WatchFlags
+)
+
+// field 설정 항목 하나의 플래그 이름, 환경 변수 이름, 값 설정 함수
+type field struct {
+	group  Group
+	flag   string
+	env    string
+	usage  string
+	isBool bool
+	set    func(c *Config, v string) error
+}
+
+func (f field) in(groups []Group) bool {
+	for _, g := range groups {
+		if g == f.group {
+			return true
+		}
+	}
+	return false
+}
+
+var fields = []field{
+	{group: ServerFlags, flag: "u", env: "TRITON_URL", usage: "Inference Server URL. Default: localhost:8001.",
+		set: func(c *Config, v string) error { c.Server.URL = v; return nil }},
+	{group: ServerFlags, flag: "timeout", env: "REQUEST_TIMEOUT", usage: "Per-request timeout, e.g. 10s.",
+		set: func(c *Config, v string) error { return setDuration(&c.Server.Timeout, v) }},
+	{group: ServerFlags, flag: "tls", env: "TLS_ENABLED", usage: "Connect with TLS.", isBool: true,
+		set: func(c *Config, v string) error { return setBool(&c.Server.TLS.Enabled, v) }},
+	{group: ServerFlags, flag: "tls-ca", env: "TLS_CA_FILE", usage: "PEM file with CA certificates to trust.",
+		set: func(c *Config, v string) error { c.Server.TLS.CAFile = v; return nil }},
+	{group: ServerFlags, flag: "tls-cert", env: "TLS_CERT_FILE", usage: "PEM client certificate for mutual TLS.",
+		set: func(c *Config, v string) error { c.Server.TLS.CertFile = v; return nil }},
+	{group: ServerFlags, flag: "tls-key", env: "TLS_KEY_FILE", usage: "PEM client key for mutual TLS.",
+		set: func(c *Config, v string) error { c.Server.TLS.KeyFile = v; return nil }},
+	{group: ServerFlags, flag: "tls-server-name", env: "TLS_SERVER_NAME", usage: "Server name to verify the certificate against.",
+		set: func(c *Config, v string) error { c.Server.TLS.ServerName = v; return nil }},
+	{group: ServerFlags, flag: "tls-insecure", env: "TLS_INSECURE_SKIP_VERIFY", usage: "Skip server certificate verification.", isBool: true,
+		set: func(c *Config, v string) error { return setBool(&c.Server.TLS.InsecureSkipVerify, v) }},
+	{group: ServerFlags, flag: "retry-attempts", env: "RETRY_MAX_ATTEMPTS", usage: "Maximum attempts per gRPC call, including the first. 1 disables retries. Default: 3.",
+		set: func(c *Config, v string) error { return setInt(&c.Server.Retry.MaxAttempts, v) }},
+	{group: ServerFlags, flag: "retry-backoff", env: "RETRY_BACKOFF", usage: "Wait before the first retry; doubles on each retry. Default: 100ms.",
+		set: func(c *Config, v string) error { return setDuration(&c.Server.Retry.InitialBackoff, v) }},
+	{group: ServerFlags, flag: "retry-max-backoff", env: "RETRY_MAX_BACKOFF", usage: "Longest wait between retries. Default: 2s.",
+		set: func(c *Config, v string) error { return setDuration(&c.Server.Retry.MaxBackoff, v) }},
+	{group: ServerFlags, flag: "retry-jitter", env: "RETRY_JITTER", usage: "Randomize each wait by up to this fraction (0-1). Default: 0.2.",
+		set: func(c *Config, v string) error { return setFloat(&c.Server.Retry.Jitter, v) }},
+	{group: ServerFlags, flag: "attempt-timeout", env: "RETRY_ATTEMPT_TIMEOUT", usage: "Timeout of a single attempt; a timed-out attempt is retried. Default: none.",
+		set: func(c *Config, v string) error { return setDuration(&c.Server.Retry.AttemptTimeout, v) }},
+	{group: ServerFlags, flag: "retry-codes", env: "RETRY_CODES", usage: "Comma-separated gRPC codes to retry. Default: Unavailable,ResourceExhausted,Aborted.",
+		set: func(c *Config, v string) error { c.Server.Retry.Codes = splitList(v); return nil }},
+	{group: ServerFlags, flag: "retry-infer", env: "RETRY_INFER", usage: "Also retry ModelInfer in load tests; retried requests then include the backoff in their latency. Default: false.",
+		set: func(c *Config, v string) error { return setBool(&c.Server.Retry.Infer, v) }},
+
+	{group: ModelFlags, flag: "m", env: "MODEL_NAME", usage: "Name of model being served. Default: vitpose_ensemble.",
+		set: func(c *Config, v string) error { c.Model.Name = v; return nil }},
+	{group: ModelFlags, flag: "x", env: "MODEL_VERSION", usage: "Version of model. Default: Latest Version.",
+		set: func(c *Config, v string) error { c.Model.Version = v; return nil }},
+	{group: ModelFlags, flag: "b", env: "BATCH_SIZE", usage: "Batch size. Default: 4.",
+		set: func(c *Config, v string) error { return setInt(&c.Model.BatchSize, v) }},
+
+	{group: LoadFlags, flag: "mode", env: "LOAD_MODE", usage: "closed: each client waits for its response. open: send at -rps regardless of completions.",
+		set: func(c *Config, v string) error { c.Load.Mode = v; return nil }},
+	{group: LoadFlags, flag: "rps", env: "TARGET_RPS", usage: "Target requests per second in open-loop mode.",
+		set: func(c *Config, v string) error { return setFloat(&c.Load.Rate, v) }},
+	{group: LoadFlags, flag: "max-in-flight", env: "MAX_IN_FLIGHT", usage: "Cap on concurrent requests in open-loop mode. 0 means no cap.",
+		set: func(c *Config, v string) error { return setInt(&c.Load.MaxInFlight, v) }},
+	{group: LoadFlags, flag: "profile", env: "LOAD_PROFILE", usage: "Load profile, e.g. ramp:0-100:5m, step:10+10*5:1m, spike:10-200:1m,10s,1m or soak:50:1h.",
+		set: func(c *Config, v string) (err error) { c.Load.Stages, err = loadgen.ParseProfile(v); return err }},
+	{group: LoadFlags, flag: "c", env: "CONCURRENCY", usage: "Number of simulated clients.",
+		set: func(c *Config, v string) error { return setInt(&c.Load.Concurrency, v) }},
+	{group: LoadFlags, flag: "d", env: "TEST_DURATION", usage: "Test duration, in seconds or e.g. 10m. Default: 60.",
+		set: func(c *Config, v string) error { return setDuration(&c.Load.Duration, v) }},
+	{group: LoadFlags, flag: "i", env: "IMAGE_PATH", usage: "JPEG/PNG image to send. Default: random noise.",
+		set: func(c *Config, v string) error { c.Load.ImagePath = v; return nil }},
+	{group: LoadFlags, flag: "id", env: "CLIENT_ID", usage: "Unique identifier for the client instance. Default: the hostname.",
+		set: func(c *Config, v string) error { c.Load.ClientID = v; return nil }},
+	{group: LoadFlags, flag: "collector", env: "COLLECTOR_URL", usage: "URL of the latency collector server.",
+		set: func(c *Config, v string) error { c.Load.CollectorURL = v; return nil }},
+	{group: LoadFlags, flag: "run", env: "RUN_ID", usage: "Aggregator run to register with and record into. Default: the most recently started run.",
+		set: func(c *Config, v string) error { c.Load.RunID = v; return nil }},
+	{group: LoadFlags, flag: "flush", env: "FLUSH_INTERVAL", usage: "Send results to the collector in compressed batches this often. 0 posts every result. Default: 1s.",
+		set: func(c *Config, v string) error { return setDuration(&c.Load.FlushInterval, v) }},
+
+	{group: AggregatorFlags, flag: "listen", env: "LISTEN_ADDR", usage: "Address to listen on. Default: :8080.",
+		set: func(c *Config, v string) error { c.Aggregator.Listen = v; return nil }},
+	{group: AggregatorFlags, flag: "total", env: "TOTAL_REQUESTS", usage: "Number of requests, including failures, to collect before showing results.",
+		set: func(c *Config, v string) error { return setInt(&c.Aggregator.TotalRequests, v) }},
+	{group: AggregatorFlags, flag: "collect-for", env: "COLLECT_DURATION", usage: "Stop collecting this long after the first record, even if -total isn't reached. Default: none.",
+		set: func(c *Config, v string) error { return setDuration(&c.Aggregator.Duration, v) }},
+	{group: AggregatorFlags, flag: "report-dir", env: "REPORT_DIR", usage: "Directory to write JSON, CSV and Markdown reports to. Default: none.",
+		set: func(c *Config, v string) error { c.Aggregator.ReportDir = v; return nil }},
+	{group: AggregatorFlags, flag: "db", env: "RESULTS_DB", usage: "Database file to keep finished runs in for the results tool. Default: none.",
+		set: func(c *Config, v string) error { c.Aggregator.DBPath = v; return nil }},
+	{group: AggregatorFlags, flag: "window", env: "REPORT_WINDOW", usage: "Length of the time windows in the reports. Doubles whenever a run would exceed 120 windows. Default: 10s.",
+		set: func(c *Config, v string) error { return setDuration(&c.Aggregator.Window, v) }},
+	{group: AggregatorFlags, flag: "live", env: "LIVE_INTERVAL", usage: "How often to print live 1s/10s/1m windows. 0 disables. Default: 5s.",
+		set: func(c *Config, v string) error { return setDuration(&c.Aggregator.LiveInterval, v) }},
+
+	{group: WatchFlags, flag: "interval", env: "WATCH_INTERVAL", usage: "How often to poll ServerReady and ModelReady. Default: 5s.",
+		set: func(c *Config, v string) error { return setDuration(&c.Watch.Interval, v) }},
+	{group: WatchFlags, flag: "webhook", env: "WATCH_WEBHOOKS", usage: "Comma-separated URLs to POST a JSON transition to when the server stops serving.",
+		set: func(c *Config, v string) error { c.Watch.Webhooks = splitList(v); return nil }},
+	{group: WatchFlags, flag: "exec", env: "WATCH_COMMAND", usage: "Shell command to run when the server stops serving. Gets HEALTH_* variables and the transition as JSON on stdin.",
+		set: func(c *Config, v string) error { c.Watch.Command = v; return nil }},
+	{group: WatchFlags, flag: "on-recover", env: "WATCH_ON_RECOVER", usage: "Also notify when the server is serving again.", isBool: true,
+		set: func(c *Config, v string) error { return setBool(&c.Watch.OnRecover, v) }},
+}
+
+// splitList 쉼표로 나눈 목록. 빈 항목은 버린다.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func setInt(dst *int, v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	*dst = n
+	return nil
+}
+
+func setFloat(dst *float64, v string) error {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return err
+	}
+	*dst = f
+	return nil
+}
+
+func setBool(dst *bool, v string) error {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	*dst = b
+	return nil
+}
+
+// setDuration "10s" 같은 기간 문자열 또는 초 단위 정수 (TEST_DURATION 과의 호환)
+func setDuration(dst *time.Duration, v string) error {
+	if sec, err := strconv.Atoi(v); err == nil {
+		*dst = time.Duration(sec) * time.Second
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+	*dst = d
+	return nil
+}
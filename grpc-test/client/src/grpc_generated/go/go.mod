@@ -3,8 +3,10 @@ module github.com/triton-inference-server/client/src/grpc_generated/go
 go 1.22.3
 
 require (
+	go.etcd.io/bbolt v1.3.10
 	google.golang.org/grpc v1.64.0
 	google.golang.org/protobuf v1.34.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
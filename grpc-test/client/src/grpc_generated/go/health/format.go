@@ -0,0 +1,71 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteJSON r 을 들여쓴 JSON 한 덩어리로 쓴다.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText 사람이 읽는 형식으로 쓴다. 첫 줄에 전체 결과와 종료 코드가 나온다.
+func (r *Report) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%s: %s (exit %d)\n", r.Server, strings.ToUpper(strings.ReplaceAll(r.Status.String(), "_", " ")), r.ExitCode)
+	if r.Error != "" {
+		fmt.Fprintf(w, "  error:    %s\n", r.Error)
+	}
+	fmt.Fprintf(w, "  live:     %s\n", yesNo(r.Live))
+	fmt.Fprintf(w, "  ready:    %s\n", yesNo(r.Ready))
+	if r.Health != "" {
+		fmt.Fprintf(w, "  health:   %s\n", r.Health)
+	}
+	if md := r.Metadata; md != nil {
+		fmt.Fprintf(w, "  server:   %s %s\n", md.Name, md.Version)
+		if len(md.Extensions) > 0 {
+			fmt.Fprintf(w, "  extensions: %s\n", strings.Join(md.Extensions, ", "))
+		}
+	}
+	for _, m := range r.Models {
+		indent := "  "
+		kind := "model"
+		if m.Ensemble != "" {
+			indent, kind = "    ", "step"
+		}
+		name := m.Name
+		if m.Version != "" {
+			name += ":" + m.Version
+		}
+		state := "ready"
+		if !m.Ready {
+			state = "NOT READY"
+		}
+		fmt.Fprintf(w, "%s%s %s: %s", indent, kind, name, state)
+		if m.Platform != "" {
+			fmt.Fprintf(w, " (%s, versions %s)", m.Platform, strings.Join(m.Versions, ","))
+		}
+		fmt.Fprintln(w)
+		if m.Error != "" {
+			fmt.Fprintf(w, "%s  error: %s\n", indent, m.Error)
+		}
+		for _, t := range m.Inputs {
+			fmt.Fprintf(w, "%s  input  %s %s %v\n", indent, t.Name, t.Datatype, t.Shape)
+		}
+		for _, t := range m.Outputs {
+			fmt.Fprintf(w, "%s  output %s %s %v\n", indent, t.Name, t.Datatype, t.Shape)
+		}
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
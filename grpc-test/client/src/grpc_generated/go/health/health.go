@@ -0,0 +1,248 @@
+// Package health 는 Triton 서버와 모델이 추론 요청을 받을 수 있는지 한 번에 확인한다.
+//
+// ServerLive, ServerReady, grpc.health.v1 Check, ServerMetadata 로 서버를 보고, 모델은 ModelReady 와
+// ModelMetadata 로 본다. 앙상블이면 ModelConfig 의 ensemble_scheduling 에서 단계 (vitpose, postprocess 등) 를
+// 찾아 단계마다 같은 확인을 한다. 결과의 Status 는 가장 심각한 실패이고 그대로 종료 코드로 쓴다.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+)
+
+// Status 확인 결과. 값은 종료 코드이고 작을수록 심각하다 (OK 제외). 2 는 잘못된 사용에 남겨 둔다.
+type Status int
+
+const (
+	OK Status = 0
+	// Unreachable 서버에 접속하지 못했거나 ServerLive 가 실패했다.
+	Unreachable Status = 1
+	// NotLive 서버가 live 가 아니다.
+	NotLive Status = 3
+	// NotReady 서버가 ready 가 아니다.
+	NotReady Status = 4
+	// ModelNotReady 확인할 모델이 ready 가 아니다.
+	ModelNotReady Status = 5
+	// StepNotReady 모델은 ready 이지만 앙상블 단계 가운데 ready 가 아닌 것이 있다.
+	StepNotReady Status = 6
+	// MetadataFailed 모두 ready 이지만 서버나 모델의 메타데이터를 읽지 못했다.
+	MetadataFailed Status = 7
+)
+
+var statusNames = map[Status]string{
+	OK:             "ok",
+	Unreachable:    "unreachable",
+	NotLive:        "not_live",
+	NotReady:       "not_ready",
+	ModelNotReady:  "model_not_ready",
+	StepNotReady:   "step_not_ready",
+	MetadataFailed: "metadata_failed",
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return "status_" + strconv.Itoa(int(s))
+}
+
+// MarshalText JSON 에서 이름으로 쓴다.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText MarshalText 의 반대. Transition 을 받는 웹훅이 그대로 읽을 수 있다.
+func (s *Status) UnmarshalText(text []byte) error {
+	for status, name := range statusNames {
+		if name == string(text) {
+			*s = status
+			return nil
+		}
+	}
+	return fmt.Errorf("health: unknown status %q", text)
+}
+
+// Report 한 번 확인한 결과
+type Report struct {
+	Server string `json:"server"`
+	Status Status `json:"status"`
+	// ExitCode Status 의 숫자 값. 스크립트에서 status 이름 대신 쓸 수 있다.
+	ExitCode int  `json:"exit_code"`
+	Live     bool `json:"live"`
+	Ready    bool `json:"ready"`
+	// Health grpc.health.v1 Check 의 응답 (SERVING 등). 서버가 지원하지 않으면 비어 있다.
+	Health   string      `json:"health,omitempty"`
+	Metadata *ServerInfo `json:"metadata,omitempty"`
+	Models   []Model     `json:"models,omitempty"`
+	// Error 첫 번째 실패의 설명
+	Error string `json:"error,omitempty"`
+}
+
+// ServerInfo ServerMetadata 응답
+type ServerInfo struct {
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// Model 모델 하나 또는 앙상블 단계 하나의 상태
+type Model struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Ensemble 앙상블 단계면 앙상블 모델 이름
+	Ensemble string   `json:"ensemble,omitempty"`
+	Ready    bool     `json:"ready"`
+	Platform string   `json:"platform,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+	Inputs   []Tensor `json:"inputs,omitempty"`
+	Outputs  []Tensor `json:"outputs,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Tensor 모델 입력이나 출력 하나
+type Tensor struct {
+	Name     string  `json:"name"`
+	Datatype string  `json:"datatype"`
+	Shape    []int64 `json:"shape"`
+}
+
+// fail 지금까지보다 심각한 실패면 Status 와 Error 를 바꾼다.
+func (r *Report) fail(s Status, err error) {
+	if r.Status == OK || s < r.Status {
+		r.Status, r.ExitCode = s, int(s)
+		r.Error = err.Error()
+	}
+}
+
+// Check conn 의 서버와 model 을 확인한다. model 이 비어 있으면 서버만 본다. version 이 비어 있으면 Triton 이 고르는 버전이다.
+// 실패는 에러 대신 Report 의 Status 와 Error 에 남는다.
+func Check(ctx context.Context, conn grpc.ClientConnInterface, server, model, version string) *Report {
+	client := triton.NewGRPCInferenceServiceClient(conn)
+	r := &Report{Server: server}
+
+	live, err := client.ServerLive(ctx, &triton.ServerLiveRequest{})
+	if err != nil {
+		r.fail(Unreachable, fmt.Errorf("server live: %w", err))
+		return r
+	}
+	r.Live = live.Live
+	if !r.Live {
+		r.fail(NotLive, errors.New("server is not live"))
+		return r
+	}
+
+	// Triton 은 grpc.health.v1 을 ServerReady 와 같은 뜻으로 구현한다. 없는 서버도 있으므로 실패로 보지 않는다.
+	if h, err := triton.NewHealthClient(conn).Check(ctx, &triton.HealthCheckRequest{}); err == nil {
+		r.Health = h.Status.String()
+	} else if status.Code(err) != codes.Unimplemented {
+		r.Health = status.Code(err).String()
+	}
+
+	ready, err := client.ServerReady(ctx, &triton.ServerReadyRequest{})
+	if err != nil {
+		r.fail(Unreachable, fmt.Errorf("server ready: %w", err))
+		return r
+	}
+	r.Ready = ready.Ready
+	if !r.Ready {
+		r.fail(NotReady, errors.New("server is not ready"))
+	}
+
+	if md, err := client.ServerMetadata(ctx, &triton.ServerMetadataRequest{}); err != nil {
+		r.fail(MetadataFailed, fmt.Errorf("server metadata: %w", err))
+	} else {
+		r.Metadata = &ServerInfo{Name: md.Name, Version: md.Version, Extensions: md.Extensions}
+	}
+
+	if model == "" {
+		return r
+	}
+	m := checkModel(ctx, client, model, version, "")
+	r.Models = append(r.Models, m)
+	if !m.Ready {
+		r.fail(ModelNotReady, fmt.Errorf("model %s is not ready%s", model, because(m.Error)))
+	} else if m.Error != "" {
+		r.fail(MetadataFailed, fmt.Errorf("model %s: %s", model, m.Error))
+	}
+
+	steps, err := ensembleSteps(ctx, client, model, version)
+	if err != nil {
+		// 모델이 없으면 설정도 없으므로 ModelNotReady 로 이미 알렸다.
+		if m.Ready {
+			r.fail(MetadataFailed, fmt.Errorf("model %s config: %w", model, err))
+		}
+		return r
+	}
+	for _, step := range steps {
+		s := checkModel(ctx, client, step.GetModelName(), stepVersion(step), model)
+		r.Models = append(r.Models, s)
+		if !s.Ready {
+			r.fail(StepNotReady, fmt.Errorf("ensemble step %s of %s is not ready%s", s.Name, model, because(s.Error)))
+		} else if s.Error != "" {
+			r.fail(MetadataFailed, fmt.Errorf("model %s: %s", s.Name, s.Error))
+		}
+	}
+	return r
+}
+
+// checkModel ModelReady 와 ModelMetadata 로 모델 하나를 본다. 메타데이터를 읽지 못하면 Error 에 남긴다.
+func checkModel(ctx context.Context, client triton.GRPCInferenceServiceClient, name, version, ensemble string) Model {
+	m := Model{Name: name, Version: version, Ensemble: ensemble}
+	ready, err := client.ModelReady(ctx, &triton.ModelReadyRequest{Name: name, Version: version})
+	if err != nil {
+		m.Error = "model ready: " + err.Error()
+		return m
+	}
+	m.Ready = ready.Ready
+	if !m.Ready {
+		return m
+	}
+	md, err := client.ModelMetadata(ctx, &triton.ModelMetadataRequest{Name: name, Version: version})
+	if err != nil {
+		m.Error = "model metadata: " + err.Error()
+		return m
+	}
+	m.Platform, m.Versions = md.Platform, md.Versions
+	m.Inputs, m.Outputs = tensors(md.Inputs), tensors(md.Outputs)
+	return m
+}
+
+// ensembleSteps model 이 앙상블이면 단계를 돌려준다. 앙상블이 아니면 nil 이다.
+func ensembleSteps(ctx context.Context, client triton.GRPCInferenceServiceClient, model, version string) ([]*triton.ModelEnsembling_Step, error) {
+	config, err := client.ModelConfig(ctx, &triton.ModelConfigRequest{Name: model, Version: version})
+	if err != nil {
+		return nil, err
+	}
+	return config.GetConfig().GetEnsembleScheduling().GetStep(), nil
+}
+
+// stepVersion 단계의 model_version. -1 (최신) 이면 빈 문자열이다.
+func stepVersion(step *triton.ModelEnsembling_Step) string {
+	if v := step.GetModelVersion(); v > 0 {
+		return strconv.FormatInt(v, 10)
+	}
+	return ""
+}
+
+func tensors(md []*triton.ModelMetadataResponse_TensorMetadata) []Tensor {
+	var ts []Tensor
+	for _, t := range md {
+		ts = append(ts, Tensor{Name: t.Name, Datatype: t.Datatype, Shape: t.Shape})
+	}
+	return ts
+}
+
+func because(msg string) string {
+	if msg == "" {
+		return ""
+	}
+	return ": " + msg
+}
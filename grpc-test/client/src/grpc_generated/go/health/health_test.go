@@ -0,0 +1,98 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+	"google.golang.org/grpc"
+)
+
+func startServer(t *testing.T) (*tritontest.Server, *grpc.ClientConn) {
+	t.Helper()
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository("../../../../../../pose_model_zoo"); err != nil {
+		t.Fatal(err)
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return server, conn
+}
+
+func TestCheck(t *testing.T) {
+	server, conn := startServer(t)
+	ctx := context.Background()
+
+	r := Check(ctx, conn, "bufconn", "vitpose_ensemble", "")
+	if r.Status != OK || !r.Live || !r.Ready || r.Health != "SERVING" || r.Metadata == nil || r.Metadata.Version != "2.46.0" {
+		t.Fatalf("report = %+v, want a healthy server", r)
+	}
+	if len(r.Models) != 3 || r.Models[1].Name != "vitpose" || r.Models[1].Ensemble != "vitpose_ensemble" ||
+		r.Models[1].Version != "1" || r.Models[2].Name != "postprocess" {
+		t.Fatalf("models = %+v, want the ensemble and its two steps", r.Models)
+	}
+	if ens := r.Models[0]; ens.Platform != "ensemble" || len(ens.Inputs) != 3 || ens.Outputs[0].Name != "post_output" {
+		t.Errorf("ensemble metadata = %+v", ens)
+	}
+
+	tests := []struct {
+		name  string
+		setup func()
+		model string
+		want  Status
+	}{
+		{"step", func() { server.SetModelReady("postprocess", false) }, "vitpose_ensemble", StepNotReady},
+		{"model", func() { server.SetModelReady("vitpose_ensemble", false) }, "vitpose_ensemble", ModelNotReady},
+		{"unknown model", func() {}, "resnet", ModelNotReady},
+		{"server only", func() {}, "", OK},
+		{"server", func() { server.SetReady(false) }, "vitpose_ensemble", NotReady},
+		{"live", func() { server.SetLive(false) }, "vitpose_ensemble", NotLive},
+	}
+	for _, tt := range tests {
+		tt.setup()
+		if r := Check(ctx, conn, "bufconn", tt.model, ""); r.Status != tt.want || r.ExitCode != int(tt.want) {
+			t.Errorf("%s: status = %v (%s), want %v", tt.name, r.Status, r.Error, tt.want)
+		}
+	}
+}
+
+func TestCheckUnreachable(t *testing.T) {
+	server, conn := startServer(t)
+	server.Close()
+	r := Check(context.Background(), conn, "bufconn", "vitpose_ensemble", "")
+	if r.Status != Unreachable || r.Error == "" {
+		t.Errorf("report = %+v, want unreachable", r)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	server, conn := startServer(t)
+	server.SetModelReady("vitpose", false)
+	r := Check(context.Background(), conn, "localhost:8001", "vitpose_ensemble", "")
+
+	var text bytes.Buffer
+	r.WriteText(&text)
+	for _, want := range []string{"localhost:8001: STEP NOT READY (exit 6)", "server:   tritontest 2.46.0", "    step vitpose:1: NOT READY", "  input  input FP32 [-1 3 256 192]"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text does not contain %q:\n%s", want, text.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	r.WriteJSON(&buf)
+	var decoded struct {
+		Status   string `json:"status"`
+		ExitCode int    `json:"exit_code"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Status != "step_not_ready" || decoded.ExitCode != 6 {
+		t.Errorf("json = %s", buf.String())
+	}
+}
@@ -0,0 +1,121 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Hook 상태 변화를 바깥에 알린다.
+type Hook interface {
+	Notify(ctx context.Context, t Transition) error
+}
+
+// Webhook URL 에 Transition 을 JSON 으로 POST 한다. 2xx 가 아닌 응답은 실패다.
+type Webhook struct {
+	URL string
+	// Client nil 이면 http.DefaultClient
+	Client *http.Client
+}
+
+// Notify implements Hook.
+func (h Webhook) Notify(ctx context.Context, t Transition) error {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("health: webhook %s: %w", h.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("health: webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("health: webhook %s: %s", h.URL, resp.Status)
+	}
+	return nil
+}
+
+// Command sh -c 로 실행하는 명령. Transition 은 표준 입력의 JSON 과 HEALTH_STATE, HEALTH_PREVIOUS,
+// HEALTH_STATUS, HEALTH_REASON, HEALTH_SERVER, HEALTH_MODEL, HEALTH_AT, HEALTH_DOWNTIME 환경 변수로 받는다.
+type Command string
+
+// Notify implements Hook. 명령의 출력은 실패했을 때 에러에 붙는다.
+func (c Command) Notify(ctx context.Context, t Transition) error {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", string(c))
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"HEALTH_STATE="+string(t.To),
+		"HEALTH_PREVIOUS="+string(t.From),
+		"HEALTH_STATUS="+t.Status.String(),
+		"HEALTH_REASON="+t.Reason,
+		"HEALTH_SERVER="+t.Server,
+		"HEALTH_MODEL="+t.Model,
+		"HEALTH_AT="+t.At.UTC().Format(time.RFC3339),
+		"HEALTH_DOWNTIME="+t.Downtime.String(),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("health: command %q: %w: %s", string(c), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Alerter Watcher.OnTransition 으로 쓴다. NOT_SERVING 이 되면 (OnRecover 면 다시 SERVING 이 될 때도)
+// 모든 Hook 을 각자의 고루틴에서 부르므로 느린 Hook 이 감시를 막지 않는다.
+type Alerter struct {
+	Hooks     []Hook
+	OnRecover bool
+	// Timeout Hook 하나의 제한 시간. 0 이면 10초다.
+	Timeout time.Duration
+	// Logf 실패한 Hook 을 남긴다. nil 이면 버린다.
+	Logf func(format string, args ...any)
+
+	wg sync.WaitGroup
+}
+
+// Notify t 가 알릴 변화면 Hook 들을 부르고 바로 돌아온다.
+func (a *Alerter) Notify(t Transition) {
+	recovered := t.To == Serving && t.From == NotServing
+	if t.To != NotServing && !(a.OnRecover && recovered) {
+		return
+	}
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	for _, h := range a.Hooks {
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			if err := h.Notify(ctx, t); err != nil && a.Logf != nil {
+				a.Logf("%v", err)
+			}
+		}()
+	}
+}
+
+// Wait 진행 중인 Hook 이 모두 끝날 때까지 기다린다. 종료하기 전에 부른다.
+func (a *Alerter) Wait() {
+	a.wg.Wait()
+}
@@ -0,0 +1,258 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+)
+
+// State 감시 대상이 요청을 받을 수 있는지. grpc.health.v1 의 ServingStatus 이름을 따른다.
+type State string
+
+const (
+	// StateUnknown 아직 한 번도 확인하지 않았다.
+	StateUnknown State = "UNKNOWN"
+	Serving      State = "SERVING"
+	NotServing   State = "NOT_SERVING"
+)
+
+// Transition 상태가 바뀐 순간 하나
+type Transition struct {
+	At     time.Time `json:"at"`
+	Server string    `json:"server"`
+	Model  string    `json:"model,omitempty"`
+	From   State     `json:"from"`
+	To     State     `json:"to"`
+	// Status NOT_SERVING 이 된 이유의 종류. Check 의 Status 와 같다.
+	Status Status `json:"status"`
+	Reason string `json:"reason,omitempty"`
+	// Downtime NOT_SERVING 에서 SERVING 으로 돌아왔을 때 내려가 있던 시간
+	Downtime time.Duration `json:"downtime_ns,omitempty"`
+	// Source 변화를 알아챈 곳. "watch" 는 Health Watch 스트림, "poll" 은 주기적인 확인이다.
+	Source string `json:"source"`
+}
+
+// String 로그 한 줄. 예: "2024-05-01T09:00:00Z localhost:8001 vitpose_ensemble NOT_SERVING -> SERVING after 42s down (watch)"
+func (t Transition) String() string {
+	target := t.Server
+	if t.Model != "" {
+		target += " " + t.Model
+	}
+	s := fmt.Sprintf("%s %s %s -> %s", t.At.UTC().Format(time.RFC3339), target, t.From, t.To)
+	if t.Reason != "" {
+		s += ": " + t.Reason
+	}
+	if t.Downtime > 0 {
+		s += fmt.Sprintf(" after %v down", t.Downtime.Round(time.Millisecond))
+	}
+	return s + " (" + t.Source + ")"
+}
+
+// healthWatchMethod grpc.health.v1 Watch. Triton 의 health.proto 에는 Check 만 있어 스텁이 없으므로 직접 부른다.
+const healthWatchMethod = "/grpc.health.v1.Health/Watch"
+
+var healthWatchDesc = grpc.StreamDesc{StreamName: "Watch", ServerStreams: true}
+
+// Watcher 서버와 모델의 상태를 계속 보고 바뀔 때마다 OnTransition 을 부른다.
+//
+// 서버 상태는 grpc.health.v1 Watch 스트림으로 받고, 서버가 Watch 를 지원하지 않거나 스트림이 끊기면
+// ServerReady 를 Interval 마다 부른다. 모델과 앙상블 단계는 스트림이 없으므로 늘 ModelReady 로 확인한다.
+type Watcher struct {
+	Server   string
+	Model    string
+	Version  string
+	Interval time.Duration
+	// Timeout 확인 한 번의 제한 시간. 0 이면 Interval 이다.
+	Timeout time.Duration
+	// OnTransition 상태가 바뀔 때 감시 고루틴에서 부른다. 오래 걸리는 일은 따로 돌려야 한다.
+	OnTransition func(Transition)
+
+	conn grpc.ClientConnInterface
+	now  func() time.Time
+}
+
+// NewWatcher conn 의 서버와 model 을 interval 마다 확인하는 Watcher. model 이 비어 있으면 서버만 본다.
+func NewWatcher(conn grpc.ClientConnInterface, server, model, version string, interval time.Duration) *Watcher {
+	return &Watcher{
+		Server:   server,
+		Model:    model,
+		Version:  version,
+		Interval: interval,
+		conn:     conn,
+		now:      time.Now,
+	}
+}
+
+// watchState Run 이 들고 있는 상태
+type watchState struct {
+	state     State
+	downSince time.Time
+	// streaming Watch 스트림이 살아 있으면 true 이고, 그 동안 serving 이 서버 상태다.
+	streaming bool
+	serving   triton.HealthCheckResponse_ServingStatus
+}
+
+// streamEvent Watch 스트림에서 온 소식. ok 가 false 면 스트림을 쓸 수 없게 되었다.
+type streamEvent struct {
+	ok     bool
+	status triton.HealthCheckResponse_ServingStatus
+}
+
+// Run ctx 가 끝날 때까지 감시한다. 시작하자마자 한 번 확인하므로 첫 Transition 은 UNKNOWN 에서 시작한다.
+func (w *Watcher) Run(ctx context.Context) error {
+	if w.Interval <= 0 {
+		return fmt.Errorf("health: invalid watch interval %v", w.Interval)
+	}
+	events := make(chan streamEvent)
+	go w.watchStream(ctx, events)
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	st := &watchState{state: StateUnknown}
+	w.update(ctx, st, "poll")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-events:
+			st.streaming, st.serving = e.ok, e.status
+			w.update(ctx, st, "watch")
+		case <-ticker.C:
+			w.update(ctx, st, "poll")
+		}
+	}
+}
+
+// watchStream Watch 스트림의 상태를 events 로 보낸다. 스트림이 끊기면 Interval 뒤에 다시 연결하고,
+// Unimplemented 면 그만둔다.
+func (w *Watcher) watchStream(ctx context.Context, events chan<- streamEvent) {
+	send := func(e streamEvent) bool {
+		select {
+		case events <- e:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	for {
+		err := w.stream(ctx, send)
+		if ctx.Err() != nil || !send(streamEvent{}) || status.Code(err) == codes.Unimplemented {
+			return
+		}
+		select {
+		case <-time.After(w.Interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// stream Watch 를 한 번 열고 끊길 때까지 받은 상태를 send 로 넘긴다.
+func (w *Watcher) stream(ctx context.Context, send func(streamEvent) bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := w.conn.NewStream(ctx, &healthWatchDesc, healthWatchMethod)
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(&triton.HealthCheckRequest{}); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		resp := new(triton.HealthCheckResponse)
+		if err := stream.RecvMsg(resp); err != nil {
+			return err
+		}
+		if !send(streamEvent{ok: true, status: resp.Status}) {
+			return ctx.Err()
+		}
+	}
+}
+
+// update 지금 상태를 확인하고 바뀌었으면 OnTransition 을 부른다.
+func (w *Watcher) update(ctx context.Context, st *watchState, source string) {
+	s, err := w.probe(ctx, st)
+	if ctx.Err() != nil {
+		// 멈추는 중에 끊긴 확인은 서버 상태가 아니다.
+		return
+	}
+	to := Serving
+	if s != OK {
+		to = NotServing
+	}
+	if to == st.state {
+		return
+	}
+	t := Transition{At: w.now(), Server: w.Server, Model: w.Model, From: st.state, To: to, Status: s, Source: source}
+	if err != nil {
+		t.Reason = err.Error()
+	}
+	if to == NotServing {
+		st.downSince = t.At
+	} else if !st.downSince.IsZero() {
+		t.Downtime = t.At.Sub(st.downSince)
+		st.downSince = time.Time{}
+	}
+	st.state = to
+	if w.OnTransition != nil {
+		w.OnTransition(t)
+	}
+}
+
+// probe 서버, 모델, 앙상블 단계를 차례로 보고 처음 만난 실패를 돌려준다.
+func (w *Watcher) probe(ctx context.Context, st *watchState) (Status, error) {
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = w.Interval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	client := triton.NewGRPCInferenceServiceClient(w.conn)
+
+	if st.streaming {
+		if st.serving != triton.HealthCheckResponse_SERVING {
+			return NotReady, fmt.Errorf("health watch reported %s", st.serving)
+		}
+	} else {
+		ready, err := client.ServerReady(ctx, &triton.ServerReadyRequest{})
+		if err != nil {
+			return Unreachable, fmt.Errorf("server ready: %w", err)
+		}
+		if !ready.Ready {
+			return NotReady, errors.New("server is not ready")
+		}
+	}
+
+	if w.Model == "" {
+		return OK, nil
+	}
+	ready, err := client.ModelReady(ctx, &triton.ModelReadyRequest{Name: w.Model, Version: w.Version})
+	if err != nil {
+		return Unreachable, fmt.Errorf("model ready: %w", err)
+	}
+	if !ready.Ready {
+		return ModelNotReady, fmt.Errorf("model %s is not ready", w.Model)
+	}
+	// 모델을 다시 읽으면 단계가 바뀔 수 있으므로 매번 설정을 읽는다. 설정을 못 읽으면 단계는 건너뛴다.
+	steps, _ := ensembleSteps(ctx, client, w.Model, w.Version)
+	for _, step := range steps {
+		ready, err := client.ModelReady(ctx, &triton.ModelReadyRequest{Name: step.GetModelName(), Version: stepVersion(step)})
+		if err != nil {
+			return Unreachable, fmt.Errorf("model ready: %w", err)
+		}
+		if !ready.Ready {
+			return StepNotReady, fmt.Errorf("ensemble step %s of %s is not ready", step.GetModelName(), w.Model)
+		}
+	}
+	return OK, nil
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// watch Watcher 를 돌리고 Transition 을 채널로 받는다.
+func watch(t *testing.T, w *Watcher) <-chan Transition {
+	t.Helper()
+	transitions := make(chan Transition, 16)
+	w.OnTransition = func(tr Transition) { transitions <- tr }
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	})
+	return transitions
+}
+
+func next(t *testing.T, transitions <-chan Transition, from, to State) Transition {
+	t.Helper()
+	select {
+	case tr := <-transitions:
+		if tr.From != from || tr.To != to {
+			t.Fatalf("transition = %v, want %s -> %s", tr, from, to)
+		}
+		return tr
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no transition %s -> %s", from, to)
+		return Transition{}
+	}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	server, conn := startServer(t)
+	w := NewWatcher(conn, "bufconn", "vitpose_ensemble", "", 10*time.Millisecond)
+	w.Timeout = 5 * time.Second
+	transitions := watch(t, w)
+
+	tr := next(t, transitions, StateUnknown, Serving)
+	if tr.Status != OK || tr.Source != "poll" || tr.Downtime != 0 {
+		t.Errorf("first transition = %+v", tr)
+	}
+
+	server.SetModelReady("postprocess", false)
+	tr = next(t, transitions, Serving, NotServing)
+	if tr.Status != StepNotReady || !strings.Contains(tr.Reason, "postprocess") {
+		t.Errorf("step transition = %+v", tr)
+	}
+	server.SetModelReady("postprocess", true)
+	tr = next(t, transitions, NotServing, Serving)
+	if tr.Downtime <= 0 || !strings.Contains(tr.String(), " down (") {
+		t.Errorf("recovery = %v", tr)
+	}
+
+	server.SetReady(false)
+	if tr = next(t, transitions, Serving, NotServing); tr.Status != NotReady {
+		t.Errorf("server transition = %+v", tr)
+	}
+}
+
+func TestWatcherStream(t *testing.T) {
+	server, conn := startServer(t)
+	server.SetHealthWatch(true)
+	// 주기적인 확인이 끼어들지 않도록 Interval 을 길게 둔다.
+	transitions := watch(t, NewWatcher(conn, "bufconn", "", "", time.Hour))
+
+	next(t, transitions, StateUnknown, Serving)
+	server.SetReady(false)
+	tr := next(t, transitions, Serving, NotServing)
+	if tr.Source != "watch" || tr.Status != NotReady || tr.Reason != "health watch reported NOT_SERVING" {
+		t.Errorf("transition = %+v", tr)
+	}
+	server.SetReady(true)
+	if tr = next(t, transitions, NotServing, Serving); tr.Source != "watch" || tr.Downtime <= 0 {
+		t.Errorf("recovery = %+v", tr)
+	}
+}
+
+func TestAlerter(t *testing.T) {
+	var got []Transition
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tr Transition
+		if err := json.NewDecoder(r.Body).Decode(&tr); err != nil {
+			t.Error(err)
+		}
+		got = append(got, tr)
+	}))
+	defer webhook.Close()
+	out := filepath.Join(t.TempDir(), "out")
+	var errs []string
+	a := &Alerter{
+		Hooks: []Hook{
+			Webhook{URL: webhook.URL},
+			Command(`echo "$HEALTH_PREVIOUS $HEALTH_STATE $HEALTH_STATUS $HEALTH_MODEL" >> ` + out),
+			Command("exit 3"),
+		},
+		Logf: func(format string, args ...any) { errs = append(errs, format) },
+	}
+
+	at := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	a.Notify(Transition{At: at, Server: "s", Model: "m", From: StateUnknown, To: Serving})
+	a.Wait()
+	down := Transition{At: at, Server: "s", Model: "m", From: Serving, To: NotServing, Status: ModelNotReady, Reason: "model m is not ready"}
+	a.Notify(down)
+	a.Wait()
+	a.Notify(Transition{At: at, Server: "s", Model: "m", From: NotServing, To: Serving, Downtime: time.Second})
+	a.Wait()
+
+	if len(got) != 1 || got[0].To != NotServing || got[0].Reason != down.Reason || !got[0].At.Equal(at) {
+		t.Errorf("webhook got %+v, want only the NOT_SERVING transition", got)
+	}
+	if b, err := os.ReadFile(out); err != nil || string(b) != "SERVING NOT_SERVING model_not_ready m\n" {
+		t.Errorf("command output = %q, %v", b, err)
+	}
+	if len(errs) != 1 {
+		t.Errorf("logged %d errors, want 1 for the failing command", len(errs))
+	}
+
+	a.OnRecover = true
+	a.Notify(Transition{From: NotServing, To: Serving})
+	a.Wait()
+	if len(got) != 2 {
+		t.Errorf("webhook called %d times, want a recovery notification", len(got))
+	}
+}
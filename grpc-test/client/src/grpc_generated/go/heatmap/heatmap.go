@@ -0,0 +1,314 @@
+// Package heatmap 은 vitpose 모델의 [N, 17, 64, 48] 히트맵 출력을 키포인트로 바꾼다.
+// postprocess/1/util.py 의 keypoints_from_heatmaps 를 Go 로 옮긴 것으로,
+// Triton 의 Python postprocess 단계 없이 raw `vitpose` 모델을 직접 호출할 때 쓴다.
+package heatmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+// DefaultOutputName raw vitpose 모델의 히트맵 출력 텐서 이름
+const DefaultOutputName = "output"
+
+// Options keypoints_from_heatmaps 의 인자에 대응하는 설정
+type Options struct {
+	// UDP 가 true 이면 post_dark_udp, false 이면 Gaussian 변조 + _taylor 로 좌표를 보정한다.
+	UDP bool
+	// Kernel 은 Gaussian 커널 크기로 홀수여야 한다. 0 이면 11 을 쓴다.
+	Kernel int
+}
+
+// DefaultOptions postprocess 모델과 같은 설정 (use_udp=True, kernel=11)
+var DefaultOptions = Options{UDP: true, Kernel: 11}
+
+// Heatmaps [N, K, H, W] 순서의 히트맵 텐서
+type Heatmaps struct {
+	N, K, H, W int
+	Data       []float32
+}
+
+// at n 번째 사람의 k 번째 관절 히트맵 (H*W)
+func (h *Heatmaps) at(n, k int) []float32 {
+	size := h.H * h.W
+	offset := (n*h.K + k) * size
+	return h.Data[offset : offset+size]
+}
+
+// FromResponse 응답에서 name 출력 텐서를 찾아 [N, 17, H, W] FP32 히트맵으로 해석한다.
+func FromResponse(response *triton.ModelInferResponse, name string) (*Heatmaps, error) {
+	index := -1
+	for i, output := range response.GetOutputs() {
+		if output.GetName() == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("heatmap: output %q not found in response from model %q", name, response.GetModelName())
+	}
+	output := response.Outputs[index]
+	if output.Datatype != "FP32" {
+		return nil, fmt.Errorf("heatmap: output %q has datatype %s, want FP32", name, output.Datatype)
+	}
+	shape := output.Shape
+	if len(shape) != 4 || shape[0] < 0 || shape[1] != vitpose.NumKeypoints || shape[2] <= 0 || shape[3] <= 0 {
+		return nil, fmt.Errorf("heatmap: output %q has shape %v, want [N %d H W]", name, shape, vitpose.NumKeypoints)
+	}
+	if index >= len(response.RawOutputContents) {
+		return nil, fmt.Errorf("heatmap: output %q has no raw contents", name)
+	}
+	raw := response.RawOutputContents[index]
+	h := &Heatmaps{N: int(shape[0]), K: int(shape[1]), H: int(shape[2]), W: int(shape[3])}
+	if want := h.N * h.K * h.H * h.W * 4; len(raw) != want {
+		return nil, fmt.Errorf("heatmap: output %q has %d bytes, want %d for shape %v", name, len(raw), want, shape)
+	}
+	h.Data = make([]float32, len(raw)/4)
+	for i := range h.Data {
+		h.Data[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:]))
+	}
+	return h, nil
+}
+
+// Decode 히트맵에서 키포인트를 구하고 boxes[n] 기준 원본 이미지 좌표로 옮긴다.
+// boxes 가 nil 이면 vitpose.InputBox (256x192 crop 좌표) 를 쓴다. h.Data 는 바뀌지 않는다.
+func Decode(h *Heatmaps, boxes []vitpose.Box, opts Options) ([]vitpose.Pose, error) {
+	if h.K != vitpose.NumKeypoints {
+		return nil, fmt.Errorf("heatmap: got %d keypoints, want %d", h.K, vitpose.NumKeypoints)
+	}
+	if len(h.Data) != h.N*h.K*h.H*h.W {
+		return nil, fmt.Errorf("heatmap: got %d values for shape [%d %d %d %d]", len(h.Data), h.N, h.K, h.H, h.W)
+	}
+	if boxes != nil && len(boxes) != h.N {
+		return nil, fmt.Errorf("heatmap: got %d boxes for %d heatmaps", len(boxes), h.N)
+	}
+	kernel := opts.Kernel
+	if kernel == 0 {
+		kernel = 11
+	}
+	if kernel < 0 || kernel%2 != 1 {
+		return nil, fmt.Errorf("heatmap: kernel size must be odd, got %d", kernel)
+	}
+
+	poses := make([]vitpose.Pose, h.N)
+	for n := range poses {
+		box := vitpose.InputBox
+		if boxes != nil {
+			box = boxes[n]
+		}
+		for k := 0; k < h.K; k++ {
+			heatmap := h.at(n, k)
+			x, y, maxval := maxPred(heatmap, h.W)
+			if opts.UDP {
+				x, y = darkUDP(heatmap, h.H, h.W, x, y, kernel)
+			} else {
+				x, y = taylor(modulate(heatmap, h.H, h.W, kernel), h.H, h.W, x, y)
+			}
+			px, py := box.ToImage(float32(x), float32(y), h.W, h.H, opts.UDP)
+			poses[n].Keypoints[k] = vitpose.Keypoint{X: px, Y: py, Score: maxval}
+		}
+	}
+	return poses, nil
+}
+
+// NewDecoder raw vitpose 모델 응답을 Options 로 디코딩하는 vitpose.Decoder 를 만든다.
+func NewDecoder(opts Options) vitpose.Decoder {
+	return func(response *triton.ModelInferResponse, name string, batch *vitpose.Batch) ([]vitpose.Pose, error) {
+		h, err := FromResponse(response, name)
+		if err != nil {
+			return nil, err
+		}
+		return Decode(h, batch.Boxes, opts)
+	}
+}
+
+// maxPred _get_max_preds 와 같이 최댓값 위치와 값을 구한다. 최댓값이 0 이하이면 위치는 (-1, -1) 이다.
+func maxPred(heatmap []float32, width int) (x, y float64, maxval float32) {
+	idx := 0
+	for i, v := range heatmap {
+		if v > heatmap[idx] {
+			idx = i
+		}
+	}
+	maxval = heatmap[idx]
+	if maxval <= 0 {
+		return -1, -1, maxval
+	}
+	return float64(idx % width), float64(idx / width), maxval
+}
+
+// darkUDP post_dark_udp 와 같이 blur 후 로그 히트맵의 2차 테일러 전개로 좌표를 보정한다.
+// 필요한 3x3 이웃에서만 blur 값을 계산한다. 가장자리는 edge 패딩처럼 좌표를 잘라 쓴다.
+// 최댓값이 0 이하여서 좌표가 (-1, -1) 이면 post_dark_udp 는 평탄화된 배열에서 앞 맵의 마지막 행을 읽지만
+// 여기서는 이 맵의 가장자리로 자른다. 앞 맵의 아래쪽 모서리가 비어 있으면 결과가 같다.
+func darkUDP(heatmap []float32, height, width int, x, y float64, kernel int) (float64, float64) {
+	weights := gaussianKernel(kernel)
+	px, py := int(x), int(y)
+	logAt := func(dy, dx int) float64 {
+		v := blurAt(heatmap, height, width, clamp(py+dy, height), clamp(px+dx, width), weights)
+		return math.Log(math.Min(math.Max(v, 0.001), 50))
+	}
+
+	i := logAt(0, 0)
+	ix1 := logAt(0, 1)
+	iy1 := logAt(1, 0)
+	ix1y1 := logAt(1, 1)
+	ix1y1_ := logAt(-1, -1)
+	ix1_ := logAt(0, -1)
+	iy1_ := logAt(-1, 0)
+
+	dx := 0.5 * (ix1 - ix1_)
+	dy := 0.5 * (iy1 - iy1_)
+	dxx := ix1 - 2*i + ix1_
+	dyy := iy1 - 2*i + iy1_
+	dxy := 0.5 * (ix1y1 - ix1 - iy1 + i + i - ix1_ - iy1_ + ix1y1_)
+
+	// np.linalg.inv(hessian + eps * I)
+	const eps = 1.1920929e-07
+	a, b, c, d := dxx+eps, dxy, dxy, dyy+eps
+	det := a*d - b*c
+	if det == 0 {
+		return x, y
+	}
+	x -= (d*dx - b*dy) / det
+	y -= (-c*dx + a*dy) / det
+	return x, y
+}
+
+// modulate _gaussian_blur 뒤 np.log(np.maximum(., 1e-10)) 를 적용한 새 히트맵을 만든다.
+func modulate(heatmap []float32, height, width, kernel int) []float64 {
+	weights := gaussianKernel(kernel)
+	border := (kernel - 1) / 2
+
+	originMax := math.Inf(-1)
+	for _, v := range heatmap {
+		originMax = math.Max(originMax, float64(v))
+	}
+
+	// 가장자리를 border 만큼 0 으로 채운 뒤 blur 하는 것과 같다.
+	tmp := make([]float64, height*width)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var sum float64
+			for j, w := range weights {
+				if xx := x + j - border; xx >= 0 && xx < width {
+					sum += w * float64(heatmap[y*width+xx])
+				}
+			}
+			tmp[y*width+x] = sum
+		}
+	}
+	out := make([]float64, height*width)
+	blurMax := math.Inf(-1)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var sum float64
+			for i, w := range weights {
+				if yy := y + i - border; yy >= 0 && yy < height {
+					sum += w * tmp[yy*width+x]
+				}
+			}
+			out[y*width+x] = sum
+			blurMax = math.Max(blurMax, sum)
+		}
+	}
+	for i, v := range out {
+		if blurMax != 0 {
+			v *= originMax / blurMax
+		}
+		out[i] = math.Log(math.Max(v, 1e-10))
+	}
+	return out
+}
+
+// taylor _taylor 와 같이 로그 히트맵의 2차 테일러 전개로 좌표를 보정한다.
+func taylor(heatmap []float64, height, width int, x, y float64) (float64, float64) {
+	px, py := int(x), int(y)
+	if !(1 < px && px < width-2 && 1 < py && py < height-2) {
+		return x, y
+	}
+	at := func(yy, xx int) float64 { return heatmap[yy*width+xx] }
+	dx := 0.5 * (at(py, px+1) - at(py, px-1))
+	dy := 0.5 * (at(py+1, px) - at(py-1, px))
+	dxx := 0.25 * (at(py, px+2) - 2*at(py, px) + at(py, px-2))
+	dxy := 0.25 * (at(py+1, px+1) - at(py-1, px+1) - at(py+1, px-1) + at(py-1, px-1))
+	dyy := 0.25 * (at(py+2, px) - 2*at(py, px) + at(py-2, px))
+	det := dxx*dyy - dxy*dxy
+	if det == 0 {
+		return x, y
+	}
+	x -= (dyy*dx - dxy*dy) / det
+	y -= (-dxy*dx + dxx*dy) / det
+	return x, y
+}
+
+// gaussianKernel sigma=0 일 때 cv2.getGaussianKernel 과 같은 1차원 커널을 만든다.
+func gaussianKernel(size int) []float64 {
+	// OpenCV 는 7 이하의 크기에 고정 커널을 쓴다.
+	switch size {
+	case 1:
+		return []float64{1}
+	case 3:
+		return []float64{0.25, 0.5, 0.25}
+	case 5:
+		return []float64{0.0625, 0.25, 0.375, 0.25, 0.0625}
+	case 7:
+		return []float64{0.03125, 0.109375, 0.21875, 0.28125, 0.21875, 0.109375, 0.03125}
+	}
+	sigma := 0.3*(float64(size-1)*0.5-1) + 0.8
+	weights := make([]float64, size)
+	var sum float64
+	for i := range weights {
+		x := float64(i - (size-1)/2)
+		weights[i] = math.Exp(-x * x / (2 * sigma * sigma))
+		sum += weights[i]
+	}
+	for i := range weights {
+		weights[i] /= sum
+	}
+	return weights
+}
+
+// blurAt cv2.GaussianBlur (BORDER_REFLECT_101) 결과의 (y, x) 한 점만 계산한다.
+func blurAt(heatmap []float32, height, width, y, x int, weights []float64) float64 {
+	radius := len(weights) / 2
+	var sum float64
+	for i, wy := range weights {
+		yy := reflect101(y+i-radius, height)
+		var row float64
+		for j, wx := range weights {
+			row += wx * float64(heatmap[yy*width+reflect101(x+j-radius, width)])
+		}
+		sum += wy * row
+	}
+	return sum
+}
+
+// reflect101 OpenCV BORDER_REFLECT_101 (gfedcb|abcdefgh|gfedcba) 인덱스
+func reflect101(i, n int) int {
+	if n == 1 {
+		return 0
+	}
+	for i < 0 || i >= n {
+		if i < 0 {
+			i = -i
+		} else {
+			i = 2*n - 2 - i
+		}
+	}
+	return i
+}
+
+func clamp(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
@@ -0,0 +1,295 @@
+package heatmap
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"testing"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+const (
+	testHeight = 64
+	testWidth  = 48
+)
+
+// gaussianHeatmaps 모든 관절에 (cx, cy) 중심, sigma 2 인 Gaussian 을 그린 [n, 17, 64, 48] 히트맵
+func gaussianHeatmaps(n int, cx, cy, peak float64) *Heatmaps {
+	h := &Heatmaps{N: n, K: vitpose.NumKeypoints, H: testHeight, W: testWidth}
+	h.Data = make([]float32, n*h.K*h.H*h.W)
+	for i := 0; i < n*h.K; i++ {
+		for y := 0; y < h.H; y++ {
+			for x := 0; x < h.W; x++ {
+				d2 := (float64(x)-cx)*(float64(x)-cx) + (float64(y)-cy)*(float64(y)-cy)
+				h.Data[i*h.H*h.W+y*h.W+x] = float32(peak * math.Exp(-d2/8))
+			}
+		}
+	}
+	return h
+}
+
+func TestGaussianKernel(t *testing.T) {
+	// cv2.getGaussianKernel(ksize, 0)
+	golden := map[int][]float64{
+		3:  {0.25, 0.5, 0.25},
+		11: {0.00881223, 0.02714358, 0.06511406, 0.12164907, 0.17699836, 0.20056541, 0.17699836, 0.12164907, 0.06511406, 0.02714358, 0.00881223},
+	}
+	for size, want := range golden {
+		got := gaussianKernel(size)
+		if len(got) != len(want) {
+			t.Fatalf("gaussianKernel(%d) has %d taps, want %d", size, len(got), len(want))
+		}
+		for i := range want {
+			if math.Abs(got[i]-want[i]) > 1e-8 {
+				t.Errorf("gaussianKernel(%d)[%d] = %.8f, want %.8f", size, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestReflect101(t *testing.T) {
+	// gfedcb|abcdefgh|gfedcba
+	for i, want := range map[int]int{-3: 3, -1: 1, 0: 0, 7: 7, 8: 6, 10: 4} {
+		if got := reflect101(i, 8); got != want {
+			t.Errorf("reflect101(%d, 8) = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestMaxPred(t *testing.T) {
+	heatmap := make([]float32, testHeight*testWidth)
+	heatmap[30*testWidth+20] = 0.75
+	heatmap[30*testWidth+21] = 0.5
+	x, y, maxval := maxPred(heatmap, testWidth)
+	if x != 20 || y != 30 || maxval != 0.75 {
+		t.Errorf("maxPred = (%v, %v, %v), want (20, 30, 0.75)", x, y, maxval)
+	}
+
+	for i := range heatmap {
+		heatmap[i] = -1
+	}
+	if x, y, _ := maxPred(heatmap, testWidth); x != -1 || y != -1 {
+		t.Errorf("maxPred on non-positive heatmap = (%v, %v), want (-1, -1)", x, y)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	// 로그를 취하면 2차식이 되는 Gaussian 은 DARK/Taylor 보정이 서브픽셀 중심을 그대로 찾아야 한다.
+	const cx, cy = 20.3, 30.6
+	h := gaussianHeatmaps(2, cx, cy, 0.9)
+	original := append([]float32(nil), h.Data...)
+
+	tests := []struct {
+		name   string
+		opts   Options
+		wantX  float64
+		wantY  float64
+		maxErr float64
+	}{
+		// transform_preds(use_udp=True): x * 192 / 47, y * 256 / 63
+		{"udp", Options{UDP: true, Kernel: 11}, cx * 192 / 47, cy * 256 / 63, 0.05},
+		// transform_preds(use_udp=False): x * 192 / 48, y * 256 / 64
+		{"taylor", Options{UDP: false, Kernel: 11}, cx * 4, cy * 4, 0.05},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poses, err := Decode(h, nil, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(poses) != 2 {
+				t.Fatalf("got %d poses, want 2", len(poses))
+			}
+			for _, pose := range poses {
+				for k, kp := range pose.Keypoints {
+					if math.Abs(float64(kp.X)-tt.wantX) > tt.maxErr || math.Abs(float64(kp.Y)-tt.wantY) > tt.maxErr {
+						t.Fatalf("keypoint %v = (%.3f, %.3f), want (%.3f, %.3f)", vitpose.Joint(k), kp.X, kp.Y, tt.wantX, tt.wantY)
+					}
+					// maxvals 는 보정 전 히트맵의 최댓값 (20, 31)
+					want := float32(0.9 * math.Exp(-(0.3*0.3+0.4*0.4)/8))
+					if math.Abs(float64(kp.Score-want)) > 1e-6 {
+						t.Fatalf("keypoint %v score = %v, want %v", vitpose.Joint(k), kp.Score, want)
+					}
+				}
+			}
+		})
+	}
+
+	for i := range original {
+		if h.Data[i] != original[i] {
+			t.Fatal("Decode modified the input heatmaps")
+		}
+	}
+}
+
+// fixture testdata/gen_keypoints.py 가 postprocess/1/util.py 의 keypoints_from_heatmaps 로 만든 golden 값
+type fixture struct {
+	Height, Width, Kernel int
+	People                []struct {
+		Box struct {
+			Center [2]float32
+			Scale  [2]float32
+		}
+		Heatmaps []heatmapSpec
+	}
+	// UDP, Taylor 사람별 관절마다 (x, y, score)
+	UDP    [][][3]float64
+	Taylor [][][3]float64
+}
+
+// heatmapSpec 히트맵 하나를 그리는 방법. gen_keypoints.py 의 render 와 같다.
+type heatmapSpec struct {
+	Offset float64
+	Peaks  [][4]float64 // x, y, sigma, peak
+	Noise  float64
+	Seed   uint64
+}
+
+func (s heatmapSpec) render(dst []float32, width int) {
+	state := s.Seed
+	if state == 0 {
+		state = 1
+	}
+	for i := range dst {
+		x, y := float64(i%width), float64(i/width)
+		v := s.Offset
+		for _, p := range s.Peaks {
+			v += p[3] * math.Exp(-((x-p[0])*(x-p[0])+(y-p[1])*(y-p[1]))/(2*p[2]*p[2]))
+		}
+		if s.Noise != 0 {
+			state = (state*1103515245 + 12345) % (1 << 31)
+			v += s.Noise * (float64(state)/(1<<31) - 0.5)
+		}
+		dst[i] = float32(v)
+	}
+}
+
+func TestDecodeGolden(t *testing.T) {
+	data, err := os.ReadFile("testdata/keypoints.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	h := &Heatmaps{N: len(f.People), K: vitpose.NumKeypoints, H: f.Height, W: f.Width}
+	h.Data = make([]float32, h.N*h.K*h.H*h.W)
+	boxes := make([]vitpose.Box, h.N)
+	for n, person := range f.People {
+		boxes[n] = vitpose.Box{Center: person.Box.Center, Scale: person.Box.Scale}
+		for k, spec := range person.Heatmaps {
+			spec.render(h.at(n, k), h.W)
+		}
+	}
+
+	tests := []struct {
+		name string
+		udp  bool
+		want [][][3]float64
+	}{
+		{"udp", true, f.UDP},
+		{"taylor", false, f.Taylor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poses, err := Decode(h, boxes, Options{UDP: tt.udp, Kernel: f.Kernel})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for n, pose := range poses {
+				for k, kp := range pose.Keypoints {
+					// util.py 는 float32 로 계산하므로 좌표는 1e-3 픽셀까지 허용한다.
+					want := tt.want[n][k]
+					if math.Abs(float64(kp.X)-want[0]) > 1e-3 || math.Abs(float64(kp.Y)-want[1]) > 1e-3 || math.Abs(float64(kp.Score)-want[2]) > 1e-6 {
+						t.Errorf("person %d %v = (%.4f, %.4f, %.6f), want (%.4f, %.4f, %.6f)",
+							n, vitpose.Joint(k), kp.X, kp.Y, kp.Score, want[0], want[1], want[2])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeBoxes(t *testing.T) {
+	h := gaussianHeatmaps(1, 23.5, 31.5, 1)
+	box := vitpose.BoxFromXYWH(100, 50, 96, 128, 1)
+	poses, err := Decode(h, []vitpose.Box{box}, DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 박스 좌상단 + 히트맵 좌표 * (박스 크기 / (히트맵 크기 - 1))
+	wantX, wantY := 100+23.5*96/47, 50+31.5*128/63
+	kp := poses[0].Joint(vitpose.Nose)
+	if math.Abs(float64(kp.X)-wantX) > 0.05 || math.Abs(float64(kp.Y)-wantY) > 0.05 {
+		t.Errorf("nose = (%.3f, %.3f), want (%.3f, %.3f)", kp.X, kp.Y, wantX, wantY)
+	}
+
+	if _, err := Decode(h, []vitpose.Box{box, box}, DefaultOptions); err == nil {
+		t.Error("Decode with mismatched boxes succeeded")
+	}
+}
+
+func TestLocalTransformMatchesBoxes(t *testing.T) {
+	// 서버에 center/scale 을 보낸 결과와 InputBox 기준 결과를 Pose.FromCrop 으로 옮긴 결과 (WithLocalTransform) 는 같아야 한다.
+	h := gaussianHeatmaps(1, 17.3, 40.8, 1)
+	for _, box := range []vitpose.Box{
+		vitpose.BoxFromXYWH(100, 50, 96, 128, 1),
+		vitpose.BoxFromXYWH(-20, 300, 700, 500, 1.25),
+	} {
+		server, err := Decode(h, []vitpose.Box{box}, DefaultOptions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		crop, err := Decode(h, nil, DefaultOptions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		local := crop[0].FromCrop(box)
+		for k := range local.Keypoints {
+			got, want := local.Keypoints[k], server[0].Keypoints[k]
+			if math.Abs(float64(got.X-want.X)) > 1e-3 || math.Abs(float64(got.Y-want.Y)) > 1e-3 {
+				t.Errorf("box %+v keypoint %d: local (%.3f, %.3f), server (%.3f, %.3f)", box, k, got.X, got.Y, want.X, want.Y)
+			}
+		}
+	}
+}
+
+func TestFromResponse(t *testing.T) {
+	h := gaussianHeatmaps(1, 10, 10, 1)
+	raw := make([]byte, 4*len(h.Data))
+	for i, v := range h.Data {
+		binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(v))
+	}
+	response := &triton.ModelInferResponse{
+		ModelName: "vitpose",
+		Outputs: []*triton.ModelInferResponse_InferOutputTensor{
+			{Name: DefaultOutputName, Datatype: "FP32", Shape: []int64{1, 17, testHeight, testWidth}},
+		},
+		RawOutputContents: [][]byte{raw},
+	}
+
+	got, err := FromResponse(response, DefaultOutputName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.N != 1 || got.H != testHeight || got.W != testWidth || got.Data[10*testWidth+10] != 1 {
+		t.Errorf("FromResponse = %dx%dx%dx%d, peak %v", got.N, got.K, got.H, got.W, got.Data[10*testWidth+10])
+	}
+
+	response.Outputs[0].Shape = []int64{1, 16, testHeight, testWidth}
+	if _, err := FromResponse(response, DefaultOutputName); err == nil {
+		t.Error("FromResponse accepted 16 keypoints")
+	}
+	response.Outputs[0].Shape = []int64{1, 17, testHeight, testWidth}
+	response.Outputs[0].Datatype = "FP16"
+	if _, err := FromResponse(response, DefaultOutputName); err == nil {
+		t.Error("FromResponse accepted FP16")
+	}
+	if _, err := FromResponse(response, "post_output"); err == nil {
+		t.Error("FromResponse found a missing output")
+	}
+}
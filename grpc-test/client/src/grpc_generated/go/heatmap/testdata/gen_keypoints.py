@@ -0,0 +1,105 @@
+"""heatmap 패키지 테스트의 golden fixture (keypoints.json) 를 만든다.
+
+postprocess/1/util.py 의 keypoints_from_heatmaps 를 그대로 불러 use_udp=True (post_dark_udp) 와
+use_udp=False (_gaussian_blur + _taylor) 결과를 모두 적는다. numpy 와 opencv-python 이 필요하다.
+
+    cd heatmap/testdata && python3 gen_keypoints.py
+
+히트맵은 파일에 값 대신 만드는 방법 (spec) 으로 적고, heatmap_test.go 의 render 가 같은 식으로 그린다.
+    value(x, y) = offset + sum(peak * exp(-((x-px)^2 + (y-py)^2) / (2 sigma^2))) + noise * (lcg() - 0.5)
+"""
+import json
+import math
+import os
+import sys
+
+import numpy as np
+
+HERE = os.path.dirname(os.path.abspath(__file__))
+sys.path.insert(0, os.path.join(HERE, '../../../../../../../pose_model_zoo/postprocess/1'))
+
+from util import keypoints_from_heatmaps  # noqa: E402
+
+H, W, K = 64, 48, 17
+KERNEL = 11
+
+
+def gaussian(x, y, peak=0.9, sigma=2.0):
+    return {'peaks': [[x, y, sigma, peak]]}
+
+
+def render(spec):
+    state = spec.get('seed', 1)
+    values = []
+    for y in range(H):
+        for x in range(W):
+            v = spec.get('offset', 0.0)
+            for px, py, sigma, peak in spec.get('peaks', []):
+                v += peak * math.exp(-((x - px) ** 2 + (y - py) ** 2) / (2 * sigma * sigma))
+            if spec.get('noise'):
+                state = (state * 1103515245 + 12345) % 2 ** 31
+                v += spec['noise'] * (state / 2 ** 31 - 0.5)
+            values.append(v)
+    return values
+
+
+# 값이 0 이하인 맵 (preds = -1) 은 post_dark_udp 가 평탄화된 배열에서 앞 맵의 마지막 행까지 읽으므로
+# 앞 맵의 아래쪽 모서리가 비어 있도록 순서를 잡는다.
+PEOPLE = [
+    {
+        'box': {'center': [300.0, 400.0], 'scale': [150.0, 200.0]},
+        'heatmaps': [
+            gaussian(20.3, 30.6),
+            {},  # 모두 0
+            {'offset': -0.5, 'peaks': [[24, 32, 3.0, 0.3]]},  # 모두 음수
+            gaussian(0, 0, 0.8),
+            gaussian(47, 63, 0.7),
+            gaussian(0, 31.4),
+            gaussian(47, 10.2),
+            gaussian(24.6, 0),
+            gaussian(12.3, 63),
+            gaussian(1.2, 1.4),  # _taylor 범위 (1 < px < W-2) 바로 밖
+            gaussian(2.3, 2.2),  # _taylor 범위 안쪽 첫 칸
+            gaussian(45.7, 61.6),
+            {'peaks': [[10, 10, 2.0, 0.6], [30, 40, 3.0, 0.8]]},
+            gaussian(33.5, 20.5, 0.9, 1.0),  # 두 칸이 같은 최댓값
+            gaussian(15.8, 45.1, 0.5, 3.0),
+            {'peaks': [[27.4, 18.9, 2.0, 0.7]], 'noise': 0.02, 'seed': 7},
+            gaussian(30, 20, 0.002),  # clip 하한 (0.001) 근처
+        ],
+    },
+    {
+        'box': {'center': [96.0, 128.0], 'scale': [192.0, 256.0]},
+        'heatmaps': [
+            {'offset': -0.1},
+            gaussian(47, 0, 0.6),
+            gaussian(0, 63, 0.6),
+        ] + [
+            gaussian((7 * k + 3) % 44 + 2.37, (11 * k + 5) % 58 + 1.61, 0.5 + 0.02 * k)
+            for k in range(3, K - 1)
+        ] + [
+            {},
+        ],
+    },
+]
+
+
+def main():
+    heatmaps = np.array([[render(spec) for spec in person['heatmaps']] for person in PEOPLE],
+                        dtype=np.float32).reshape((len(PEOPLE), K, H, W))
+    center = np.array([person['box']['center'] for person in PEOPLE], dtype=np.float32)
+    scale = np.array([person['box']['scale'] for person in PEOPLE], dtype=np.float32)
+
+    fixture = {'height': H, 'width': W, 'kernel': KERNEL, 'people': PEOPLE}
+    for name, use_udp in (('udp', True), ('taylor', False)):
+        preds, maxvals = keypoints_from_heatmaps(heatmaps, center, scale, kernel=KERNEL, use_udp=use_udp)
+        fixture[name] = [[[float(preds[n][k][0]), float(preds[n][k][1]), float(maxvals[n][k][0])]
+                          for k in range(K)] for n in range(len(PEOPLE))]
+
+    with open(os.path.join(HERE, 'keypoints.json'), 'w') as f:
+        json.dump(fixture, f, indent=1)
+        f.write('\n')
+
+
+if __name__ == '__main__':
+    main()
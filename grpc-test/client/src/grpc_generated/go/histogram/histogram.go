@@ -0,0 +1,249 @@
+// Package histogram 은 레이턴시용 HDR (high dynamic range) 히스토그램을 제공한다.
+//
+// 값을 2 의 거듭제곱 구간마다 같은 개수의 하위 구간으로 나누어 세므로, 기록한 요청 수와 상관없이
+// 메모리가 일정하고 모든 값의 상대 오차가 유효 숫자 범위 안에 든다. 같은 설정의 히스토그램은
+// Merge 로 합칠 수 있다.
+package histogram
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// Histogram Lowest 단위로 0 부터 Highest 까지의 값을 세는 히스토그램. 동시에 쓰려면 호출하는 쪽에서 잠가야 한다.
+type Histogram struct {
+	lowest   time.Duration
+	highest  time.Duration
+	sigFigs  int
+	subBits  uint // 하위 구간 수 = 1 << subBits
+	counts   []int64
+	total    int64
+	min      time.Duration
+	max      time.Duration
+	sum      float64
+	sumSq    float64
+	overflow int64
+}
+
+// New lowest 단위로 highest 까지를 유효 숫자 sigFigs (1-5) 자리로 기록하는 히스토그램을 만든다.
+func New(lowest, highest time.Duration, sigFigs int) *Histogram {
+	if lowest <= 0 {
+		lowest = 1
+	}
+	if highest < lowest {
+		highest = lowest
+	}
+	if sigFigs < 1 {
+		sigFigs = 1
+	}
+	if sigFigs > 5 {
+		sigFigs = 5
+	}
+	// 하위 구간 수는 2*10^sigFigs 이상인 2 의 거듭제곱이다.
+	subBits := uint(bits.Len64(uint64(2*math.Pow10(sigFigs)) - 1))
+	h := &Histogram{lowest: lowest, highest: highest, sigFigs: sigFigs, subBits: subBits}
+	h.counts = make([]int64, h.index(h.units(highest))+1)
+	return h
+}
+
+// NewLatency 1µs 부터 1분까지를 유효 숫자 3 자리로 기록하는 히스토그램. 약 140KB 를 쓴다.
+func NewLatency() *Histogram {
+	return New(time.Microsecond, time.Minute, 3)
+}
+
+func (h *Histogram) units(d time.Duration) uint64 {
+	if d < 0 {
+		return 0
+	}
+	return uint64(d / h.lowest)
+}
+
+// index 값 v (lowest 단위) 가 들어갈 칸. v < 2^subBits 는 그대로,
+// 그 위는 상위 subBits 비트만 남겨 2 의 거듭제곱 구간마다 2^(subBits-1) 칸에 나눈다.
+func (h *Histogram) index(v uint64) int {
+	subCount := uint64(1) << h.subBits
+	if v < subCount {
+		return int(v)
+	}
+	shift := uint(bits.Len64(v)) - h.subBits
+	half := subCount >> 1
+	sub := v >> shift
+	return int(subCount + uint64(shift-1)*half + (sub - half))
+}
+
+// bounds index 칸에 들어가는 가장 작은 값과 가장 큰 값 (lowest 단위)
+func (h *Histogram) bounds(i int) (low, high uint64) {
+	subCount := uint64(1) << h.subBits
+	if uint64(i) < subCount {
+		return uint64(i), uint64(i)
+	}
+	half := subCount >> 1
+	rest := uint64(i) - subCount
+	shift := uint(rest/half) + 1
+	sub := half + rest%half
+	return sub << shift, (sub+1)<<shift - 1
+}
+
+// Record 값 하나를 기록한다. Highest 보다 큰 값은 Highest 로 센다.
+func (h *Histogram) Record(d time.Duration) {
+	h.RecordN(d, 1)
+}
+
+// RecordN 같은 값 n 개를 기록한다.
+func (h *Histogram) RecordN(d time.Duration, n int64) {
+	if n <= 0 {
+		return
+	}
+	if d < 0 {
+		d = 0
+	}
+	if d > h.highest {
+		h.overflow += n
+		d = h.highest
+	}
+	h.counts[h.index(h.units(d))] += n
+	if h.total == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.total += n
+	s := float64(d)
+	h.sum += s * float64(n)
+	h.sumSq += s * s * float64(n)
+}
+
+// Merge other 의 기록을 h 에 더한다. 두 히스토그램의 설정이 같아야 한다.
+func (h *Histogram) Merge(other *Histogram) error {
+	if other.lowest != h.lowest || other.highest != h.highest || other.sigFigs != h.sigFigs {
+		return fmt.Errorf("histogram: cannot merge histograms with different ranges")
+	}
+	if other.total == 0 {
+		return nil
+	}
+	for i, c := range other.counts {
+		h.counts[i] += c
+	}
+	if h.total == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+	h.total += other.total
+	h.sum += other.sum
+	h.sumSq += other.sumSq
+	h.overflow += other.overflow
+	return nil
+}
+
+// Reset 모든 기록을 지운다.
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+	h.total, h.min, h.max, h.sum, h.sumSq, h.overflow = 0, 0, 0, 0, 0, 0
+}
+
+// Count 기록한 값의 수
+func (h *Histogram) Count() int64 { return h.total }
+
+// Overflow Highest 보다 커서 Highest 로 센 값의 수
+func (h *Histogram) Overflow() int64 { return h.overflow }
+
+// Min 기록한 가장 작은 값
+func (h *Histogram) Min() time.Duration { return h.min }
+
+// Max 기록한 가장 큰 값
+func (h *Histogram) Max() time.Duration { return h.max }
+
+// Sum 기록한 값의 합
+func (h *Histogram) Sum() time.Duration { return time.Duration(h.sum) }
+
+// CountAtOrBelow d 이하로 기록된 값의 수. d 가 속한 칸의 값은 모두 d 이하로 센다.
+func (h *Histogram) CountAtOrBelow(d time.Duration) int64 {
+	if d < 0 {
+		return 0
+	}
+	last := len(h.counts) - 1
+	if d <= h.highest {
+		last = h.index(h.units(d))
+	}
+	var n int64
+	for _, c := range h.counts[:last+1] {
+		n += c
+	}
+	return n
+}
+
+// Mean 기록한 값의 평균
+func (h *Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return time.Duration(h.sum / float64(h.total))
+}
+
+// StdDev 기록한 값의 표준편차
+func (h *Histogram) StdDev() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	mean := h.sum / float64(h.total)
+	variance := h.sumSq/float64(h.total) - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	return time.Duration(math.Sqrt(variance))
+}
+
+// Percentile p (0-100) 백분위수. 해당 칸의 가장 큰 값을 돌려주되 Max 를 넘지 않는다.
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	p = math.Min(math.Max(p, 0), 100)
+	rank := int64(math.Ceil(p / 100 * float64(h.total)))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			_, high := h.bounds(i)
+			if v := time.Duration(high) * h.lowest; v < h.max {
+				return max(v, h.min)
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+// Bucket 값이 있는 칸 하나. Low 이상 High 이하의 값이 Count 개 기록됐다.
+type Bucket struct {
+	Low   time.Duration `json:"low_ns"`
+	High  time.Duration `json:"high_ns"`
+	Count int64         `json:"count"`
+}
+
+// Buckets 값이 있는 칸을 작은 값부터 돌려준다.
+func (h *Histogram) Buckets() []Bucket {
+	var buckets []Bucket
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		low, high := h.bounds(i)
+		buckets = append(buckets, Bucket{
+			Low:   time.Duration(low) * h.lowest,
+			High:  time.Duration(high)*h.lowest + h.lowest - 1,
+			Count: c,
+		})
+	}
+	return buckets
+}
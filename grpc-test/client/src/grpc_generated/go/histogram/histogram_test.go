@@ -0,0 +1,127 @@
+package histogram
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestIndexBounds(t *testing.T) {
+	h := New(time.Microsecond, time.Minute, 3)
+	for _, v := range []uint64{0, 1, 2047, 2048, 2049, 4095, 4096, 123456, 59_999_999} {
+		low, high := h.bounds(h.index(v))
+		if v < low || v > high {
+			t.Errorf("value %d in bucket [%d, %d]", v, low, high)
+		}
+		// 유효 숫자 3 자리: 칸의 폭은 값의 1/1000 이하다.
+		if v > 0 && float64(high-low)/float64(v) > 1e-3 {
+			t.Errorf("bucket [%d, %d] is too wide for %d", low, high, v)
+		}
+	}
+	if got, want := len(h.counts), h.index(60_000_000)+1; got != want {
+		t.Errorf("len(counts) = %d, want %d", got, want)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	h := NewLatency()
+	for i := 1; i <= 10000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 5000 * time.Millisecond},
+		{90, 9000 * time.Millisecond},
+		{99, 9900 * time.Millisecond},
+		{99.9, 9990 * time.Millisecond},
+		{100, 10000 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		got := h.Percentile(tt.p)
+		if rel := math.Abs(float64(got-tt.want)) / float64(tt.want); rel > 1e-3 {
+			t.Errorf("Percentile(%v) = %v, want %v within 0.1%%", tt.p, got, tt.want)
+		}
+	}
+	if h.Min() != time.Millisecond || h.Max() != 10*time.Second {
+		t.Errorf("Min, Max = %v, %v", h.Min(), h.Max())
+	}
+	if h.Mean() != 5000500*time.Microsecond {
+		t.Errorf("Mean = %v, want 5.0005s", h.Mean())
+	}
+	// 1..n 의 표준편차는 sqrt((n^2-1)/12)
+	want := math.Sqrt((10000.0*10000-1)/12) * float64(time.Millisecond)
+	if rel := math.Abs(float64(h.StdDev())-want) / want; rel > 1e-6 {
+		t.Errorf("StdDev = %v, want %v", h.StdDev(), time.Duration(want))
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b, all := NewLatency(), NewLatency(), NewLatency()
+	for i := 1; i <= 1000; i++ {
+		d := time.Duration(i*i) * time.Microsecond
+		all.Record(d)
+		if i%2 == 0 {
+			a.Record(d)
+		} else {
+			b.Record(d)
+		}
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []float64{1, 50, 95, 99.9} {
+		if a.Percentile(p) != all.Percentile(p) {
+			t.Errorf("merged Percentile(%v) = %v, want %v", p, a.Percentile(p), all.Percentile(p))
+		}
+	}
+	if a.Count() != all.Count() || a.Min() != all.Min() || a.Max() != all.Max() || a.Mean() != all.Mean() {
+		t.Errorf("merged histogram differs: count %d, min %v, max %v, mean %v", a.Count(), a.Min(), a.Max(), a.Mean())
+	}
+	if err := a.Merge(New(time.Millisecond, time.Hour, 2)); err == nil {
+		t.Error("Merge accepted a histogram with a different range")
+	}
+}
+
+func TestOverflowAndBuckets(t *testing.T) {
+	h := New(time.Millisecond, time.Second, 2)
+	h.Record(5 * time.Millisecond)
+	h.RecordN(5*time.Millisecond, 2)
+	h.Record(time.Hour)
+	if h.Count() != 4 || h.Overflow() != 1 || h.Max() != time.Second {
+		t.Errorf("Count, Overflow, Max = %d, %d, %v", h.Count(), h.Overflow(), h.Max())
+	}
+	buckets := h.Buckets()
+	if len(buckets) != 2 || buckets[0].Count != 3 || buckets[0].Low != 5*time.Millisecond {
+		t.Errorf("Buckets = %+v", buckets)
+	}
+	h.Reset()
+	if h.Count() != 0 || len(h.Buckets()) != 0 || h.Percentile(50) != 0 {
+		t.Error("Reset left values behind")
+	}
+}
+
+func TestSumAndCountAtOrBelow(t *testing.T) {
+	h := NewLatency()
+	for _, d := range []time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond} {
+		h.Record(d)
+	}
+	if h.Sum() != 111*time.Millisecond {
+		t.Errorf("Sum = %v", h.Sum())
+	}
+	for _, c := range []struct {
+		d    time.Duration
+		want int64
+	}{
+		{-time.Second, 0},
+		{500 * time.Microsecond, 0},
+		{time.Millisecond, 1},
+		{50 * time.Millisecond, 2},
+		{time.Hour, 3},
+	} {
+		if got := h.CountAtOrBelow(c.d); got != c.want {
+			t.Errorf("CountAtOrBelow(%v) = %d, want %d", c.d, got, c.want)
+		}
+	}
+}
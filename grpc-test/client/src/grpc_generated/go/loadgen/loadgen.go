@@ -0,0 +1,281 @@
+// Package loadgen 은 부하 테스트용 요청 스케줄러와 부하 프로필을 제공한다.
+//
+// ClosedLoop 은 클라이언트마다 응답을 받은 뒤 다음 요청을 보내고 (closed-loop),
+// OpenLoop 은 응답을 기다리지 않고 목표 RPS 에 맞춰 요청을 보낸다 (open-loop).
+// OpenLoop 은 요청마다 예정 전송 시각과 실제 전송 시각을 기록하므로, 전송이 밀린 만큼을 포함한
+// coordinated omission 보정 레이턴시를 구할 수 있다.
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+)
+
+// Request 요청 하나를 보내고 응답을 기다린다.
+type Request func(ctx context.Context) error
+
+// Sample 요청 하나의 예정 전송 시각, 실제 전송 시각, 완료 시각과 결과
+type Sample struct {
+	Intended time.Time
+	Sent     time.Time
+	Done     time.Time
+	Err      error
+}
+
+// Latency 실제 전송부터 완료까지의 시간 (서비스 시간)
+func (s Sample) Latency() time.Duration { return s.Done.Sub(s.Sent) }
+
+// CorrectedLatency 예정 전송 시각부터 완료까지의 시간. 전송이 밀린 시간까지 포함한다.
+func (s Sample) CorrectedLatency() time.Duration { return s.Done.Sub(s.Intended) }
+
+// SendDelay 예정 전송 시각보다 실제 전송이 늦어진 시간
+func (s Sample) SendDelay() time.Duration { return s.Sent.Sub(s.Intended) }
+
+// OpenLoop 목표 RPS 로 요청을 보내는 스케줄러
+type OpenLoop struct {
+	// Rate 초당 요청 수
+	Rate float64
+	// MaxInFlight 동시에 처리 중인 요청 수의 상한. 0 이면 제한하지 않는다.
+	// 상한에 걸려 늦게 보낸 요청은 CorrectedLatency 에 지연이 반영된다.
+	MaxInFlight int
+}
+
+// Run duration 동안 i/Rate 초마다 req 를 호출하고, 모든 요청이 끝나면 반환한다.
+// 이전 요청의 완료를 기다리지 않으며, 뒤처진 요청은 즉시 보낸다.
+// record 는 요청이 끝날 때마다 호출되며 동시에 호출되지 않는다.
+func (o OpenLoop) Run(ctx context.Context, duration time.Duration, req Request, record func(Sample)) error {
+	if o.Rate <= 0 {
+		return fmt.Errorf("loadgen: invalid rate %v", o.Rate)
+	}
+	profile := Profile{{Duration: duration, Value: o.Rate}}
+	return o.RunProfile(ctx, profile, req, func(_ int, s Sample) { record(s) })
+}
+
+// RunProfile Rate 대신 profile 의 구간별 RPS 로 요청을 보낸다.
+// 램프 구간에서는 RPS 가 선형으로 바뀌도록 전송 시각을 계산한다.
+// record 는 요청이 속한 구간 번호와 함께 호출된다.
+func (o OpenLoop) RunProfile(ctx context.Context, profile Profile, req Request, record func(stage int, s Sample)) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	var sem chan struct{}
+	if o.MaxInFlight > 0 {
+		sem = make(chan struct{}, o.MaxInFlight)
+	}
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	sched := schedule{profile: profile}
+	start := time.Now()
+	for {
+		at, stage, ok := sched.next()
+		if !ok {
+			break
+		}
+		intended := start.Add(at)
+		if wait := time.Until(intended); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				wg.Wait()
+				return ctx.Err()
+			}
+		}
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return ctx.Err()
+			}
+		}
+
+		wg.Add(1)
+		go func(stage int, intended, sent time.Time) {
+			defer wg.Done()
+			err := req(ctx)
+			done := time.Now()
+			if sem != nil {
+				<-sem
+			}
+			mu.Lock()
+			record(stage, Sample{Intended: intended, Sent: sent, Done: done, Err: err})
+			mu.Unlock()
+		}(stage, intended, time.Now())
+	}
+	wg.Wait()
+	return nil
+}
+
+// ClosedLoop 클라이언트마다 이전 응답을 받은 뒤 다음 요청을 보내는 스케줄러
+type ClosedLoop struct {
+	// Interval 클라이언트가 요청을 보내는 주기. 응답이 더 늦으면 곧바로 다음 요청을 보낸다.
+	// 0 이면 쉬지 않고 보낸다.
+	Interval time.Duration
+}
+
+// controlInterval ClosedLoop 가 목표 클라이언트 수를 다시 맞추는 주기
+const controlInterval = 100 * time.Millisecond
+
+// Run profile 의 구간별 값만큼 클라이언트를 유지하며 요청을 보내고, 모든 요청이 끝나면 반환한다.
+// 클라이언트 수는 controlInterval 마다 반올림한 목표 값에 맞춘다.
+// closed-loop 이므로 Sample 의 Intended 와 Sent 는 같다.
+// record 는 요청이 속한 구간 번호와 함께 호출되며 동시에 호출되지 않는다.
+func (c ClosedLoop) Run(ctx context.Context, profile Profile, req Request, record func(stage int, s Sample)) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		running []bool
+		target  int
+	)
+	stop := make(chan struct{})
+	start := time.Now()
+
+	worker := func(id int) {
+		defer wg.Done()
+		defer func() {
+			mu.Lock()
+			running[id] = false
+			mu.Unlock()
+		}()
+		for {
+			mu.Lock()
+			active := id < target
+			mu.Unlock()
+			if !active {
+				return
+			}
+			sent := time.Now()
+			stage, _, ok := profile.At(sent.Sub(start))
+			if !ok {
+				return
+			}
+			err := req(ctx)
+			done := time.Now()
+			mu.Lock()
+			record(stage, Sample{Intended: sent, Sent: sent, Done: done, Err: err})
+			mu.Unlock()
+
+			select {
+			case <-time.After(time.Until(sent.Add(c.Interval))):
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	ticker := time.NewTicker(controlInterval)
+	defer ticker.Stop()
+	var err error
+loop:
+	for {
+		_, value, ok := profile.At(time.Since(start))
+		if !ok {
+			break
+		}
+		n := int(math.Round(value))
+		mu.Lock()
+		target = n
+		for len(running) < n {
+			running = append(running, false)
+		}
+		for id := 0; id < n; id++ {
+			if !running[id] {
+				running[id] = true
+				wg.Add(1)
+				go worker(id)
+			}
+		}
+		mu.Unlock()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		}
+	}
+	mu.Lock()
+	target = 0
+	mu.Unlock()
+	close(stop)
+	wg.Wait()
+	return err
+}
+
+// Stats Sample 들의 요약. 레이턴시는 히스토그램에 모으므로 요청 수와 상관없이 메모리가 일정하다.
+type Stats struct {
+	Count     int
+	Errors    int
+	First     time.Time
+	Last      time.Time
+	latencies *histogram.Histogram
+	corrected *histogram.Histogram
+	sendDelay time.Duration
+}
+
+// Add 성공한 요청은 레이턴시에, 실패한 요청은 Errors 에 더한다.
+func (s *Stats) Add(sample Sample) {
+	if s.latencies == nil {
+		s.latencies, s.corrected = histogram.NewLatency(), histogram.NewLatency()
+	}
+	if s.Count == 0 || sample.Intended.Before(s.First) {
+		s.First = sample.Intended
+	}
+	if sample.Done.After(s.Last) {
+		s.Last = sample.Done
+	}
+	s.Count++
+	if sample.Err != nil {
+		s.Errors++
+		return
+	}
+	s.latencies.Record(sample.Latency())
+	s.corrected.Record(sample.CorrectedLatency())
+	if d := sample.SendDelay(); d > s.sendDelay {
+		s.sendDelay = d
+	}
+}
+
+// Throughput 첫 예정 전송부터 마지막 완료까지 초당 성공한 요청 수
+func (s *Stats) Throughput() float64 {
+	elapsed := s.Last.Sub(s.First).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Count-s.Errors) / elapsed
+}
+
+// Percentile 서비스 시간의 p 분위수 (0 < p <= 1)
+func (s *Stats) Percentile(p float64) time.Duration { return percentile(s.latencies, p) }
+
+// CorrectedPercentile coordinated omission 을 보정한 레이턴시의 p 분위수
+func (s *Stats) CorrectedPercentile(p float64) time.Duration { return percentile(s.corrected, p) }
+
+// MaxSendDelay 가장 많이 밀린 전송 지연
+func (s *Stats) MaxSendDelay() time.Duration { return s.sendDelay }
+
+func percentile(h *histogram.Histogram, p float64) time.Duration {
+	if h == nil {
+		return 0
+	}
+	return h.Percentile(p * 100)
+}
@@ -0,0 +1,85 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOpenLoopSchedulesRegardlessOfCompletion(t *testing.T) {
+	// 요청 하나가 50ms 걸려도 200 RPS 로 200ms 동안 40 개를 예정대로 보낸다.
+	var samples []Sample
+	loop := OpenLoop{Rate: 200}
+	err := loop.Run(context.Background(), 200*time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}, func(s Sample) { samples = append(samples, s) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 40 {
+		t.Fatalf("got %d samples, want 40", len(samples))
+	}
+	for _, s := range samples {
+		if offset := s.Intended.Sub(samples[0].Intended) % (5 * time.Millisecond); offset != 0 {
+			t.Fatalf("intended send time %v is not on the 5ms schedule", s.Intended)
+		}
+		if s.Latency() < 50*time.Millisecond || s.CorrectedLatency() < s.Latency() {
+			t.Fatalf("sample %+v: latency %v, corrected %v", s, s.Latency(), s.CorrectedLatency())
+		}
+	}
+}
+
+func TestOpenLoopCorrectsCoordinatedOmission(t *testing.T) {
+	// 한 번에 하나만 보낼 수 있으면 뒤 요청들은 밀리고, 보정 레이턴시가 그 지연을 포함한다.
+	var stats Stats
+	loop := OpenLoop{Rate: 100, MaxInFlight: 1}
+	err := loop.Run(context.Background(), 100*time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}, stats.Add)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != 10 {
+		t.Fatalf("Count = %d, want 10", stats.Count)
+	}
+	if p := stats.Percentile(1); p > 100*time.Millisecond {
+		t.Errorf("max service time = %v, want about 20ms", p)
+	}
+	// 마지막 요청은 90ms 에 예정됐지만 약 180ms 에 보내져 약 200ms 에 끝난다.
+	if p := stats.CorrectedPercentile(1); p < 80*time.Millisecond {
+		t.Errorf("max corrected latency = %v, want it to include the send delay", p)
+	}
+	if stats.MaxSendDelay() < 60*time.Millisecond {
+		t.Errorf("MaxSendDelay = %v, want at least 60ms", stats.MaxSendDelay())
+	}
+}
+
+func TestStats(t *testing.T) {
+	var stats Stats
+	start := time.Now()
+	for i := 1; i <= 100; i++ {
+		sent := start.Add(time.Duration(i) * time.Millisecond)
+		stats.Add(Sample{Intended: sent, Sent: sent, Done: sent.Add(time.Duration(i) * time.Millisecond)})
+	}
+	stats.Add(Sample{Intended: start, Sent: start, Done: start, Err: errors.New("unavailable")})
+
+	if stats.Count != 101 || stats.Errors != 1 {
+		t.Errorf("Count, Errors = %d, %d, want 101, 1", stats.Count, stats.Errors)
+	}
+	// 히스토그램의 유효 숫자는 3 자리다.
+	if p := stats.Percentile(0.5); p < 50*time.Millisecond || p > 50050*time.Microsecond {
+		t.Errorf("p50 = %v, want 50ms", p)
+	}
+	if p := stats.Percentile(0.99); p < 99*time.Millisecond || p > 99100*time.Microsecond {
+		t.Errorf("p99 = %v, want 99ms", p)
+	}
+}
+
+func TestOpenLoopInvalidRate(t *testing.T) {
+	if err := (OpenLoop{}).Run(context.Background(), time.Second, nil, nil); err == nil {
+		t.Error("Run with rate 0 succeeded")
+	}
+}
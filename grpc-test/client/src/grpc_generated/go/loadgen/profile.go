@@ -0,0 +1,232 @@
+package loadgen
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stage 부하 프로필의 한 구간. Value 는 open-loop 에서는 RPS, closed-loop 에서는 동시 클라이언트 수다.
+type Stage struct {
+	Name     string        `yaml:"name"`
+	Duration time.Duration `yaml:"duration"`
+	Value    float64       `yaml:"value"`
+	// Ramp 이면 구간 동안 From 에서 Value 까지 선형으로 바꾼다.
+	Ramp bool    `yaml:"ramp"`
+	From float64 `yaml:"from"`
+}
+
+// start 구간 시작 시점의 값
+func (s Stage) start() float64 {
+	if s.Ramp {
+		return s.From
+	}
+	return s.Value
+}
+
+// at 구간 시작부터 elapsed 가 지난 시점의 값
+func (s Stage) at(elapsed time.Duration) float64 {
+	if !s.Ramp || s.Duration <= 0 {
+		return s.Value
+	}
+	frac := math.Min(math.Max(elapsed.Seconds()/s.Duration.Seconds(), 0), 1)
+	return s.From + (s.Value-s.From)*frac
+}
+
+// Target 보고서에 쓸 목표 값. 램프는 "10→100" 처럼 표시한다.
+func (s Stage) Target() string {
+	if s.Ramp {
+		return fmt.Sprintf("%g→%g", s.From, s.Value)
+	}
+	return fmt.Sprintf("%g", s.Value)
+}
+
+// Profile 차례로 실행할 구간 목록
+type Profile []Stage
+
+// Duration 전체 구간의 길이
+func (p Profile) Duration() time.Duration {
+	var total time.Duration
+	for _, s := range p {
+		total += s.Duration
+	}
+	return total
+}
+
+// At 시작부터 elapsed 가 지난 시점의 구간 번호와 값. 프로필이 끝났으면 ok 가 false 다.
+func (p Profile) At(elapsed time.Duration) (stage int, value float64, ok bool) {
+	for i, s := range p {
+		if elapsed < s.Duration {
+			return i, s.at(elapsed), true
+		}
+		elapsed -= s.Duration
+	}
+	return len(p), 0, false
+}
+
+// Validate 구간 길이와 값이 올바른지 확인한다.
+func (p Profile) Validate() error {
+	if len(p) == 0 {
+		return fmt.Errorf("loadgen: empty profile")
+	}
+	for i, s := range p {
+		if s.Duration <= 0 {
+			return fmt.Errorf("loadgen: stage %d (%s): invalid duration %v", i, s.Name, s.Duration)
+		}
+		if s.Value < 0 || s.From < 0 {
+			return fmt.Errorf("loadgen: stage %d (%s): negative value", i, s.Name)
+		}
+	}
+	return nil
+}
+
+// schedule open-loop 의 n 번째 요청을 보낼 시각을 차례로 계산한다.
+// 구간마다 누적 요청 수 N(t) = r0*t + (r1-r0)/(2D)*t^2 를 n 에 대해 풀어 구한다.
+type schedule struct {
+	profile Profile
+	stage   int
+	offset  time.Duration // 현재 구간의 시작 시각
+	base    float64       // 현재 구간 이전까지의 누적 요청 수
+	n       int
+}
+
+// next 다음 요청의 예정 시각 (프로필 시작 기준) 과 구간 번호. 프로필이 끝났으면 ok 가 false 다.
+func (s *schedule) next() (at time.Duration, stage int, ok bool) {
+	for s.stage < len(s.profile) {
+		st := s.profile[s.stage]
+		d := st.Duration.Seconds()
+		r0, r1 := st.start(), st.at(st.Duration)
+		x := float64(s.n) - s.base
+		if total := (r0 + r1) / 2 * d; x < total-1e-9 {
+			var t float64
+			if a := (r1 - r0) / (2 * d); a == 0 {
+				t = x / r0
+			} else {
+				t = (-r0 + math.Sqrt(r0*r0+4*a*x)) / (2 * a)
+			}
+			s.n++
+			return s.offset + time.Duration(t*float64(time.Second)), s.stage, true
+		} else {
+			s.base += total
+		}
+		s.offset += st.Duration
+		s.stage++
+	}
+	return 0, s.stage, false
+}
+
+// ParseProfile 간단한 표기로 자주 쓰는 프로필을 만든다. 값은 모드에 따라 RPS 또는 동시 클라이언트 수다.
+//
+//	ramp:FROM-TO:DURATION            FROM 에서 TO 까지 선형 증가     ramp:0-100:5m
+//	step:START+INC*COUNT:DURATION    DURATION 마다 INC 씩 COUNT 단계  step:10+10*5:1m
+//	spike:BASE-PEAK:BEFORE,SPIKE,AFTER  BASE 유지 후 잠깐 PEAK       spike:10-200:1m,10s,1m
+//	soak:VALUE:DURATION              VALUE 로 오래 유지             soak:50:1h
+func ParseProfile(spec string) (Profile, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("loadgen: profile %q: want KIND:VALUES:DURATIONS", spec)
+	}
+	kind, values, durations := parts[0], parts[1], parts[2]
+	bad := func(err error) (Profile, error) {
+		return nil, fmt.Errorf("loadgen: profile %q: %w", spec, err)
+	}
+
+	var p Profile
+	switch kind {
+	case "ramp":
+		v, err := parseFloats(values, "-", 2)
+		if err != nil {
+			return bad(err)
+		}
+		d, err := parseDurations(durations, 1)
+		if err != nil {
+			return bad(err)
+		}
+		p = Profile{{Name: "ramp", Duration: d[0], From: v[0], Value: v[1], Ramp: true}}
+	case "step":
+		start, rest, ok := strings.Cut(values, "+")
+		inc, count, ok2 := strings.Cut(rest, "*")
+		if !ok || !ok2 {
+			return bad(fmt.Errorf("want START+INC*COUNT"))
+		}
+		v, err := parseFloats(start+","+inc, ",", 2)
+		if err != nil {
+			return bad(err)
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n <= 0 {
+			return bad(fmt.Errorf("invalid step count %q", count))
+		}
+		d, err := parseDurations(durations, 1)
+		if err != nil {
+			return bad(err)
+		}
+		for i := 0; i < n; i++ {
+			p = append(p, Stage{Name: fmt.Sprintf("step-%d", i+1), Duration: d[0], Value: v[0] + float64(i)*v[1]})
+		}
+	case "spike":
+		v, err := parseFloats(values, "-", 2)
+		if err != nil {
+			return bad(err)
+		}
+		d, err := parseDurations(durations, 3)
+		if err != nil {
+			return bad(err)
+		}
+		p = Profile{
+			{Name: "base", Duration: d[0], Value: v[0]},
+			{Name: "spike", Duration: d[1], Value: v[1]},
+			{Name: "recovery", Duration: d[2], Value: v[0]},
+		}
+	case "soak":
+		v, err := parseFloats(values, "-", 1)
+		if err != nil {
+			return bad(err)
+		}
+		d, err := parseDurations(durations, 1)
+		if err != nil {
+			return bad(err)
+		}
+		p = Profile{{Name: "soak", Duration: d[0], Value: v[0]}}
+	default:
+		return bad(fmt.Errorf("unknown kind %q (want ramp, step, spike or soak)", kind))
+	}
+	if err := p.Validate(); err != nil {
+		return bad(err)
+	}
+	return p, nil
+}
+
+func parseFloats(s, sep string, n int) ([]float64, error) {
+	fields := strings.Split(s, sep)
+	if len(fields) != n {
+		return nil, fmt.Errorf("want %d values in %q", n, s)
+	}
+	values := make([]float64, n)
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func parseDurations(s string, n int) ([]time.Duration, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != n {
+		return nil, fmt.Errorf("want %d durations in %q", n, s)
+	}
+	durations := make([]time.Duration, n)
+	for i, f := range fields {
+		d, err := time.ParseDuration(f)
+		if err != nil {
+			return nil, err
+		}
+		durations[i] = d
+	}
+	return durations, nil
+}
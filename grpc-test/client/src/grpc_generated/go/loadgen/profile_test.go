@@ -0,0 +1,137 @@
+package loadgen
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseProfile(t *testing.T) {
+	tests := []struct {
+		spec     string
+		names    []string
+		values   []float64
+		duration time.Duration
+	}{
+		{"ramp:0-100:5m", []string{"ramp"}, []float64{100}, 5 * time.Minute},
+		{"step:10+10*3:1m", []string{"step-1", "step-2", "step-3"}, []float64{10, 20, 30}, 3 * time.Minute},
+		{"spike:10-200:1m,10s,1m", []string{"base", "spike", "recovery"}, []float64{10, 200, 10}, 130 * time.Second},
+		{"soak:50:1h", []string{"soak"}, []float64{50}, time.Hour},
+	}
+	for _, tt := range tests {
+		p, err := ParseProfile(tt.spec)
+		if err != nil {
+			t.Errorf("ParseProfile(%q): %v", tt.spec, err)
+			continue
+		}
+		if len(p) != len(tt.names) {
+			t.Errorf("ParseProfile(%q) has %d stages, want %d", tt.spec, len(p), len(tt.names))
+			continue
+		}
+		for i, s := range p {
+			if s.Name != tt.names[i] || s.Value != tt.values[i] {
+				t.Errorf("ParseProfile(%q)[%d] = %+v, want %s %v", tt.spec, i, s, tt.names[i], tt.values[i])
+			}
+		}
+		if p.Duration() != tt.duration {
+			t.Errorf("ParseProfile(%q).Duration() = %v, want %v", tt.spec, p.Duration(), tt.duration)
+		}
+	}
+
+	for _, spec := range []string{"", "ramp:100:5m", "step:10+10:1m", "spike:10-200:1m", "soak:50:0s", "burst:1:1s", "soak:-1:1m"} {
+		if _, err := ParseProfile(spec); err == nil {
+			t.Errorf("ParseProfile(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestProfileAt(t *testing.T) {
+	p := Profile{
+		{Name: "ramp", Duration: 10 * time.Second, From: 0, Value: 100, Ramp: true},
+		{Name: "hold", Duration: 10 * time.Second, Value: 100},
+	}
+	tests := []struct {
+		elapsed time.Duration
+		stage   int
+		value   float64
+		ok      bool
+	}{
+		{0, 0, 0, true},
+		{5 * time.Second, 0, 50, true},
+		{15 * time.Second, 1, 100, true},
+		{20 * time.Second, 2, 0, false},
+	}
+	for _, tt := range tests {
+		stage, value, ok := p.At(tt.elapsed)
+		if stage != tt.stage || value != tt.value || ok != tt.ok {
+			t.Errorf("At(%v) = %d, %v, %v, want %d, %v, %v", tt.elapsed, stage, value, ok, tt.stage, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestScheduleRamp(t *testing.T) {
+	// 0 → 100 RPS 로 1초 램프 후 100 RPS 로 1초: 50 + 100 개
+	sched := schedule{profile: Profile{
+		{Duration: time.Second, Value: 100, Ramp: true},
+		{Duration: time.Second, Value: 100},
+	}}
+	counts := make([]int, 2)
+	var times []time.Duration
+	for {
+		at, stage, ok := sched.next()
+		if !ok {
+			break
+		}
+		counts[stage]++
+		times = append(times, at)
+	}
+	if counts[0] != 50 || counts[1] != 100 {
+		t.Fatalf("requests per stage = %v, want [50 100]", counts)
+	}
+	// 램프에서 n 번째 요청은 50t^2 = n 인 t 에 보낸다.
+	if want := time.Duration(math.Sqrt(25.0/50) * float64(time.Second)); times[25]-want > time.Microsecond || want-times[25] > time.Microsecond {
+		t.Errorf("request 25 at %v, want %v", times[25], want)
+	}
+	if times[50] != time.Second || times[60] != 1100*time.Millisecond {
+		t.Errorf("hold stage requests at %v and %v, want 1s and 1.1s", times[50], times[60])
+	}
+}
+
+func TestClosedLoopFollowsProfile(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     = make([]int, 2)
+		counts   = make([]int, 2)
+	)
+	profile := Profile{
+		{Name: "step-1", Duration: 300 * time.Millisecond, Value: 2},
+		{Name: "step-2", Duration: 300 * time.Millisecond, Value: 4},
+	}
+	start := time.Now()
+	err := ClosedLoop{}.Run(context.Background(), profile, func(ctx context.Context) error {
+		mu.Lock()
+		inFlight++
+		stage, _, _ := profile.At(time.Since(start))
+		if stage < 2 && inFlight > peak[stage] {
+			peak[stage] = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return nil
+	}, func(stage int, s Sample) { counts[stage]++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peak[0] != 2 || peak[1] != 4 {
+		t.Errorf("peak concurrency per stage = %v, want [2 4]", peak)
+	}
+	if counts[0] == 0 || counts[1] <= counts[0] {
+		t.Errorf("requests per stage = %v, want more requests in the second stage", counts)
+	}
+}
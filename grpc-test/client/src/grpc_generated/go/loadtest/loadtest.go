@@ -0,0 +1,160 @@
+// Package loadtest 는 config.LoadConfig 대로 vitpose 모델에 부하를 주고 결과 표를 쓴다.
+// cmd/client, cmd/client-all 과 루트의 client.go 가 같은 방식으로 부하를 주도록 함께 쓴다.
+// cmd/client-all 과 루트의 client.go 는 본문 전체를 Standalone 으로 공유한다.
+//
+// Stages 가 있으면 구간별 프로필로, Mode 가 open 이면 Rate 에 맞춘 open-loop 로,
+// 아니면 Concurrency 명의 클라이언트가 1초에 한 번씩 요청하는 closed-loop 로 Duration 동안 돈다.
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/loadgen"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/preprocess"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+// ClientInterval closed-loop 클라이언트가 요청을 보내는 주기
+const ClientInterval = time.Second
+
+// Runner 부하 테스트 한 번의 설정
+type Runner struct {
+	Config *config.Config
+	Client *vitpose.Client
+	// Batch 보낼 배치. nil 이면 Config.Model.BatchSize 장의 난수 배치를 한 번 만들어 계속 보낸다.
+	// 요청마다 만들면 난수 생성 시간이 레이턴시에 섞인다.
+	Batch *vitpose.Batch
+	// Request 요청 하나. nil 이면 Client 로 Batch 를 보내고 실패를 로그로 남긴다.
+	// 결과를 집계 서버로 보내는 클라이언트가 바꾸며, 이때 실패 로그는 Request 가 남긴다.
+	Request loadgen.Request
+	Out     io.Writer
+}
+
+// Run Config.Load 대로 부하를 주고 결과 표를 Out 에 쓴다.
+func (r *Runner) Run(ctx context.Context) error {
+	if r.Batch == nil {
+		r.Batch = vitpose.RandomBatch(r.Config.Model.BatchSize)
+	}
+	if r.Request == nil {
+		r.Request = func(ctx context.Context) error {
+			_, err := r.Client.Infer(ctx, r.Batch)
+			if err != nil {
+				log.Printf("InferRequest 처리 오류: %v", err)
+			}
+			return err
+		}
+	}
+	load := r.Config.Load
+	switch {
+	case len(load.Stages) > 0:
+		return r.runProfile(ctx, load.Stages)
+	case load.Mode == config.OpenLoop:
+		return r.runOpenLoop(ctx)
+	default:
+		return r.runProfile(ctx, loadgen.Profile{{Name: "closed", Duration: load.Duration, Value: float64(load.Concurrency)}})
+	}
+}
+
+// Standalone 집계 서버 없이 cfg.Server.URL 에 붙어 부하를 주고 결과 표와 재시도 지표를 w 에 쓴다.
+func Standalone(ctx context.Context, cfg *config.Config, w io.Writer) error {
+	retryPolicy := cfg.LoadRetryPolicy()
+	conn, err := cfg.Dial(retryPolicy.DialOption())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := vitpose.NewClient(triton.NewGRPCInferenceServiceClient(conn), cfg.VitposeOptions()...)
+
+	var batch *vitpose.Batch
+	if cfg.Load.ImagePath != "" {
+		batch, err = preprocess.LoadBatch(cfg.Load.ImagePath, cfg.Model.BatchSize)
+		if err != nil {
+			return fmt.Errorf("loadtest: couldn't load image %s: %w", cfg.Load.ImagePath, err)
+		}
+	}
+
+	runner := &Runner{Config: cfg, Client: client, Batch: batch, Out: w}
+	if err := runner.Run(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "모든 클라이언트의 테스트가 완료되었습니다.")
+	fmt.Fprintln(w, "재시도:")
+	retryPolicy.Metrics.WriteText(w)
+	return nil
+}
+
+// runOpenLoop 응답과 상관없이 Load.Rate 에 맞춰 요청을 보내고,
+// 서비스 시간과 coordinated omission 을 보정한 레이턴시를 함께 쓴다.
+func (r *Runner) runOpenLoop(ctx context.Context) error {
+	load := r.Config.Load
+	var stats loadgen.Stats
+	loop := loadgen.OpenLoop{Rate: load.Rate, MaxInFlight: load.MaxInFlight}
+	err := loop.Run(ctx, load.Duration, r.Request, stats.Add)
+	if err != nil {
+		return err
+	}
+
+	w := r.Out
+	fmt.Fprintf(w, "Open-loop 결과 (목표 %.1f RPS, %v)\n", load.Rate, load.Duration)
+	fmt.Fprintf(w, "요청 %d, 실패 %d, 처리량 %.1f RPS, 최대 전송 지연 %v\n",
+		stats.Count, stats.Errors, stats.Throughput(), stats.MaxSendDelay())
+	fmt.Fprintln(w, "---------------------------------------------------------")
+	fmt.Fprintf(w, "%-12s | %-10s | %-10s | %-10s | %-10s\n", "", "P50", "P90", "P99", "Max")
+	fmt.Fprintln(w, "---------------------------------------------------------")
+	fmt.Fprintf(w, "%-12s | %-10d | %-10d | %-10d | %-10d\n", "Service",
+		stats.Percentile(0.5).Milliseconds(), stats.Percentile(0.9).Milliseconds(),
+		stats.Percentile(0.99).Milliseconds(), stats.Percentile(1).Milliseconds())
+	fmt.Fprintf(w, "%-12s | %-10d | %-10d | %-10d | %-10d\n", "Corrected",
+		stats.CorrectedPercentile(0.5).Milliseconds(), stats.CorrectedPercentile(0.9).Milliseconds(),
+		stats.CorrectedPercentile(0.99).Milliseconds(), stats.CorrectedPercentile(1).Milliseconds())
+	fmt.Fprintln(w, "---------------------------------------------------------")
+	return nil
+}
+
+// runProfile profile 의 구간별 RPS (open) 또는 동시 클라이언트 수 (closed) 로 부하를 주고,
+// 구간마다 레이턴시와 에러 통계를 쓴다. open 모드의 레이턴시는 coordinated omission 을 보정한 값이다.
+func (r *Runner) runProfile(ctx context.Context, profile loadgen.Profile) error {
+	load := r.Config.Load
+	stats := make([]loadgen.Stats, len(profile))
+	record := func(stage int, s loadgen.Sample) { stats[stage].Add(s) }
+	var err error
+	if load.Mode == config.OpenLoop {
+		err = loadgen.OpenLoop{MaxInFlight: load.MaxInFlight}.RunProfile(ctx, profile, r.Request, record)
+	} else {
+		err = loadgen.ClosedLoop{Interval: ClientInterval}.Run(ctx, profile, r.Request, record)
+	}
+	if err != nil {
+		return err
+	}
+
+	unit := "Clients"
+	if load.Mode == config.OpenLoop {
+		unit = "RPS"
+	}
+	w := r.Out
+	fmt.Fprintf(w, "구간별 결과 (%s 모드, Target 단위 %s, %v)\n", load.Mode, unit, profile.Duration())
+	fmt.Fprintln(w, "-----------------------------------------------------------------------------------------------")
+	fmt.Fprintf(w, "%-10s | %-9s | %-8s | %-8s | %-6s | %-7s | %-7s | %-7s | %-7s | %-7s\n",
+		"Stage", "Target", "Requests", "Errors", "Err%", "RPS", "P50", "P90", "P99", "Max")
+	fmt.Fprintln(w, "-----------------------------------------------------------------------------------------------")
+	for i, stage := range profile {
+		st := &stats[i]
+		errRate := 0.0
+		if st.Count > 0 {
+			errRate = 100 * float64(st.Errors) / float64(st.Count)
+		}
+		fmt.Fprintf(w, "%-10s | %-9s | %-8d | %-8d | %-6.1f | %-7.1f | %-7d | %-7d | %-7d | %-7d\n",
+			stage.Name, stage.Target(), st.Count, st.Errors, errRate, st.Throughput(),
+			st.CorrectedPercentile(0.5).Milliseconds(), st.CorrectedPercentile(0.9).Milliseconds(),
+			st.CorrectedPercentile(0.99).Milliseconds(), st.CorrectedPercentile(1).Milliseconds())
+	}
+	fmt.Fprintln(w, "-----------------------------------------------------------------------------------------------")
+	return nil
+}
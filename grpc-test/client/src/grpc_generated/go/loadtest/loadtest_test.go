@@ -0,0 +1,106 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/loadgen"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+func TestRun(t *testing.T) {
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository("../../../../../../pose_model_zoo"); err != nil {
+		t.Fatal(err)
+	}
+	server.Start()
+	defer server.Close()
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := vitpose.NewClient(triton.NewGRPCInferenceServiceClient(conn))
+
+	tests := []struct {
+		name   string
+		load   func(*config.LoadConfig)
+		header string
+		// min, max 보낸 요청 수의 범위
+		min, max int64
+	}{
+		// closed-loop 클라이언트는 시작하자마자 한 번 보내고 ClientInterval 뒤에 다시 보낸다.
+		{"closed", func(l *config.LoadConfig) { l.Concurrency = 3 }, "closed     | 3", 3, 3},
+		{"open", func(l *config.LoadConfig) { l.Mode, l.Rate = config.OpenLoop, 100 }, "Open-loop 결과 (목표 100.0 RPS", 15, 25},
+		{"profile", func(l *config.LoadConfig) {
+			l.Mode = config.OpenLoop
+			l.Stages = loadgen.Profile{{Name: "warmup", Duration: 100 * time.Millisecond, Value: 50}, {Name: "peak", Duration: 100 * time.Millisecond, Value: 100}}
+		}, "peak       | 100", 10, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Load.Duration = 200 * time.Millisecond
+			tt.load(&cfg.Load)
+			var out bytes.Buffer
+			var requests atomic.Int64
+			r := &Runner{Config: cfg, Client: client, Out: &out}
+			infer := func(ctx context.Context) error {
+				requests.Add(1)
+				_, err := client.Infer(ctx, r.Batch)
+				return err
+			}
+			r.Request = infer
+			if err := r.Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), tt.header) {
+				t.Errorf("output =\n%s\nwant it to contain %q", out.String(), tt.header)
+			}
+			if n := requests.Load(); n < tt.min || n > tt.max {
+				t.Errorf("sent %d requests, want %d to %d", n, tt.min, tt.max)
+			}
+			if r.Batch == nil || r.Batch.Len() != cfg.Model.BatchSize {
+				t.Errorf("batch = %v, want a random batch of %d", r.Batch, cfg.Model.BatchSize)
+			}
+		})
+	}
+}
+
+func TestStandalone(t *testing.T) {
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository("../../../../../../pose_model_zoo"); err != nil {
+		t.Fatal(err)
+	}
+	addr, err := server.StartTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Server.URL = addr
+	cfg.Load.Concurrency = 2
+	cfg.Load.Duration = 200 * time.Millisecond
+	var out bytes.Buffer
+	if err := Standalone(context.Background(), cfg, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"closed     | 2", "재시도:", "ModelInfer: 2 calls"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output =\n%s\nwant it to contain %q", out.String(), want)
+		}
+	}
+
+	cfg.Load.ImagePath = "missing.png"
+	if err := Standalone(context.Background(), cfg, &out); err == nil || !strings.Contains(err.Error(), "couldn't load image") {
+		t.Errorf("Standalone() with a missing image error = %v", err)
+	}
+}
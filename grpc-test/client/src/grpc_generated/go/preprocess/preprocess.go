@@ -0,0 +1,162 @@
+// Package preprocess 는 JPEG/PNG 이미지를 vitpose "input" 텐서 형식(정규화된 3x256x192 CHW float32)으로 바꾼다.
+package preprocess
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+// ImageNet 평균/표준편차 (RGB, 0~255 스케일)
+var (
+	Mean = [3]float32{0.485 * 255, 0.456 * 255, 0.406 * 255}
+	Std  = [3]float32{0.229 * 255, 0.224 * 255, 0.225 * 255}
+)
+
+// Decode JPEG 또는 PNG 바이트를 이미지로 디코딩한다.
+func Decode(data []byte) (image.Image, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("preprocess: decode image: %w", err)
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, fmt.Errorf("preprocess: unsupported image format %q", format)
+	}
+	return img, nil
+}
+
+// FullImageBox 이미지 전체를 감싸는 Box 를 만든다.
+func FullImageBox(img image.Image) vitpose.Box {
+	bounds := img.Bounds()
+	return vitpose.BoxFromXYWH(0, 0, float32(bounds.Dx()), float32(bounds.Dy()), 1)
+}
+
+// Crop box 영역을 192x256 으로 리사이즈하고 정규화해서 dst 에 CHW 로 쓴다.
+// dst 의 길이는 vitpose.ImageSize 이어야 한다.
+//
+// 출력 픽셀 (u, v) 는 box.FromCrop(u, v), 즉 (Left + u*w/(192-1), Top + v*h/(256-1)) 에 대응한다.
+// 이미지 밖은 0 으로 채운다.
+func Crop(img image.Image, box vitpose.Box, dst []float32) error {
+	if len(dst) != vitpose.ImageSize {
+		return fmt.Errorf("preprocess: dst has %d elements, want %d", len(dst), vitpose.ImageSize)
+	}
+	if box.Scale[0] <= 0 || box.Scale[1] <= 0 {
+		return fmt.Errorf("preprocess: invalid box scale %v", box.Scale)
+	}
+	rgba := toRGBA(img)
+
+	const plane = vitpose.InputHeight * vitpose.InputWidth
+	for v := 0; v < vitpose.InputHeight; v++ {
+		for u := 0; u < vitpose.InputWidth; u++ {
+			sx, sy := box.FromCrop(float32(u), float32(v))
+			r, g, b := bilinear(rgba, sx, sy)
+			i := v*vitpose.InputWidth + u
+			dst[i] = (r - Mean[0]) / Std[0]
+			dst[plane+i] = (g - Mean[1]) / Std[1]
+			dst[2*plane+i] = (b - Mean[2]) / Std[2]
+		}
+	}
+	return nil
+}
+
+// FromImage img 의 box 영역을 텐서 하나로 만든다.
+func FromImage(img image.Image, box vitpose.Box) ([]float32, error) {
+	dst := make([]float32, vitpose.ImageSize)
+	if err := Crop(img, box, dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// FromBytes JPEG/PNG 바이트를 디코딩해서 box 영역을 텐서 하나로 만든다.
+func FromBytes(data []byte, box vitpose.Box) ([]float32, error) {
+	img, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return FromImage(img, box)
+}
+
+// NewBatch images[i] 의 boxes[i] 영역을 잘라 vitpose.Batch 로 묶는다. 키포인트가 원본 좌표로 오도록 Boxes 도 채운다.
+func NewBatch(images []image.Image, boxes []vitpose.Box) (*vitpose.Batch, error) {
+	if len(images) != len(boxes) {
+		return nil, fmt.Errorf("preprocess: %d images but %d boxes", len(images), len(boxes))
+	}
+	batch := vitpose.NewBatch(len(images))
+	batch.Boxes = append([]vitpose.Box(nil), boxes...)
+	for i, img := range images {
+		if err := Crop(img, boxes[i], batch.Image(i)); err != nil {
+			return nil, fmt.Errorf("preprocess: image %d: %w", i, err)
+		}
+	}
+	return batch, nil
+}
+
+// toRGBA 픽셀을 직접 읽을 수 있도록 이미지를 *image.RGBA 로 바꾼다.
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(bounds)
+	draw.Draw(rgba, bounds, img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// bilinear (x, y) 위치의 RGB 값을 양선형 보간으로 구한다. 이미지 밖 픽셀은 0 으로 본다.
+func bilinear(img *image.RGBA, x, y float32) (r, g, b float32) {
+	bounds := img.Bounds()
+	x0, y0 := floor(x), floor(y)
+	fx, fy := x-float32(x0), y-float32(y0)
+
+	weights := [4]float32{(1 - fx) * (1 - fy), fx * (1 - fy), (1 - fx) * fy, fx * fy}
+	points := [4]image.Point{{x0, y0}, {x0 + 1, y0}, {x0, y0 + 1}, {x0 + 1, y0 + 1}}
+	for i, p := range points {
+		if weights[i] == 0 {
+			continue
+		}
+		p = p.Add(bounds.Min)
+		if !p.In(bounds) {
+			continue
+		}
+		offset := img.PixOffset(p.X, p.Y)
+		r += weights[i] * float32(img.Pix[offset])
+		g += weights[i] * float32(img.Pix[offset+1])
+		b += weights[i] * float32(img.Pix[offset+2])
+	}
+	return r, g, b
+}
+
+func floor(v float32) int {
+	i := int(v)
+	if v < 0 && float32(i) != v {
+		i--
+	}
+	return i
+}
+
+// LoadBatch path 의 이미지 전체를 전처리해서 n 장 반복한 Batch 를 만든다. 부하 테스트에서 실제 이미지를 보낼 때 쓴다.
+func LoadBatch(path string, n int) (*vitpose.Batch, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("preprocess: %w", err)
+	}
+	img, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	batch := vitpose.NewBatch(n)
+	if err := Crop(img, FullImageBox(img), batch.Image(0)); err != nil {
+		return nil, err
+	}
+	for i := 1; i < n; i++ {
+		copy(batch.Image(i), batch.Image(0))
+	}
+	return batch, nil
+}
@@ -0,0 +1,238 @@
+package preprocess
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+// pixel dst 의 crop 픽셀 (u, v) 를 정규화 전 RGB 로 되돌린다.
+func pixel(dst []float32, u, v int) [3]float32 {
+	const plane = vitpose.InputHeight * vitpose.InputWidth
+	i := v*vitpose.InputWidth + u
+	var rgb [3]float32
+	for c := range rgb {
+		rgb[c] = dst[c*plane+i]*Std[c] + Mean[c]
+	}
+	return rgb
+}
+
+func near(a, b, tolerance float32) bool {
+	return math.Abs(float64(a-b)) <= float64(tolerance)
+}
+
+func TestCropRoundTrip(t *testing.T) {
+	// 박스의 네 모서리가 정수 픽셀에 오도록 잡고 모서리마다 다른 색을 칠한다.
+	box := vitpose.Box{Center: [2]float32{201, 275}, Scale: [2]float32{382, 510}}
+	left, top := int(box.Left()), int(box.Top())
+	right, bottom := left+int(box.Scale[0]), top+int(box.Scale[1])
+	img := image.NewRGBA(image.Rect(0, 0, 400, 540))
+	corners := []struct {
+		u, v int
+		x, y int
+		c    color.RGBA
+	}{
+		{0, 0, left, top, color.RGBA{255, 0, 0, 255}},
+		{vitpose.InputWidth - 1, 0, right, top, color.RGBA{0, 255, 0, 255}},
+		{0, vitpose.InputHeight - 1, left, bottom, color.RGBA{0, 0, 255, 255}},
+		{vitpose.InputWidth - 1, vitpose.InputHeight - 1, right, bottom, color.RGBA{255, 255, 255, 255}},
+	}
+	for _, c := range corners {
+		img.SetRGBA(c.x, c.y, c.c)
+	}
+
+	dst, err := FromImage(img, box)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range corners {
+		want := [3]float32{float32(c.c.R), float32(c.c.G), float32(c.c.B)}
+		if got := pixel(dst, c.u, c.v); !near(got[0], want[0], 0.01) || !near(got[1], want[1], 0.01) || !near(got[2], want[2], 0.01) {
+			t.Errorf("crop pixel (%d, %d) = %v, want the color %v at image (%d, %d)", c.u, c.v, got, want, c.x, c.y)
+		}
+		// Box.FromCrop 은 Crop 이 샘플링한 이미지 좌표를 가리켜야 한다.
+		if x, y := box.FromCrop(float32(c.u), float32(c.v)); !near(x, float32(c.x), 1e-3) || !near(y, float32(c.y), 1e-3) {
+			t.Errorf("FromCrop(%d, %d) = (%v, %v), want box corner (%d, %d)", c.u, c.v, x, y, c.x, c.y)
+		}
+	}
+}
+
+// solid w x h 크기의 c 한 색 이미지
+func solid(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestCrop(t *testing.T) {
+	// 채널마다 다른 값을 써서 CHW 의 채널 순서가 섞이면 드러나게 한다.
+	img := solid(192, 256, color.RGBA{200, 100, 50, 255})
+	fill := [3]float32{200, 100, 50}
+	zero := [3]float32{}
+	// exact crop 의 첫 픽셀과 마지막 픽셀이 이미지의 양 끝 픽셀에 맞는 박스
+	exact := vitpose.Box{Center: [2]float32{95.5, 127.5}, Scale: [2]float32{191, 255}}
+	// left 왼쪽 절반이 이미지 밖으로 나간 박스
+	left := vitpose.Box{Center: [2]float32{0, 127.5}, Scale: [2]float32{191, 255}}
+	// outside 이미지와 겹치지 않는 박스
+	outside := vitpose.Box{Center: [2]float32{1000, 1000}, Scale: [2]float32{191, 255}}
+
+	tests := []struct {
+		name string
+		box  vitpose.Box
+		u, v int
+		want [3]float32
+	}{
+		{"first pixel", exact, 0, 0, fill},
+		{"last pixel", exact, vitpose.InputWidth - 1, vitpose.InputHeight - 1, fill},
+		{"middle", exact, 100, 37, fill},
+		{"outside left", left, 0, 100, zero},
+		{"inside right", left, vitpose.InputWidth - 1, 100, fill},
+		{"outside", outside, 50, 50, zero},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := make([]float32, vitpose.ImageSize)
+			if err := Crop(img, tt.box, dst); err != nil {
+				t.Fatal(err)
+			}
+			const plane = vitpose.InputHeight * vitpose.InputWidth
+			i := tt.v*vitpose.InputWidth + tt.u
+			for c := 0; c < 3; c++ {
+				want := (tt.want[c] - Mean[c]) / Std[c]
+				if got := dst[c*plane+i]; !near(got, want, 1e-4) {
+					t.Errorf("channel %d at (%d, %d) = %v, want %v", c, tt.u, tt.v, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCropErrors(t *testing.T) {
+	img := solid(10, 10, color.RGBA{A: 255})
+	box := FullImageBox(img)
+	tests := []struct {
+		name string
+		box  vitpose.Box
+		dst  []float32
+		want string
+	}{
+		{"short dst", box, make([]float32, vitpose.ImageSize-1), "dst has"},
+		{"zero scale", vitpose.Box{Center: box.Center}, make([]float32, vitpose.ImageSize), "invalid box scale"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Crop(img, tt.box, tt.dst)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Crop() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFullImageBox(t *testing.T) {
+	// 박스는 중심을 유지한 채 짧은 쪽을 192:256 비율에 맞게 넓힌다.
+	tests := []struct {
+		name          string
+		width, height int
+		want          vitpose.Box
+	}{
+		{"input aspect", 192, 256, vitpose.Box{Center: [2]float32{96, 128}, Scale: [2]float32{192, 256}}},
+		{"wide", 384, 256, vitpose.Box{Center: [2]float32{192, 128}, Scale: [2]float32{384, 512}}},
+		{"tall", 100, 400, vitpose.Box{Center: [2]float32{50, 200}, Scale: [2]float32{300, 400}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FullImageBox(image.NewRGBA(image.Rect(0, 0, tt.width, tt.height))); got != tt.want {
+				t.Errorf("FullImageBox(%dx%d) = %+v, want %+v", tt.width, tt.height, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	img := solid(4, 4, color.RGBA{10, 20, 30, 255})
+	encode := func(f func(*bytes.Buffer) error) []byte {
+		var buf bytes.Buffer
+		if err := f(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	tests := []struct {
+		name string
+		data []byte
+		// want 빈 문자열이면 성공해야 한다.
+		want string
+	}{
+		{"png", encode(func(w *bytes.Buffer) error { return png.Encode(w, img) }), ""},
+		{"jpeg", encode(func(w *bytes.Buffer) error { return jpeg.Encode(w, img, nil) }), ""},
+		{"gif", encode(func(w *bytes.Buffer) error { return gif.Encode(w, img, nil) }), `unsupported image format "gif"`},
+		{"garbage", []byte("not an image"), "decode image"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.data)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Bounds() != img.Bounds() {
+					t.Errorf("bounds = %v, want %v", got.Bounds(), img.Bounds())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Decode() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBatch(t *testing.T) {
+	img := solid(30, 40, color.RGBA{200, 100, 50, 255})
+	img.SetRGBA(3, 4, color.RGBA{0, 0, 0, 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "person.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want, err := FromImage(img, FullImageBox(img))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []int{1, 3} {
+		batch, err := LoadBatch(path, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if batch.Len() != n {
+			t.Fatalf("LoadBatch(%d) has %d images", n, batch.Len())
+		}
+		for i := 0; i < n; i++ {
+			if !slices.Equal(batch.Image(i), want) {
+				t.Errorf("LoadBatch(%d) image %d differs from the full-image crop", n, i)
+			}
+		}
+	}
+
+	if _, err := LoadBatch(filepath.Join(t.TempDir(), "missing.png"), 1); err == nil {
+		t.Error("LoadBatch() of a missing file succeeded")
+	}
+}
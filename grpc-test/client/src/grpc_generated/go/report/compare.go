@@ -0,0 +1,289 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+)
+
+// z95 양측 95% 신뢰 구간의 표준정규 분위수
+const z95 = 1.959964
+
+// Metric 두 실행의 지표 하나를 비교한 결과. 레이턴시는 ms, 처리량은 RPS 단위다.
+type Metric struct {
+	Name      string
+	Base      float64
+	Candidate float64
+	// Change (Candidate-Base)/Base
+	Change float64
+	// BaseCI, CandidateCI 95% 신뢰 구간. 구할 수 없으면 NaN 이다.
+	BaseCI      [2]float64
+	CandidateCI [2]float64
+	// PValue 평균 차이 검정의 p 값. 백분위수처럼 신뢰 구간으로 판단하는 지표는 NaN 이다.
+	PValue      float64
+	Significant bool
+	// HigherIsBetter 처리량처럼 클수록 좋은 지표
+	HigherIsBetter bool
+}
+
+// Worsening 나빠진 비율. 레이턴시는 늘어난 비율, 처리량은 줄어든 비율이다.
+func (m Metric) Worsening() float64 {
+	if m.HigherIsBetter {
+		return -m.Change
+	}
+	return m.Change
+}
+
+// Comparison 기준 실행과 후보 실행의 비교
+type Comparison struct {
+	Base      Metadata
+	Candidate Metadata
+	Metrics   []Metric
+}
+
+// Compare 전체 요약을 백분위수, 평균, 처리량별로 비교한다.
+//
+// 백분위수는 순서 통계량의 이항 근사로 구한 95% 신뢰 구간이 겹치지 않으면 유의하다고 본다.
+// 평균은 Welch 검정 (정규 근사) 을, 처리량은 시간 구간별 처리량에 같은 검정을 쓴다.
+func Compare(base, candidate *Report) *Comparison {
+	c := &Comparison{Base: base.Metadata, Candidate: candidate.Metadata}
+	b, k := base.Total, candidate.Total
+	for _, p := range []struct {
+		name  string
+		q     float64
+		value func(Summary) time.Duration
+	}{
+		{"p50", 0.50, func(s Summary) time.Duration { return s.P50 }},
+		{"p90", 0.90, func(s Summary) time.Duration { return s.P90 }},
+		{"p95", 0.95, func(s Summary) time.Duration { return s.P95 }},
+		{"p99", 0.99, func(s Summary) time.Duration { return s.P99 }},
+		{"p999", 0.999, func(s Summary) time.Duration { return s.P999 }},
+	} {
+		m := Metric{
+			Name:        p.name,
+			Base:        ms(p.value(b)),
+			Candidate:   ms(p.value(k)),
+			BaseCI:      quantileCI(b.Histogram, b.Count, p.q),
+			CandidateCI: quantileCI(k.Histogram, k.Count, p.q),
+			PValue:      math.NaN(),
+		}
+		m.Significant = m.BaseCI[1] < m.CandidateCI[0] || m.CandidateCI[1] < m.BaseCI[0]
+		c.Metrics = append(c.Metrics, m.withChange())
+	}
+
+	mean := Metric{
+		Name:      "mean",
+		Base:      ms(b.Mean),
+		Candidate: ms(k.Mean),
+	}
+	mean.BaseCI = meanCI(mean.Base, ms(b.StdDev), float64(b.Count))
+	mean.CandidateCI = meanCI(mean.Candidate, ms(k.StdDev), float64(k.Count))
+	mean.PValue = welch(mean.Base, ms(b.StdDev), float64(b.Count), mean.Candidate, ms(k.StdDev), float64(k.Count))
+	mean.Significant = mean.PValue < 0.05
+	c.Metrics = append(c.Metrics, mean.withChange())
+
+	throughput := Metric{
+		Name:           "throughput",
+		Base:           b.Throughput,
+		Candidate:      k.Throughput,
+		HigherIsBetter: true,
+		BaseCI:         [2]float64{math.NaN(), math.NaN()},
+		CandidateCI:    [2]float64{math.NaN(), math.NaN()},
+		PValue:         math.NaN(),
+	}
+	bm, bs, bn := windowThroughput(base.Windows)
+	km, ks, kn := windowThroughput(candidate.Windows)
+	if bn >= 2 && kn >= 2 {
+		throughput.BaseCI = meanCI(bm, bs, bn)
+		throughput.CandidateCI = meanCI(km, ks, kn)
+		throughput.PValue = welch(bm, bs, bn, km, ks, kn)
+		throughput.Significant = throughput.PValue < 0.05
+	}
+	c.Metrics = append(c.Metrics, throughput.withChange())
+	return c
+}
+
+func (m Metric) withChange() Metric {
+	switch {
+	case m.Base != 0:
+		m.Change = (m.Candidate - m.Base) / m.Base
+	case m.Candidate != 0:
+		m.Change = math.Inf(1)
+	}
+	return m
+}
+
+// quantileCI q 분위수의 95% 신뢰 구간. 순위 n*q ± z*sqrt(n*q*(1-q)) 에 해당하는 칸의 경계를 쓴다.
+func quantileCI(buckets []histogram.Bucket, n int64, q float64) [2]float64 {
+	if n == 0 || len(buckets) == 0 {
+		return [2]float64{math.NaN(), math.NaN()}
+	}
+	center := float64(n) * q
+	half := z95 * math.Sqrt(float64(n)*q*(1-q))
+	lo := int64(math.Max(math.Floor(center-half), 1))
+	hi := int64(math.Min(math.Ceil(center+half), float64(n)))
+	var loValue, hiValue time.Duration
+	var seen int64
+	for _, b := range buckets {
+		if seen < lo && seen+b.Count >= lo {
+			loValue = b.Low
+		}
+		seen += b.Count
+		if seen >= hi {
+			hiValue = b.High
+			break
+		}
+	}
+	return [2]float64{ms(loValue), ms(hiValue)}
+}
+
+func meanCI(mean, stddev, n float64) [2]float64 {
+	if n < 2 {
+		return [2]float64{math.NaN(), math.NaN()}
+	}
+	half := z95 * stddev / math.Sqrt(n)
+	return [2]float64{mean - half, mean + half}
+}
+
+// welch 두 평균이 같다는 가설의 양측 p 값 (Welch 검정의 정규 근사)
+func welch(m1, s1, n1, m2, s2, n2 float64) float64 {
+	if n1 < 2 || n2 < 2 {
+		return math.NaN()
+	}
+	se := math.Sqrt(s1*s1/n1 + s2*s2/n2)
+	if se == 0 {
+		if m1 == m2 {
+			return 1
+		}
+		return 0
+	}
+	z := math.Abs(m2-m1) / se
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// windowThroughput 시간 구간별 처리량의 평균, 표본 표준편차, 구간 수.
+// 마지막 구간은 덜 찬 경우가 많으므로 구간이 셋 이상이면 뺀다.
+func windowThroughput(windows []Window) (mean, stddev, n float64) {
+	if len(windows) > 2 {
+		windows = windows[:len(windows)-1]
+	}
+	for _, w := range windows {
+		mean += w.Throughput
+	}
+	n = float64(len(windows))
+	if n == 0 {
+		return 0, 0, 0
+	}
+	mean /= n
+	if n < 2 {
+		return mean, 0, n
+	}
+	for _, w := range windows {
+		stddev += (w.Throughput - mean) * (w.Throughput - mean)
+	}
+	return mean, math.Sqrt(stddev / (n - 1)), n
+}
+
+// Threshold 지표가 MaxWorsening (비율, 0.1 = 10%) 보다 나빠지면 회귀로 본다.
+type Threshold struct {
+	Metric       string
+	MaxWorsening float64
+}
+
+// ParseThresholds "p99=10,throughput=5" 처럼 지표별 허용 악화율 (%) 을 읽는다.
+func ParseThresholds(s string) ([]Threshold, error) {
+	var thresholds []Threshold
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("report: threshold %q: want METRIC=PERCENT", part)
+		}
+		name = strings.TrimSpace(name)
+		if !knownMetric(name) {
+			return nil, fmt.Errorf("report: threshold %q: unknown metric %q", part, name)
+		}
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("report: threshold %q: %w", part, err)
+		}
+		thresholds = append(thresholds, Threshold{Metric: name, MaxWorsening: pct / 100})
+	}
+	return thresholds, nil
+}
+
+func knownMetric(name string) bool {
+	switch name {
+	case "p50", "p90", "p95", "p99", "p999", "mean", "throughput":
+		return true
+	}
+	return false
+}
+
+// Regressions thresholds 를 넘게 나빠진 지표를 설명하는 문장들. requireSignificant 이면
+// 통계적으로 유의한 변화만 회귀로 본다.
+func (c *Comparison) Regressions(thresholds []Threshold, requireSignificant bool) []string {
+	var regressions []string
+	for _, th := range thresholds {
+		for _, m := range c.Metrics {
+			if m.Name != th.Metric || m.Worsening() <= th.MaxWorsening {
+				continue
+			}
+			if requireSignificant && !m.Significant {
+				continue
+			}
+			regressions = append(regressions, fmt.Sprintf("%s worsened by %.1f%% (limit %.1f%%)",
+				m.Name, 100*m.Worsening(), 100*th.MaxWorsening))
+		}
+	}
+	return regressions
+}
+
+// Write 비교 결과를 표로 쓴다.
+func (c *Comparison) Write(w io.Writer) {
+	describe := func(m Metadata) string {
+		version := m.ModelVersion
+		if version == "" {
+			version = "latest"
+		}
+		return fmt.Sprintf("%s %s:%s batch %d, concurrency %d, Triton %s",
+			m.StartedAt.Format("2006-01-02 15:04"), m.ModelName, version, m.BatchSize, m.Concurrency, m.ServerVersion)
+	}
+	fmt.Fprintf(w, "Base:      %s\n", describe(c.Base))
+	fmt.Fprintf(w, "Candidate: %s\n", describe(c.Candidate))
+
+	line := "------------------------------------------------------------------------------------------------------"
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "%-10s | %-9s | %-9s | %-8s | %-19s | %-19s | %-7s | %s\n",
+		"Metric", "Base", "Candidate", "Change", "Base 95% CI", "Candidate 95% CI", "p", "Significant")
+	fmt.Fprintln(w, line)
+	for _, m := range c.Metrics {
+		p := "-"
+		if !math.IsNaN(m.PValue) {
+			p = fmt.Sprintf("%.3f", m.PValue)
+		}
+		significant := "no"
+		if m.Significant {
+			significant = "yes"
+		}
+		fmt.Fprintf(w, "%-10s | %-9.2f | %-9.2f | %+7.1f%% | %-19s | %-19s | %-7s | %s\n",
+			m.Name, m.Base, m.Candidate, 100*m.Change, ci(m.BaseCI), ci(m.CandidateCI), p, significant)
+	}
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w, "Latency in ms, throughput in requests per second.")
+}
+
+func ci(interval [2]float64) string {
+	if math.IsNaN(interval[0]) {
+		return "-"
+	}
+	return fmt.Sprintf("[%.2f, %.2f]", interval[0], interval[1])
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+)
+
+// runReport 10ms 부터 scale 배로 늘어나는 레이턴시 10000 개와 초당 rps 개의 시간 구간을 가진 보고서
+func runReport(scale, rps float64) *Report {
+	h := histogram.NewLatency()
+	for i := 0; i < 10000; i++ {
+		h.Record(time.Duration(scale * float64(10*time.Millisecond+time.Duration(i%1000)*10*time.Microsecond)))
+	}
+	r := &Report{Total: NewSummary("ALL", h, time.Duration(10000/rps*float64(time.Second)))}
+	for i := 0; i < 10; i++ {
+		// 구간별 처리량은 rps 근처에서 조금씩 흔들린다.
+		r.Windows = append(r.Windows, Window{Summary: Summary{Throughput: rps + float64(i%3-1)}})
+	}
+	return r
+}
+
+func metric(c *Comparison, name string) Metric {
+	for _, m := range c.Metrics {
+		if m.Name == name {
+			return m
+		}
+	}
+	return Metric{}
+}
+
+func TestCompareSameRun(t *testing.T) {
+	c := Compare(runReport(1, 100), runReport(1, 100))
+	for _, m := range c.Metrics {
+		if m.Change != 0 || m.Significant {
+			t.Errorf("%s: change %v, significant %v, want no change", m.Name, m.Change, m.Significant)
+		}
+	}
+	if r := c.Regressions([]Threshold{{"p99", 0}}, false); len(r) != 0 {
+		t.Errorf("Regressions = %v, want none", r)
+	}
+}
+
+func TestCompareRegression(t *testing.T) {
+	c := Compare(runReport(1, 100), runReport(1.2, 90))
+
+	p99 := metric(c, "p99")
+	if math.Abs(p99.Change-0.2) > 0.01 || !p99.Significant {
+		t.Errorf("p99 = %+v, want a significant +20%%", p99)
+	}
+	mean := metric(c, "mean")
+	if !mean.Significant || mean.PValue > 1e-6 {
+		t.Errorf("mean = %+v, want a significant change", mean)
+	}
+	throughput := metric(c, "throughput")
+	if math.Abs(throughput.Worsening()-0.1) > 0.01 || !throughput.Significant {
+		t.Errorf("throughput = %+v, want a significant 10%% drop", throughput)
+	}
+
+	thresholds, err := ParseThresholds("p99=10, throughput=5%, p50=25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	regressions := c.Regressions(thresholds, true)
+	if len(regressions) != 2 || !strings.HasPrefix(regressions[0], "p99") || !strings.HasPrefix(regressions[1], "throughput") {
+		t.Errorf("Regressions = %v, want p99 and throughput", regressions)
+	}
+
+	var buf bytes.Buffer
+	c.Write(&buf)
+	if !strings.Contains(buf.String(), "+20.0%") {
+		t.Errorf("comparison table does not show the p99 change:\n%s", buf.String())
+	}
+}
+
+func TestCompareSmallSample(t *testing.T) {
+	// 표본이 적으면 같은 차이라도 유의하지 않다.
+	small := func(scale float64) *Report {
+		h := histogram.NewLatency()
+		for _, v := range []float64{10, 30, 12, 50, 11} {
+			h.Record(time.Duration(scale * v * float64(time.Millisecond)))
+		}
+		return &Report{Total: NewSummary("ALL", h, time.Second)}
+	}
+	c := Compare(small(1), small(1.2))
+	if m := metric(c, "p99"); m.Significant {
+		t.Errorf("p99 = %+v, want not significant with 5 samples", m)
+	}
+	if r := c.Regressions([]Threshold{{"p99", 0.1}}, true); len(r) != 0 {
+		t.Errorf("Regressions = %v, want none when requiring significance", r)
+	}
+	if r := c.Regressions([]Threshold{{"p99", 0.1}}, false); len(r) != 1 {
+		t.Errorf("Regressions = %v, want p99 without the significance check", r)
+	}
+}
+
+func TestParseThresholdsErrors(t *testing.T) {
+	for _, s := range []string{"p99", "p98=10", "p99=ten"} {
+		if _, err := ParseThresholds(s); err == nil {
+			t.Errorf("ParseThresholds(%q) succeeded", s)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var summaryHeader = []string{"count", "throughput", "mean_ms", "stddev_ms", "min_ms", "p50_ms", "p90_ms", "p95_ms", "p99_ms", "p999_ms", "max_ms", "errors", "error_rate"}
+
+func (s Summary) csvRecord() []string {
+	record := []string{strconv.FormatInt(s.Count, 10), strconv.FormatFloat(s.Throughput, 'f', 3, 64)}
+	for _, d := range []time.Duration{s.Mean, s.StdDev, s.Min, s.P50, s.P90, s.P95, s.P99, s.P999, s.Max} {
+		record = append(record, strconv.FormatFloat(ms(d), 'f', 3, 64))
+	}
+	return append(record, strconv.FormatInt(s.Errors, 10), strconv.FormatFloat(s.ErrorRate(), 'f', 4, 64))
+}
+
+// WriteClientsCSV 클라이언트마다 한 줄, 마지막에 전체 합계 (client_id "ALL") 를 CSV 로 쓴다. 레이턴시는 ms 단위다.
+func (r *Report) WriteClientsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(append([]string{"client_id"}, summaryHeader...))
+	for _, c := range r.Clients {
+		cw.Write(append([]string{c.Name}, c.csvRecord()...))
+	}
+	cw.Write(append([]string{"ALL"}, r.Total.csvRecord()...))
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteWindowsCSV 시간 구간마다 한 줄을 CSV 로 쓴다. 레이턴시는 ms 단위다.
+func (r *Report) WriteWindowsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(append([]string{"start", "end"}, summaryHeader...))
+	for _, win := range r.Windows {
+		cw.Write(append([]string{win.Start.Format(time.RFC3339), win.End.Format(time.RFC3339)}, win.csvRecord()...))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMarkdown 실행 조건과 전체/클라이언트별 요약을 Markdown 으로 쓴다.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	m := r.Metadata
+	version := m.ModelVersion
+	if version == "" {
+		version = "latest"
+	}
+	fmt.Fprintf(w, "# Benchmark %s\n\n", m.StartedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "| | |\n|---|---|\n")
+	if m.RunName != "" {
+		fmt.Fprintf(w, "| Run | %s |\n", m.RunName)
+	}
+	fmt.Fprintf(w, "| Duration | %v |\n", m.FinishedAt.Sub(m.StartedAt).Round(time.Second))
+	fmt.Fprintf(w, "| Server | %s (%s %s) |\n", m.ServerURL, m.ServerName, m.ServerVersion)
+	fmt.Fprintf(w, "| Model | %s (version %s) |\n", m.ModelName, version)
+	fmt.Fprintf(w, "| Batch size | %d |\n", m.BatchSize)
+	fmt.Fprintf(w, "| Concurrency | %d |\n", m.Concurrency)
+	if m.LoadMode != "" {
+		fmt.Fprintf(w, "| Load mode | %s |\n", m.LoadMode)
+	}
+
+	fmt.Fprintf(w, "\n## Latency (ms)\n\n")
+	fmt.Fprintf(w, "| Client | Count | RPS | Mean | StdDev | P50 | P90 | P95 | P99 | P99.9 | Max |\n")
+	fmt.Fprintf(w, "|---|---:|---:|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+	row := func(s Summary) {
+		fmt.Fprintf(w, "| %s | %d | %.1f | %.1f | %.1f | %.1f | %.1f | %.1f | %.1f | %.1f | %.1f |\n",
+			s.Name, s.Count, s.Throughput, ms(s.Mean), ms(s.StdDev),
+			ms(s.P50), ms(s.P90), ms(s.P95), ms(s.P99), ms(s.P999), ms(s.Max))
+	}
+	total := r.Total
+	total.Name = "**ALL**"
+	row(total)
+	for _, c := range r.Clients {
+		row(c)
+	}
+	if r.Total.Errors > 0 {
+		r.writeErrors(w)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// writeErrors 클라이언트별 에러율과 gRPC 상태 코드별 실패 수를 표로 쓴다.
+func (r *Report) writeErrors(w io.Writer) {
+	codes := make([]string, 0, len(r.Total.ErrorCodes))
+	for code := range r.Total.ErrorCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	fmt.Fprintf(w, "\n## Errors\n\n")
+	fmt.Fprintf(w, "| Client | Requests | Errors | Err%% |")
+	for _, code := range codes {
+		fmt.Fprintf(w, " %s |", code)
+	}
+	fmt.Fprintf(w, "\n|---|---:|---:|---:|%s\n", strings.Repeat("---:|", len(codes)))
+	row := func(s Summary) {
+		fmt.Fprintf(w, "| %s | %d | %d | %.2f |", s.Name, s.Requests(), s.Errors, 100*s.ErrorRate())
+		for _, code := range codes {
+			fmt.Fprintf(w, " %d |", s.ErrorCodes[code])
+		}
+		fmt.Fprintln(w)
+	}
+	total := r.Total
+	total.Name = "**ALL**"
+	row(total)
+	for _, c := range r.Clients {
+		row(c)
+	}
+}
@@ -0,0 +1,360 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// WriteHTML 차트를 SVG 로 넣은 HTML 보고서 하나를 쓴다. 외부 스크립트나 스타일시트가 없으므로
+// 파일 하나만 옮겨도 브라우저에서 그대로 열린다.
+func (r *Report) WriteHTML(w io.Writer) error {
+	m := r.Metadata
+	version := m.ModelVersion
+	if version == "" {
+		version = "latest"
+	}
+	page := htmlPage{
+		Title: "Benchmark " + m.StartedAt.Format("2006-01-02 15:04:05"),
+		Total: r.Total,
+	}
+	if m.RunName != "" {
+		page.Title += " · " + m.RunName
+		page.Meta = append(page.Meta, [2]string{"Run", m.RunName})
+	}
+	page.Meta = append(page.Meta,
+		[2]string{"Duration", m.FinishedAt.Sub(m.StartedAt).Round(time.Second).String()},
+		[2]string{"Server", fmt.Sprintf("%s (%s %s)", m.ServerURL, m.ServerName, m.ServerVersion)},
+		[2]string{"Model", fmt.Sprintf("%s (version %s)", m.ModelName, version)},
+		[2]string{"Batch size", strconv.Itoa(m.BatchSize)},
+		[2]string{"Concurrency", strconv.Itoa(m.Concurrency)},
+	)
+	if m.LoadMode != "" {
+		page.Meta = append(page.Meta, [2]string{"Load mode", m.LoadMode})
+	}
+	page.Clients = r.Clients
+	page.Scatter = r.scatterChart()
+	page.Percentiles = r.percentileChart()
+	page.Distribution = r.distributionChart()
+	page.Histogram = r.histogramChart()
+	page.Heatmap, page.HeatmapScale = r.heatmapChart()
+	page.Errors = r.errorChart()
+	return htmlTemplate.Execute(w, page)
+}
+
+type htmlPage struct {
+	Title        string
+	Meta         [][2]string
+	Total        Summary
+	Clients      []Summary
+	Scatter      template.HTML
+	Percentiles  template.HTML
+	Distribution template.HTML
+	Histogram    template.HTML
+	Heatmap      template.HTML
+	HeatmapScale string
+	Errors       template.HTML
+}
+
+// seconds Metadata.StartedAt 부터 t 까지의 초
+func (r *Report) seconds(t time.Time) float64 {
+	return t.Sub(r.Metadata.StartedAt).Seconds()
+}
+
+// scatterChart 뽑아 둔 요청의 레이턴시를 시간에 따라 점으로 찍는다. 실패한 요청은 위쪽에 빨간 x 로 표시한다.
+func (r *Report) scatterChart() template.HTML {
+	if len(r.Samples) == 0 {
+		return ""
+	}
+	var xMax, yMax float64
+	failed := false
+	for _, s := range r.Samples {
+		xMax = math.Max(xMax, s.Offset.Seconds())
+		yMax = math.Max(yMax, ms(s.Latency))
+		failed = failed || s.Error != ""
+	}
+	c := newChart(0, xMax, 0, yMax*1.05)
+	c.xLabel, c.yLabel = "Time since start (s)", "Latency (ms)"
+	c.axes(niceTicks(0, xMax, 10, ""), niceTicks(0, yMax*1.05, 5, ""))
+	for _, s := range r.Samples {
+		x := c.x(s.Offset.Seconds())
+		if s.Error != "" {
+			y := float64(marginTop) + 4
+			c.printf(`<path d="M%.1f %.1fl4 4m0 -4l-4 4" stroke="#d62728" stroke-width="1.2"><title>%s</title></path>`, x-2, y-2, html.EscapeString(s.Client+": "+s.Error))
+			continue
+		}
+		c.printf(`<circle cx="%.1f" cy="%.1f" r="1.6" fill="#1f77b4" fill-opacity="0.45"/>`, x, c.y(ms(s.Latency)))
+	}
+	if failed {
+		c.legend([]string{"ok", "failed"}, []string{"#1f77b4", "#d62728"})
+	}
+	return c.svg()
+}
+
+// percentileChart 시간 구간마다 P50, P90, P99, 최댓값을 선으로 잇는다.
+func (r *Report) percentileChart() template.HTML {
+	if len(r.Windows) == 0 {
+		return ""
+	}
+	names := []string{"P50", "P90", "P99", "Max"}
+	series := make([][]float64, len(names))
+	var xs []float64
+	var yMax float64
+	for _, win := range r.Windows {
+		if win.Count == 0 {
+			continue
+		}
+		xs = append(xs, (r.seconds(win.Start)+r.seconds(win.End))/2)
+		for i, d := range []time.Duration{win.P50, win.P90, win.P99, win.Max} {
+			series[i] = append(series[i], ms(d))
+		}
+		yMax = math.Max(yMax, ms(win.Max))
+	}
+	if len(xs) == 0 {
+		return ""
+	}
+	xMax := r.seconds(r.Windows[len(r.Windows)-1].End)
+	c := newChart(0, xMax, 0, yMax*1.05)
+	c.xLabel, c.yLabel = "Time since start (s)", "Latency (ms)"
+	c.axes(niceTicks(0, xMax, 10, ""), niceTicks(0, yMax*1.05, 5, ""))
+	colors := palette[:len(names)]
+	for i, name := range names {
+		c.polyline(xs, series[i], colors[i], name)
+	}
+	c.legend(names, colors)
+	return c.svg()
+}
+
+// distributionChart 전체 히스토그램의 백분위 곡선. 가로축은 -log10(1-p) 라서 꼬리 (99%, 99.9%, ...) 가 잘 보인다.
+func (r *Report) distributionChart() template.HTML {
+	if r.Total.Count == 0 || len(r.Total.Histogram) == 0 {
+		return ""
+	}
+	nines := math.Max(1, math.Min(5, math.Ceil(math.Log10(float64(r.Total.Count)))))
+	xs := []float64{0}
+	ys := []float64{ms(r.Total.Min)}
+	var seen int64
+	for _, b := range r.Total.Histogram {
+		seen += b.Count
+		x := nines
+		if f := float64(seen) / float64(r.Total.Count); f < 1 {
+			x = math.Min(nines, -math.Log10(1-f))
+		}
+		xs = append(xs, x)
+		ys = append(ys, ms(b.High))
+	}
+	yMax := ms(r.Total.Max) * 1.05
+	c := newChart(0, nines, 0, yMax)
+	c.xLabel, c.yLabel = "Percentile", "Latency (ms)"
+	var xTicks []tick
+	for i := 0; i <= int(nines); i++ {
+		p := 100 * (1 - math.Pow(10, -float64(i)))
+		xTicks = append(xTicks, tick{float64(i), strconv.FormatFloat(p, 'f', max(0, i-2), 64) + "%"})
+	}
+	c.axes(xTicks, niceTicks(0, yMax, 5, ""))
+	c.polyline(xs, ys, palette[0], "ALL")
+	return c.svg()
+}
+
+// histogramBins 히스토그램 차트의 막대 수
+const histogramBins = 60
+
+// histogramChart 전체 히스토그램을 최솟값부터 최댓값까지 같은 폭의 막대로 다시 나눠 그린다.
+func (r *Report) histogramChart() template.HTML {
+	if r.Total.Count == 0 || len(r.Total.Histogram) == 0 {
+		return ""
+	}
+	lo, hi := ms(r.Total.Min), ms(r.Total.Max)
+	if hi <= lo {
+		hi = lo + 1
+	}
+	width := (hi - lo) / histogramBins
+	counts := make([]int64, histogramBins)
+	for _, b := range r.Total.Histogram {
+		i := int((ms(b.Low+b.High)/2 - lo) / width)
+		counts[max(0, min(histogramBins-1, i))] += b.Count
+	}
+	var yMax int64
+	for _, n := range counts {
+		yMax = max(yMax, n)
+	}
+	c := newChart(lo, hi, 0, float64(yMax)*1.05)
+	c.xLabel, c.yLabel = "Latency (ms)", "Requests"
+	c.axes(niceTicks(lo, hi, 10, ""), niceTicks(0, float64(yMax)*1.05, 5, ""))
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		x0, x1 := c.x(lo+float64(i)*width), c.x(lo+float64(i+1)*width)
+		c.printf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#1f77b4"><title>%.1f-%.1f ms: %d</title></rect>`,
+			x0, c.y(float64(n)), math.Max(1, x1-x0-1), c.y(0)-c.y(float64(n)), lo+float64(i)*width, lo+float64(i+1)*width, n)
+	}
+	return c.svg()
+}
+
+// heatmapRow 히트맵 한 줄의 높이 (px)
+const heatmapRow = 14
+
+// heatmapChart 클라이언트마다 한 줄, 시간 구간마다 한 칸에 P99 를 색으로 칠한다. 느린 클라이언트나 특정 시점에
+// 몰린 지연이 드러난다. 기록이 없는 칸은 회색, 실패만 있는 칸은 검은색이다. 색의 범위 설명도 돌려준다.
+func (r *Report) heatmapChart() (template.HTML, string) {
+	if len(r.ClientWindows) == 0 || len(r.Windows) == 0 {
+		return "", ""
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, cells := range r.ClientWindows {
+		for _, cell := range cells {
+			if cell.Count > 0 {
+				lo, hi = math.Min(lo, ms(cell.P99)), math.Max(hi, ms(cell.P99))
+			}
+		}
+	}
+	if math.IsInf(lo, 1) {
+		lo, hi = 0, 0
+	}
+
+	rows := len(r.ClientWindows)
+	windowSec := r.Windows[0].End.Sub(r.Windows[0].Start).Seconds()
+	xMax := windowSec * float64(len(r.Windows))
+	c := newChart(0, xMax, 0, float64(rows))
+	c.height = float64(marginTop + marginBottom + rows*heatmapRow)
+	c.xLabel = "Time since start (s)"
+	c.axes(niceTicks(0, xMax, 10, ""), nil)
+	for i, cells := range r.ClientWindows {
+		name := ""
+		if i < len(r.Clients) {
+			name = r.Clients[i].Name
+		}
+		top, bottom := c.y(float64(rows-i)), c.y(float64(rows-i-1))
+		label := name
+		if runes := []rune(label); len(runes) > 10 {
+			// 호스트 이름이나 실행 이름은 한글일 수 있으므로 바이트가 아니라 글자 단위로 자른다.
+			label = string(runes[:9]) + "…"
+		}
+		c.printf(`<text x="%d" y="%.1f" class="tick" text-anchor="end">%s<title>%s</title></text>`,
+			marginLeft-6, bottom-3, html.EscapeString(label), html.EscapeString(name))
+		for j, cell := range cells {
+			color := "#eeeeee"
+			switch {
+			case cell.Count > 0 && hi > lo:
+				color = heatColor((ms(cell.P99) - lo) / (hi - lo))
+			case cell.Count > 0:
+				color = heatColor(0)
+			case cell.Errors > 0:
+				color = "#222222"
+			}
+			x0, x1 := c.x(float64(j)*windowSec), c.x(float64(j+1)*windowSec)
+			c.printf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s %.0f-%.0fs: %d ok, %d failed, P50 %.1f ms, P99 %.1f ms</title></rect>`,
+				x0, top, x1-x0, bottom-top, color, html.EscapeString(name), float64(j)*windowSec, float64(j+1)*windowSec,
+				cell.Count, cell.Errors, ms(cell.P50), ms(cell.P99))
+		}
+	}
+	return c.svg(), fmt.Sprintf("P99 from %.1f ms (light) to %.1f ms (dark)", lo, hi)
+}
+
+// errorChart 시간 구간마다 실패 수를 gRPC 상태 코드별로 쌓은 막대. 실패가 없으면 비어 있다.
+func (r *Report) errorChart() template.HTML {
+	if r.Total.Errors == 0 || len(r.Windows) == 0 {
+		return ""
+	}
+	codes := make([]string, 0, len(r.Total.ErrorCodes))
+	for code := range r.Total.ErrorCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	var yMax int64
+	for _, win := range r.Windows {
+		yMax = max(yMax, win.Errors)
+	}
+	xMax := r.seconds(r.Windows[len(r.Windows)-1].End)
+	c := newChart(0, xMax, 0, float64(yMax)*1.1)
+	c.xLabel, c.yLabel = "Time since start (s)", "Failed requests"
+	c.axes(niceTicks(0, xMax, 10, ""), niceTicks(0, float64(yMax)*1.1, 5, ""))
+	colors := make([]string, len(codes))
+	for i := range codes {
+		colors[i] = palette[(i+3)%len(palette)]
+	}
+	for _, win := range r.Windows {
+		x0, x1 := c.x(r.seconds(win.Start)), c.x(r.seconds(win.End))
+		var stacked int64
+		for i, code := range codes {
+			n := win.ErrorCodes[code]
+			if n == 0 {
+				continue
+			}
+			c.printf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %d</title></rect>`,
+				x0+1, c.y(float64(stacked+n)), math.Max(1, x1-x0-2), c.y(float64(stacked))-c.y(float64(stacked+n)), colors[i], html.EscapeString(code), n)
+			stacked += n
+		}
+	}
+	c.legend(codes, colors)
+	return c.svg()
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ms":      func(d time.Duration) string { return strconv.FormatFloat(ms(d), 'f', 1, 64) },
+	"percent": func(f float64) string { return strconv.FormatFloat(100*f, 'f', 2, 64) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font: 14px/1.4 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 24px auto; max-width: 940px; color: #222; }
+h1 { font-size: 22px; } h2 { font-size: 17px; margin-top: 32px; }
+table { border-collapse: collapse; margin: 8px 0; }
+th, td { padding: 3px 10px; border-bottom: 1px solid #ddd; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+tr.total td { font-weight: bold; }
+p.note { color: #666; margin: 4px 0; }
+svg { display: block; }
+svg .grid { stroke: #e5e5e5; } svg .frame { fill: none; stroke: #999; }
+svg .tick { font-size: 11px; fill: #444; } svg .label { font-size: 12px; fill: #222; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+{{- range .Meta}}
+<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Latency (ms)</h2>
+<table>
+<tr><th>Client</th><th>Count</th><th>RPS</th><th>Mean</th><th>P50</th><th>P90</th><th>P99</th><th>P99.9</th><th>Max</th><th>Errors</th><th>Err%</th></tr>
+{{- with .Total}}
+<tr class="total"><td>ALL</td><td>{{.Count}}</td><td>{{printf "%.1f" .Throughput}}</td><td>{{ms .Mean}}</td><td>{{ms .P50}}</td><td>{{ms .P90}}</td><td>{{ms .P99}}</td><td>{{ms .P999}}</td><td>{{ms .Max}}</td><td>{{.Errors}}</td><td>{{percent .ErrorRate}}</td></tr>
+{{- end}}
+{{- range .Clients}}
+<tr><td>{{.Name}}</td><td>{{.Count}}</td><td>{{printf "%.1f" .Throughput}}</td><td>{{ms .Mean}}</td><td>{{ms .P50}}</td><td>{{ms .P90}}</td><td>{{ms .P99}}</td><td>{{ms .P999}}</td><td>{{ms .Max}}</td><td>{{.Errors}}</td><td>{{percent .ErrorRate}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Latency over time</h2>
+{{if .Scatter}}<p class="note">A uniform sample of requests. Failed requests are marked along the top.</p>
+{{.Scatter}}{{else}}<p class="note">No request samples in this report.</p>{{end}}
+
+<h2>Percentiles over time</h2>
+{{if .Percentiles}}{{.Percentiles}}{{else}}<p class="note">No time windows in this report.</p>{{end}}
+
+<h2>Percentile distribution</h2>
+{{if .Distribution}}{{.Distribution}}{{else}}<p class="note">No successful requests.</p>{{end}}
+
+<h2>Histogram</h2>
+{{if .Histogram}}{{.Histogram}}{{else}}<p class="note">No successful requests.</p>{{end}}
+
+<h2>P99 by client and time</h2>
+{{if .Heatmap}}<p class="note">{{.HeatmapScale}}. Grey: no requests. Black: only failures.</p>
+{{.Heatmap}}{{else}}<p class="note">No per-client windows in this report.</p>{{end}}
+
+<h2>Errors over time</h2>
+{{if .Errors}}{{.Errors}}{{else}}<p class="note">No failed requests.</p>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteHTML(t *testing.T) {
+	r := testReport()
+	r.Clients[0].Name = "<client-1>"
+	r.ClientWindows = [][]Cell{{{Count: 100, P50: 50 * time.Millisecond, P99: 99 * time.Millisecond}}}
+	for i := 0; i < 100; i++ {
+		r.Samples = append(r.Samples, Sample{Offset: time.Duration(i) * 100 * time.Millisecond, Client: "client-1", Latency: time.Duration(i+1) * time.Millisecond})
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if n := strings.Count(out, "<svg "); n != 5 {
+		t.Errorf("%d charts, want scatter, percentiles, distribution, histogram and heatmap", n)
+	}
+	for _, want := range []string{"<td>vitpose_ensemble (version latest)</td>", "&lt;client-1&gt;", "P99 from 99.0 ms", "No failed requests.", ">99%<"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML does not contain %q", want)
+		}
+	}
+	if strings.Contains(out, "<client-1>") {
+		t.Error("client ID is not escaped")
+	}
+
+	r.Samples = append(r.Samples, Sample{Offset: 5 * time.Second, Client: "client-1", Error: "Unavailable"})
+	r.Total = r.Total.WithErrors(map[string]int64{"Unavailable": 1})
+	r.Windows[0].Summary = r.Windows[0].WithErrors(map[string]int64{"Unavailable": 1})
+	buf.Reset()
+	if err := r.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); strings.Count(out, "<svg ") != 6 || !strings.Contains(out, "<title>Unavailable: 1</title>") || !strings.Contains(out, "client-1: Unavailable") {
+		t.Error("error timeline or failed samples are missing")
+	}
+}
+
+func TestWriteHTMLLongClientID(t *testing.T) {
+	r := testReport()
+	r.Clients[0].Name = "부하테스트-클라이언트-1"
+	r.ClientWindows = [][]Cell{{{Count: 100, P50: 50 * time.Millisecond, P99: 99 * time.Millisecond}}}
+	var buf bytes.Buffer
+	if err := r.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !utf8.Valid(buf.Bytes()) {
+		t.Error("HTML is not valid UTF-8")
+	}
+	if want := ">부하테스트-클라이…<title>부하테스트-클라이언트-1</title>"; !strings.Contains(buf.String(), want) {
+		t.Errorf("HTML does not contain the heatmap label %q", want)
+	}
+}
+
+func TestWriteHTMLEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Report{}).WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<svg ") || !strings.Contains(buf.String(), "No successful requests.") {
+		t.Errorf("empty report:\n%s", buf.String())
+	}
+}
+
+func TestNiceTicks(t *testing.T) {
+	var got []string
+	for _, tk := range niceTicks(0.3, 1.05, 4, "") {
+		got = append(got, tk.label)
+	}
+	if want := []string{"0.4", "0.6", "0.8", "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("niceTicks = %v, want %v", got, want)
+	}
+	if heatColor(0) != "#ffffcc" || heatColor(1) != "#bd0026" {
+		t.Errorf("heatColor ends = %s, %s", heatColor(0), heatColor(1))
+	}
+}
@@ -0,0 +1,42 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+)
+
+// FetchServerInfo ServerMetadata 로 서버 이름과 버전을 채운다. ModelVersion 이 비어 있으면
+// ModelMetadata 의 가장 높은 버전 (Triton 이 기본으로 쓰는 최신 버전) 으로 채운다.
+func (m *Metadata) FetchServerInfo(ctx context.Context, client triton.GRPCInferenceServiceClient) error {
+	server, err := client.ServerMetadata(ctx, &triton.ServerMetadataRequest{})
+	if err != nil {
+		return fmt.Errorf("report: server metadata: %w", err)
+	}
+	m.ServerName = server.Name
+	m.ServerVersion = server.Version
+
+	if m.ModelName == "" || m.ModelVersion != "" {
+		return nil
+	}
+	model, err := client.ModelMetadata(ctx, &triton.ModelMetadataRequest{Name: m.ModelName})
+	if err != nil {
+		return fmt.Errorf("report: model metadata: %w", err)
+	}
+	m.ModelVersion = latestVersion(model.Versions)
+	return nil
+}
+
+// latestVersion 숫자로 가장 높은 버전. Triton 은 버전 목록의 순서를 정하지 않으므로 마지막 값을 쓰지 않는다.
+// 숫자인 버전이 없으면 빈 문자열이다.
+func latestVersion(versions []string) string {
+	latest, latestNum := "", int64(-1)
+	for _, version := range versions {
+		if n, err := strconv.ParseInt(version, 10, 64); err == nil && n > latestNum {
+			latest, latestNum = version, n
+		}
+	}
+	return latest
+}
@@ -0,0 +1,204 @@
+// Package report 는 부하 테스트 결과를 보관·비교할 수 있는 형식 (JSON, CSV, Markdown) 으로 쓴다.
+//
+// JSON 보고서는 전체 히스토그램을 담으므로 Load 로 다시 읽어 두 실행을 비교할 수 있다.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+)
+
+// Metadata 실행 조건. 서버 정보는 ServerMetadata 와 ModelMetadata 로 채운다.
+type Metadata struct {
+	RunName       string    `json:"run_name,omitempty"`
+	StartedAt     time.Time `json:"started_at"`
+	FinishedAt    time.Time `json:"finished_at"`
+	ServerURL     string    `json:"server_url,omitempty"`
+	ServerName    string    `json:"server_name,omitempty"`
+	ServerVersion string    `json:"server_version,omitempty"`
+	ModelName     string    `json:"model_name,omitempty"`
+	ModelVersion  string    `json:"model_version,omitempty"`
+	BatchSize     int       `json:"batch_size,omitempty"`
+	Concurrency   int       `json:"concurrency,omitempty"`
+	LoadMode      string    `json:"load_mode,omitempty"`
+}
+
+// Summary 히스토그램 하나의 요약. 레이턴시는 ns 단위이고 성공한 요청만 센다.
+type Summary struct {
+	Name   string `json:"name"`
+	Count  int64  `json:"count"`
+	Errors int64  `json:"errors,omitempty"`
+	// ErrorCodes gRPC 상태 코드 ("Unavailable", "DeadlineExceeded" 등) 별 실패 수
+	ErrorCodes map[string]int64   `json:"error_codes,omitempty"`
+	Throughput float64            `json:"throughput"`
+	Mean       time.Duration      `json:"mean_ns"`
+	StdDev     time.Duration      `json:"stddev_ns"`
+	Min        time.Duration      `json:"min_ns"`
+	Max        time.Duration      `json:"max_ns"`
+	P50        time.Duration      `json:"p50_ns"`
+	P90        time.Duration      `json:"p90_ns"`
+	P95        time.Duration      `json:"p95_ns"`
+	P99        time.Duration      `json:"p99_ns"`
+	P999       time.Duration      `json:"p999_ns"`
+	Histogram  []histogram.Bucket `json:"histogram,omitempty"`
+}
+
+// NewSummary h 를 요약한다. elapsed 는 처리량 계산에 쓴다.
+func NewSummary(name string, h *histogram.Histogram, elapsed time.Duration) Summary {
+	s := Summary{
+		Name:      name,
+		Count:     h.Count(),
+		Mean:      h.Mean(),
+		StdDev:    h.StdDev(),
+		Min:       h.Min(),
+		Max:       h.Max(),
+		P50:       h.Percentile(50),
+		P90:       h.Percentile(90),
+		P95:       h.Percentile(95),
+		P99:       h.Percentile(99),
+		P999:      h.Percentile(99.9),
+		Histogram: h.Buckets(),
+	}
+	if elapsed > 0 {
+		s.Throughput = float64(s.Count) / elapsed.Seconds()
+	}
+	return s
+}
+
+// WithErrors codes 별 실패 수를 더한 요약
+func (s Summary) WithErrors(codes map[string]int64) Summary {
+	if len(codes) == 0 {
+		return s
+	}
+	merged := make(map[string]int64, len(s.ErrorCodes)+len(codes))
+	for code, n := range s.ErrorCodes {
+		merged[code] = n
+	}
+	for code, n := range codes {
+		merged[code] += n
+		s.Errors += n
+	}
+	s.ErrorCodes = merged
+	return s
+}
+
+// Requests 성공과 실패를 합친 요청 수
+func (s Summary) Requests() int64 { return s.Count + s.Errors }
+
+// ErrorRate 실패한 요청의 비율 (0-1)
+func (s Summary) ErrorRate() float64 {
+	if s.Requests() == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Requests())
+}
+
+// Window 시간 구간 하나의 요약
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Summary
+}
+
+// Cell 클라이언트 하나의 시간 구간 하나. HTML 보고서의 클라이언트별 히트맵 칸이다.
+type Cell struct {
+	Count  int64         `json:"count"`
+	Errors int64         `json:"errors,omitempty"`
+	P50    time.Duration `json:"p50_ns"`
+	P99    time.Duration `json:"p99_ns"`
+}
+
+// Sample 요청 하나. Offset 은 Metadata.StartedAt 부터의 시간이다.
+type Sample struct {
+	Offset  time.Duration `json:"offset_ns"`
+	Client  string        `json:"client"`
+	Latency time.Duration `json:"latency_ns,omitempty"`
+	// Error 가 있으면 실패한 요청이고 값은 gRPC 상태 코드 이름이다.
+	Error string `json:"error,omitempty"`
+}
+
+// Report 한 번의 실행 결과
+type Report struct {
+	Metadata Metadata  `json:"metadata"`
+	Total    Summary   `json:"total"`
+	Clients  []Summary `json:"clients"`
+	Windows  []Window  `json:"windows"`
+	// ClientWindows Clients 와 같은 순서로, 클라이언트마다 Windows 와 같은 구간의 요약
+	ClientWindows [][]Cell `json:"client_windows,omitempty"`
+	// Samples 실행 전체에서 고르게 뽑은 요청. 산점도에 쓴다.
+	Samples []Sample `json:"samples,omitempty"`
+}
+
+// Load path 의 JSON 보고서를 읽는다.
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("report: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("report: parse %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// WriteJSON 히스토그램을 포함한 전체 보고서를 JSON 으로 쓴다.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteFiles dir 에 <이름>.json, <이름>-clients.csv, <이름>-windows.csv, <이름>.md, <이름>.html 을 쓰고 경로를 돌려준다.
+// 이름은 실행 이름 (없으면 "run") 과 시작 시각으로 정해지므로 같은 디렉토리에 여러 실행을 보관할 수 있다.
+func (r *Report) WriteFiles(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("report: %w", err)
+	}
+	prefix := "run"
+	if r.Metadata.RunName != "" {
+		prefix = r.Metadata.RunName
+	}
+	name := prefix + "-" + r.Metadata.StartedAt.Format("20060102-150405")
+	files := []struct {
+		suffix string
+		write  func(io.Writer) error
+	}{
+		{".json", r.WriteJSON},
+		{"-clients.csv", r.WriteClientsCSV},
+		{"-windows.csv", r.WriteWindowsCSV},
+		{".md", r.WriteMarkdown},
+		{".html", r.WriteHTML},
+	}
+	var paths []string
+	for _, file := range files {
+		path := filepath.Join(dir, name+file.suffix)
+		if err := writeFile(path, file.write); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("report: %w", err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("report: write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
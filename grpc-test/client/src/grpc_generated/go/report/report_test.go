@@ -0,0 +1,181 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+)
+
+func testReport() *Report {
+	h := histogram.NewLatency()
+	for i := 1; i <= 100; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	started := time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC)
+	return &Report{
+		Metadata: Metadata{
+			StartedAt:  started,
+			FinishedAt: started.Add(10 * time.Second),
+			ServerURL:  "localhost:8001",
+			ModelName:  "vitpose_ensemble",
+			BatchSize:  4,
+		},
+		Total:   NewSummary("ALL", h, 10*time.Second),
+		Clients: []Summary{NewSummary("client-1", h, 10*time.Second)},
+		Windows: []Window{{Start: started, End: started.Add(10 * time.Second), Summary: NewSummary("", h, 10*time.Second)}},
+	}
+}
+
+func TestNewSummary(t *testing.T) {
+	s := testReport().Total
+	if s.Count != 100 || s.Throughput != 10 {
+		t.Errorf("Count, Throughput = %d, %v, want 100, 10", s.Count, s.Throughput)
+	}
+	if s.P50 < 50*time.Millisecond || s.P50 > 50100*time.Microsecond || s.Max != 100*time.Millisecond {
+		t.Errorf("P50, Max = %v, %v", s.P50, s.Max)
+	}
+	var n int64
+	for _, b := range s.Histogram {
+		n += b.Count
+	}
+	if n != 100 {
+		t.Errorf("histogram has %d values, want 100", n)
+	}
+}
+
+func TestWriteFilesAndLoad(t *testing.T) {
+	r := testReport()
+	dir := t.TempDir()
+	paths, err := r.WriteFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"run-20241001-090000.json", "run-20241001-090000-clients.csv", "run-20241001-090000-windows.csv", "run-20241001-090000.md", "run-20241001-090000.html"}
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	for i, path := range paths {
+		if filepath.Base(path) != want[i] {
+			t.Errorf("paths[%d] = %s, want %s", i, path, want[i])
+		}
+	}
+
+	loaded, err := Load(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, r) {
+		t.Errorf("Load(WriteJSON(r)) = %+v, want %+v", loaded, r)
+	}
+
+	f, err := os.Open(paths[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[1][0] != "client-1" || records[2][0] != "ALL" || records[2][1] != "100" {
+		t.Errorf("clients.csv = %v", records)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"vitpose_ensemble (version latest)", "| Batch size | 4 |", "| **ALL** | 100 | 10.0 |", "| client-1 |"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("markdown does not contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	r := testReport()
+	r.Clients[0] = r.Clients[0].WithErrors(map[string]int64{"Unavailable": 20, "DeadlineExceeded": 5})
+	r.Total = r.Total.WithErrors(r.Clients[0].ErrorCodes)
+	if r.Total.Errors != 25 || r.Total.Requests() != 125 || r.Total.ErrorRate() != 0.2 {
+		t.Errorf("Errors, Requests, ErrorRate = %d, %d, %v, want 25, 125, 0.2", r.Total.Errors, r.Total.Requests(), r.Total.ErrorRate())
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## Errors", "| Client | Requests | Errors | Err% | DeadlineExceeded | Unavailable |", "| client-1 | 125 | 25 | 20.00 | 5 | 20 |"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("markdown does not contain %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := r.WriteClientsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := len(records[0]) - 1
+	if records[0][last] != "error_rate" || records[1][last-1] != "25" || records[1][last] != "0.2000" {
+		t.Errorf("clients.csv = %v", records)
+	}
+}
+
+func TestFetchServerInfo(t *testing.T) {
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository("../../../../../../pose_model_zoo"); err != nil {
+		t.Fatal(err)
+	}
+	server.Start()
+	defer server.Close()
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	meta := Metadata{ModelName: "vitpose"}
+	if err := meta.FetchServerInfo(context.Background(), triton.NewGRPCInferenceServiceClient(conn)); err != nil {
+		t.Fatal(err)
+	}
+	if meta.ServerName != "tritontest" || meta.ServerVersion != "2.46.0" || meta.ModelVersion != "1" {
+		t.Errorf("metadata = %+v", meta)
+	}
+
+	meta = Metadata{ModelName: "resnet"}
+	if err := meta.FetchServerInfo(context.Background(), triton.NewGRPCInferenceServiceClient(conn)); err == nil {
+		t.Error("FetchServerInfo found an unknown model")
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	tests := []struct {
+		versions []string
+		want     string
+	}{
+		{[]string{"1"}, "1"},
+		{[]string{"10", "9", "2"}, "10"},
+		{[]string{"2", "10", "9"}, "10"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := latestVersion(tt.versions); got != tt.want {
+			t.Errorf("latestVersion(%v) = %q, want %q", tt.versions, got, tt.want)
+		}
+	}
+}
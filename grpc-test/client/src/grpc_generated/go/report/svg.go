@@ -0,0 +1,151 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 차트 크기 (px). 그림 영역 바깥에 축 눈금과 이름이 들어갈 여백을 둔다.
+const (
+	chartWidth   = 900
+	chartHeight  = 280
+	marginLeft   = 64
+	marginRight  = 16
+	marginTop    = 12
+	marginBottom = 40
+)
+
+// chart 축이 하나씩 있는 SVG 차트. 좌표는 데이터 값으로 받아 px 로 바꾼다.
+type chart struct {
+	b              strings.Builder
+	height         float64
+	xMin, xMax     float64
+	yMin, yMax     float64
+	xLabel, yLabel string
+}
+
+func newChart(xMin, xMax, yMin, yMax float64) *chart {
+	if xMax <= xMin {
+		xMax = xMin + 1
+	}
+	if yMax <= yMin {
+		yMax = yMin + 1
+	}
+	return &chart{height: chartHeight, xMin: xMin, xMax: xMax, yMin: yMin, yMax: yMax}
+}
+
+func (c *chart) plotWidth() float64  { return chartWidth - marginLeft - marginRight }
+func (c *chart) plotHeight() float64 { return c.height - marginTop - marginBottom }
+
+// x, y 데이터 값의 px 좌표
+func (c *chart) x(v float64) float64 {
+	return marginLeft + (v-c.xMin)/(c.xMax-c.xMin)*c.plotWidth()
+}
+
+func (c *chart) y(v float64) float64 {
+	return marginTop + (1-(v-c.yMin)/(c.yMax-c.yMin))*c.plotHeight()
+}
+
+func (c *chart) printf(format string, args ...any) {
+	fmt.Fprintf(&c.b, format, args...)
+}
+
+// axes 눈금과 격자, 축 이름을 그린다. xTicks, yTicks 는 데이터 값과 표시할 글자다.
+func (c *chart) axes(xTicks, yTicks []tick) {
+	left, right := float64(marginLeft), chartWidth-float64(marginRight)
+	top, bottom := float64(marginTop), c.height-marginBottom
+	for _, t := range yTicks {
+		y := c.y(t.value)
+		c.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" class="grid"/>`, left, y, right, y)
+		c.printf(`<text x="%.1f" y="%.1f" class="tick" text-anchor="end">%s</text>`, left-6, y+4, html.EscapeString(t.label))
+	}
+	for _, t := range xTicks {
+		x := c.x(t.value)
+		c.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" class="grid"/>`, x, top, x, bottom)
+		c.printf(`<text x="%.1f" y="%.1f" class="tick" text-anchor="middle">%s</text>`, x, bottom+16, html.EscapeString(t.label))
+	}
+	c.printf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" class="frame"/>`, left, top, c.plotWidth(), c.plotHeight())
+	if c.xLabel != "" {
+		c.printf(`<text x="%.1f" y="%.1f" class="label" text-anchor="middle">%s</text>`, left+c.plotWidth()/2, c.height-6, html.EscapeString(c.xLabel))
+	}
+	if c.yLabel != "" {
+		c.printf(`<text transform="translate(14 %.1f) rotate(-90)" class="label" text-anchor="middle">%s</text>`, top+c.plotHeight()/2, html.EscapeString(c.yLabel))
+	}
+}
+
+// polyline 점들을 잇는 선. 이름이 있으면 마우스를 올렸을 때 보인다.
+func (c *chart) polyline(xs, ys []float64, color, name string) {
+	if len(xs) == 0 {
+		return
+	}
+	c.printf(`<polyline fill="none" stroke="%s" stroke-width="1.8" points="`, color)
+	for i := range xs {
+		c.printf("%.1f,%.1f ", c.x(xs[i]), c.y(ys[i]))
+	}
+	c.printf(`"><title>%s</title></polyline>`, html.EscapeString(name))
+}
+
+// legend 그림 영역 오른쪽 위에 색과 이름을 나열한다.
+func (c *chart) legend(names, colors []string) {
+	x := chartWidth - float64(marginRight) - 8
+	for i := len(names) - 1; i >= 0; i-- {
+		x -= 7*float64(len(names[i])) + 22
+		c.printf(`<rect x="%.1f" y="%d" width="10" height="10" fill="%s"/>`, x, marginTop+6, colors[i])
+		c.printf(`<text x="%.1f" y="%d" class="tick">%s</text>`, x+14, marginTop+15, html.EscapeString(names[i]))
+	}
+}
+
+func (c *chart) svg() template.HTML {
+	return template.HTML(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %.0f" width="%d" height="%.0f">%s</svg>`,
+		chartWidth, c.height, chartWidth, c.height, c.b.String()))
+}
+
+type tick struct {
+	value float64
+	label string
+}
+
+// niceTicks min 과 max 사이에 1, 2, 5 의 10 배수 간격으로 눈금을 n 개 안팎 만든다.
+func niceTicks(min, max float64, n int, unit string) []tick {
+	if max <= min || n < 1 {
+		return nil
+	}
+	raw := (max - min) / float64(n)
+	step := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if step*m >= raw {
+			step *= m
+			break
+		}
+	}
+	var ticks []tick
+	for v := math.Ceil(min/step) * step; v <= max+step*1e-9; v += step {
+		// 0.1 + 0.2 같은 부동소수점 오차가 눈금에 보이지 않도록 간격 단위로 반올림한다.
+		v = math.Round(v/step) * step
+		ticks = append(ticks, tick{v, strconv.FormatFloat(v, 'g', 6, 64) + unit})
+	}
+	return ticks
+}
+
+// palette 여러 계열을 구분하는 색
+var palette = []string{"#1f77b4", "#2ca02c", "#ff7f0e", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"}
+
+// heatColor 0 (빠름) 부터 1 (느림) 까지를 연노랑에서 진빨강으로 칠한다.
+func heatColor(f float64) string {
+	f = math.Max(0, math.Min(1, f))
+	stops := [][3]float64{{255, 255, 204}, {253, 141, 60}, {189, 0, 38}}
+	i := 0
+	if f > 0.5 {
+		i, f = 1, f-0.5
+	}
+	f *= 2
+	var rgb [3]int
+	for k := range rgb {
+		rgb[k] = int(math.Round(stops[i][k] + (stops[i+1][k]-stops[i][k])*f))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
@@ -0,0 +1,376 @@
+// Package repository 는 Triton 의 모델 저장소 API (RepositoryIndex, RepositoryModelLoad, RepositoryModelUnload) 로
+// 서버를 다시 띄우지 않고 모델을 올리고 내린다. Triton 이 --model-control-mode=explicit 으로 떠 있어야 한다.
+//
+// 앙상블은 ensemble_scheduling 의 단계 모델 (vitpose, postprocess) 을 먼저 올린 뒤 올리고, 앙상블이 쓰고 있는
+// 단계 모델은 앙상블을 내리기 전에는 내리지 않는다. 설정은 서버의 ModelConfig 에서 찾고, 서버에 없는 모델은
+// Client.Repo 의 config.pbtxt 에서 찾는다.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+)
+
+// Model RepositoryIndex 의 한 줄. 올라가지 않은 모델은 Version 이 비어 있다.
+type Model struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// State READY, UNAVAILABLE, LOADING, UNLOADING 등
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Client triton.GRPCInferenceServiceClient 로 모델을 관리한다.
+type Client struct {
+	triton triton.GRPCInferenceServiceClient
+	// Repo 서버에 올라가 있지 않은 모델의 <Repo>/<model>/config.pbtxt 를 읽을 로컬 모델 저장소 (예: pose_model_zoo).
+	// 비어 있으면 그런 모델의 단계는 알 수 없으므로 앙상블 단계를 미리 올리지 못한다.
+	Repo string
+	// PollInterval 올리거나 내린 뒤 ModelReady 를 확인하는 주기
+	PollInterval time.Duration
+}
+
+// NewClient 0.5초마다 ModelReady 를 확인하는 Client
+func NewClient(client triton.GRPCInferenceServiceClient) *Client {
+	return &Client{triton: client, PollInterval: 500 * time.Millisecond}
+}
+
+// Index 저장소의 모델. readyOnly 면 READY 인 것만 돌려준다.
+func (c *Client) Index(ctx context.Context, readyOnly bool) ([]Model, error) {
+	resp, err := c.triton.RepositoryIndex(ctx, &triton.RepositoryIndexRequest{Ready: readyOnly})
+	if err != nil {
+		return nil, fmt.Errorf("repository: index: %w", err)
+	}
+	var models []Model
+	for _, m := range resp.Models {
+		models = append(models, Model{Name: m.Name, Version: m.Version, State: m.State, Reason: m.Reason})
+	}
+	return models, nil
+}
+
+// LoadOptions Load 의 선택 사항
+type LoadOptions struct {
+	// Versions 이 버전들만 올린다. 설정의 version_policy 를 specific 으로 바꿔 보낸다.
+	// 앙상블이 쓰는 버전과 새 버전을 함께 올려 두고 비교할 때 둘 다 적는다.
+	Versions []string
+	// Config 저장소의 config.pbtxt 대신 쓸 설정 (JSON). 예: {"max_batch_size": 8}
+	// Triton 은 이 설정으로 config.pbtxt 를 통째로 대신하므로 필요한 필드를 모두 담아야 한다.
+	Config string
+	// NoDeps 앙상블의 단계 모델을 먼저 올리지 않는다.
+	NoDeps bool
+	// Wait 올린 뒤 ModelReady 가 될 때까지 기다린다.
+	Wait bool
+	// Loaded 모델 하나를 올릴 때마다 부른다. nil 이면 부르지 않는다.
+	Loaded func(name string)
+}
+
+// Load name 을 올린다. 앙상블이면 (NoDeps 가 아니면) 아직 ready 가 아닌 단계 모델을 먼저 올리고 ready 가 될 때까지 기다린다.
+func (c *Client) Load(ctx context.Context, name string, opts LoadOptions) error {
+	var override *triton.ModelConfig
+	if opts.Config != "" {
+		override = &triton.ModelConfig{}
+		if err := protojson.Unmarshal([]byte(opts.Config), override); err != nil {
+			return fmt.Errorf("repository: config override for %s: %w", name, err)
+		}
+	}
+	if len(opts.Versions) > 0 {
+		versions := make([]int64, 0, len(opts.Versions))
+		for _, version := range opts.Versions {
+			v, err := strconv.ParseInt(version, 10, 64)
+			if err != nil || v <= 0 {
+				return fmt.Errorf("repository: invalid version %q", version)
+			}
+			versions = append(versions, v)
+		}
+		var err error
+		if override, err = c.pinVersions(ctx, name, override, versions); err != nil {
+			return err
+		}
+	}
+
+	if !opts.NoDeps {
+		deps, err := c.Dependencies(ctx, name, override)
+		if err != nil {
+			return err
+		}
+		for _, dep := range deps {
+			if ready, err := c.ready(ctx, dep.Name, dep.Version); err == nil && ready {
+				continue
+			}
+			// 버전을 고정한 단계는 Triton 의 기본 정책 (최신 버전 하나) 으로 올리면 그 버전이 올라가지 않을 수 있으므로
+			// 이미 올라간 버전에 고정한 버전을 더해 specific 으로 올린다.
+			var depOverride *triton.ModelConfig
+			if dep.Version != "" {
+				pinned, _ := strconv.ParseInt(dep.Version, 10, 64)
+				versions := append(c.readyVersions(ctx, dep.Name), pinned)
+				if depOverride, err = c.pinVersions(ctx, dep.Name, nil, versions); err != nil {
+					return err
+				}
+			}
+			if err := c.load(ctx, dep.Name, depOverride); err != nil {
+				return err
+			}
+			if err := c.WaitReady(ctx, dep.Name, dep.Version); err != nil {
+				return err
+			}
+			if opts.Loaded != nil {
+				opts.Loaded(dep.Name)
+			}
+		}
+	}
+
+	if err := c.load(ctx, name, override); err != nil {
+		return err
+	}
+	if opts.Wait {
+		versions := opts.Versions
+		if len(versions) == 0 {
+			versions = []string{""}
+		}
+		for _, version := range versions {
+			if err := c.WaitReady(ctx, name, version); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.Loaded != nil {
+		opts.Loaded(name)
+	}
+	return nil
+}
+
+// pinVersions override (nil 이면 name 의 지금 설정) 의 version_policy 를 versions 만 올리는 specific 으로 바꾼다.
+func (c *Client) pinVersions(ctx context.Context, name string, override *triton.ModelConfig, versions []int64) (*triton.ModelConfig, error) {
+	if override == nil {
+		// version_policy 만 바꿔 보낼 수는 없으므로 지금 설정을 통째로 가져와 고친다.
+		config, err := c.Config(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("repository: versions %v of %s need the model config: %w", versions, name, err)
+		}
+		override = config
+	}
+	slices.Sort(versions)
+	override.VersionPolicy = &triton.ModelVersionPolicy{PolicyChoice: &triton.ModelVersionPolicy_Specific_{
+		Specific: &triton.ModelVersionPolicy_Specific{Versions: slices.Compact(versions)},
+	}}
+	return override, nil
+}
+
+// readyVersions name 의 올라가 있는 버전. 저장소 목록을 읽지 못하면 비어 있다.
+func (c *Client) readyVersions(ctx context.Context, name string) []int64 {
+	models, err := c.Index(ctx, true)
+	if err != nil {
+		return nil
+	}
+	var versions []int64
+	for _, m := range models {
+		if v, err := strconv.ParseInt(m.Version, 10, 64); m.Name == name && err == nil {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+func (c *Client) load(ctx context.Context, name string, override *triton.ModelConfig) error {
+	req := &triton.RepositoryModelLoadRequest{ModelName: name}
+	if override != nil {
+		override.Name = name
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(override)
+		if err != nil {
+			return fmt.Errorf("repository: config override for %s: %w", name, err)
+		}
+		req.Parameters = map[string]*triton.ModelRepositoryParameter{
+			"config": {ParameterChoice: &triton.ModelRepositoryParameter_StringParam{StringParam: string(data)}},
+		}
+	}
+	if _, err := c.triton.RepositoryModelLoad(ctx, req); err != nil {
+		return fmt.Errorf("repository: load %s: %w", name, err)
+	}
+	return nil
+}
+
+// UnloadOptions Unload 의 선택 사항
+type UnloadOptions struct {
+	// Dependents 앙상블의 단계 모델도 함께 내린다 (Triton 의 unload_dependents).
+	Dependents bool
+	// Force 올라가 있는 앙상블이 쓰고 있는 모델도 내린다.
+	Force bool
+	// Wait 내린 뒤 ModelReady 가 false 가 될 때까지 기다린다.
+	Wait bool
+}
+
+// Unload name 을 내린다. Force 가 아니면 name 을 단계로 쓰는 앙상블이 올라가 있을 때 실패한다.
+func (c *Client) Unload(ctx context.Context, name string, opts UnloadOptions) error {
+	if !opts.Force {
+		users, err := c.Dependents(ctx, name)
+		if err != nil {
+			return err
+		}
+		if len(users) > 0 {
+			return fmt.Errorf("repository: %s is a step of loaded ensemble %s; unload that first", name, users[0])
+		}
+	}
+	req := &triton.RepositoryModelUnloadRequest{ModelName: name}
+	if opts.Dependents {
+		req.Parameters = map[string]*triton.ModelRepositoryParameter{
+			"unload_dependents": {ParameterChoice: &triton.ModelRepositoryParameter_BoolParam{BoolParam: true}},
+		}
+	}
+	if _, err := c.triton.RepositoryModelUnload(ctx, req); err != nil {
+		return fmt.Errorf("repository: unload %s: %w", name, err)
+	}
+	if opts.Wait {
+		return c.waitFor(ctx, name, "", false)
+	}
+	return nil
+}
+
+// Step 앙상블 단계 하나. Version 이 비어 있으면 최신 버전이다.
+type Step struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Dependencies name 보다 먼저 올려야 할 모델을 올릴 순서대로 돌려준다. 앙상블 안의 앙상블도 따라간다.
+// config 가 nil 이 아니면 name 의 설정으로 쓴다 (config override).
+func (c *Client) Dependencies(ctx context.Context, name string, config *triton.ModelConfig) ([]Step, error) {
+	var order []Step
+	visiting := map[string]bool{}
+	done := map[string]bool{}
+	var visit func(name string, config *triton.ModelConfig) error
+	visit = func(name string, config *triton.ModelConfig) error {
+		if visiting[name] {
+			return fmt.Errorf("repository: ensemble %s depends on itself", name)
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		if config == nil {
+			var err error
+			if config, err = c.Config(ctx, name); err != nil {
+				// 설정을 못 찾는 단계는 더 따라가지 않는다. 올릴 때 Triton 이 알려 준다.
+				return nil
+			}
+		}
+		for _, step := range config.GetEnsembleScheduling().GetStep() {
+			if done[step.ModelName] {
+				continue
+			}
+			if err := visit(step.ModelName, nil); err != nil {
+				return err
+			}
+			done[step.ModelName] = true
+			order = append(order, Step{Name: step.ModelName, Version: stepVersion(step)})
+		}
+		return nil
+	}
+	if err := visit(name, config); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Dependents name 을 단계로 쓰는 올라가 있는 앙상블
+func (c *Client) Dependents(ctx context.Context, name string) ([]string, error) {
+	models, err := c.Index(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	var users []string
+	for _, m := range models {
+		if m.Name == name || slices.Contains(users, m.Name) {
+			continue
+		}
+		resp, err := c.triton.ModelConfig(ctx, &triton.ModelConfigRequest{Name: m.Name, Version: m.Version})
+		if err != nil {
+			continue
+		}
+		for _, step := range resp.GetConfig().GetEnsembleScheduling().GetStep() {
+			if step.ModelName == name {
+				users = append(users, m.Name)
+				break
+			}
+		}
+	}
+	return users, nil
+}
+
+// Config name 의 설정. 서버에 올라가 있으면 ModelConfig 로, 아니면 Repo 의 config.pbtxt 에서 읽는다.
+func (c *Client) Config(ctx context.Context, name string) (*triton.ModelConfig, error) {
+	resp, err := c.triton.ModelConfig(ctx, &triton.ModelConfigRequest{Name: name})
+	if err == nil {
+		return resp.Config, nil
+	}
+	if c.Repo == "" {
+		return nil, err
+	}
+	data, ferr := os.ReadFile(filepath.Join(c.Repo, name, "config.pbtxt"))
+	if ferr != nil {
+		return nil, errors.Join(err, ferr)
+	}
+	config := &triton.ModelConfig{}
+	if err := prototext.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("repository: parse config of %s: %w", name, err)
+	}
+	if config.Name == "" {
+		config.Name = name
+	}
+	return config, nil
+}
+
+// WaitReady ModelReady 가 true 가 될 때까지 PollInterval 마다 확인한다.
+func (c *Client) WaitReady(ctx context.Context, name, version string) error {
+	return c.waitFor(ctx, name, version, true)
+}
+
+func (c *Client) waitFor(ctx context.Context, name, version string, want bool) error {
+	for {
+		ready, err := c.ready(ctx, name, version)
+		if err == nil && ready == want {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			what := "ready"
+			if !want {
+				what = "unloaded"
+			}
+			if err == nil {
+				err = ctx.Err()
+			}
+			return fmt.Errorf("repository: %s did not become %s: %w", name, what, err)
+		case <-time.After(c.PollInterval):
+		}
+	}
+}
+
+func (c *Client) ready(ctx context.Context, name, version string) (bool, error) {
+	resp, err := c.triton.ModelReady(ctx, &triton.ModelReadyRequest{Name: name, Version: version})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return resp.Ready, nil
+}
+
+// stepVersion 단계의 model_version. -1 (최신) 이면 빈 문자열이다.
+func stepVersion(step *triton.ModelEnsembling_Step) string {
+	if v := step.GetModelVersion(); v > 0 {
+		return strconv.FormatInt(v, 10)
+	}
+	return ""
+}
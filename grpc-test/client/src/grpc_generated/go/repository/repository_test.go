@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+)
+
+const repo = "../../../../../../pose_model_zoo"
+
+// startServer --model-control-mode=explicit 로 막 띄운 Triton 처럼 저장소에는 있지만 올라간 모델이 없는 서버
+func startServer(t *testing.T) (*tritontest.Server, *Client) {
+	t.Helper()
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository(repo); err != nil {
+		t.Fatal(err)
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	c := NewClient(triton.NewGRPCInferenceServiceClient(conn))
+	c.PollInterval = 10 * time.Millisecond
+	for _, name := range []string{"vitpose_ensemble", "vitpose", "postprocess"} {
+		if err := c.Unload(context.Background(), name, UnloadOptions{Force: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return server, c
+}
+
+func states(t *testing.T, c *Client) string {
+	t.Helper()
+	models, err := c.Index(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s []string
+	for _, m := range models {
+		s = append(s, m.Name+":"+m.Version+":"+m.State)
+	}
+	return strings.Join(s, " ")
+}
+
+func TestLoadEnsemble(t *testing.T) {
+	_, c := startServer(t)
+	ctx := context.Background()
+	if got := states(t, c); got != "postprocess::UNAVAILABLE vitpose::UNAVAILABLE vitpose_ensemble::UNAVAILABLE" {
+		t.Fatalf("index = %s", got)
+	}
+
+	// 서버에 없는 모델의 단계는 Repo 없이는 모르므로 Triton 이 거절한다.
+	if err := c.Load(ctx, "vitpose_ensemble", LoadOptions{}); err == nil || !strings.Contains(err.Error(), "no loaded version") {
+		t.Fatalf("Load without Repo = %v, want the missing step error", err)
+	}
+
+	c.Repo = repo
+	deps, err := c.Dependencies(ctx, "vitpose_ensemble", nil)
+	if err != nil || len(deps) != 2 || deps[0] != (Step{"vitpose", "1"}) || deps[1] != (Step{"postprocess", "1"}) {
+		t.Fatalf("Dependencies = %v, %v", deps, err)
+	}
+	var loaded []string
+	if err := c.Load(ctx, "vitpose_ensemble", LoadOptions{Wait: true, Loaded: func(name string) { loaded = append(loaded, name) }}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(loaded, []string{"vitpose", "postprocess", "vitpose_ensemble"}) {
+		t.Errorf("load order = %v", loaded)
+	}
+	if got := states(t, c); got != "postprocess:1:READY vitpose:1:READY vitpose_ensemble:1:READY" {
+		t.Errorf("index = %s", got)
+	}
+
+	// 이미 올라간 단계는 다시 올리지 않는다.
+	loaded = nil
+	if err := c.Load(ctx, "vitpose_ensemble", LoadOptions{Loaded: func(name string) { loaded = append(loaded, name) }}); err != nil || len(loaded) != 1 {
+		t.Errorf("reload = %v, %v; want only the ensemble", loaded, err)
+	}
+
+	if err := c.Unload(ctx, "postprocess", UnloadOptions{}); err == nil || !strings.Contains(err.Error(), "vitpose_ensemble") {
+		t.Errorf("Unload of a step in use = %v", err)
+	}
+	if err := c.Unload(ctx, "vitpose_ensemble", UnloadOptions{Dependents: true, Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+	if ready, err := c.triton.ModelReady(ctx, &triton.ModelReadyRequest{Name: "postprocess"}); err != nil || ready.Ready {
+		t.Errorf("postprocess still ready after unloading dependents: %v, %v", ready, err)
+	}
+}
+
+func TestLoadVersion(t *testing.T) {
+	server, c := startServer(t)
+	ctx := context.Background()
+	config, err := tritontest.LoadModelConfig(repo + "/vitpose/config.pbtxt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.AddRepositoryModel(config, "1", "2")
+
+	if err := c.Load(ctx, "vitpose", LoadOptions{Versions: []string{"2"}}); err == nil || !strings.Contains(err.Error(), "need the model config") {
+		t.Errorf("version load without a config = %v", err)
+	}
+	c.Repo = repo
+	if err := c.Load(ctx, "vitpose", LoadOptions{Versions: []string{"2"}, Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := states(t, c); !strings.Contains(got, "vitpose:2:READY") || strings.Contains(got, "vitpose:1:") {
+		t.Errorf("index = %s, want only version 2 of vitpose", got)
+	}
+	if err := c.Load(ctx, "vitpose", LoadOptions{Versions: []string{"1", "2"}, Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := states(t, c); !strings.Contains(got, "vitpose:1:READY vitpose:2:READY") {
+		t.Errorf("index = %s, want both versions of vitpose", got)
+	}
+	if err := c.Load(ctx, "vitpose", LoadOptions{Versions: []string{"3"}}); err == nil {
+		t.Error("loaded a version that is not in the repository")
+	}
+
+	override := `{"platform": "tensorrt_plan", "max_batch_size": 8}`
+	if err := c.Load(ctx, "vitpose", LoadOptions{Config: override, Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.triton.ModelConfig(ctx, &triton.ModelConfigRequest{Name: "vitpose"})
+	if err != nil || resp.Config.MaxBatchSize != 8 || resp.Config.Name != "vitpose" {
+		t.Errorf("config after override = %v, %v", resp, err)
+	}
+	if err := c.Load(ctx, "vitpose", LoadOptions{Config: `{"max_batch": 8}`}); err == nil {
+		t.Error("accepted an override with an unknown field")
+	}
+}
+
+func TestLoadPinnedDependency(t *testing.T) {
+	server, c := startServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	config, err := tritontest.LoadModelConfig(repo + "/vitpose/config.pbtxt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.AddRepositoryModel(config, "1", "2")
+	c.Repo = repo
+
+	// Triton 의 기본 정책은 최신 버전 하나만 올린다.
+	if err := c.Load(ctx, "vitpose", LoadOptions{Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := states(t, c); !strings.Contains(got, "vitpose:2:READY") || strings.Contains(got, "vitpose:1:") {
+		t.Fatalf("index = %s, want only the latest version of vitpose", got)
+	}
+
+	// 앙상블이 고정한 버전 1 은 이미 올라간 버전 2 와 함께 올린다.
+	if err := c.Load(ctx, "vitpose_ensemble", LoadOptions{Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := states(t, c); !strings.Contains(got, "vitpose:1:READY vitpose:2:READY") || !strings.Contains(got, "vitpose_ensemble:1:READY") {
+		t.Errorf("index = %s, want vitpose 1 and 2 with the ensemble", got)
+	}
+
+	// 아무것도 올라가 있지 않아도 고정한 버전을 올린다.
+	if err := c.Unload(ctx, "vitpose_ensemble", UnloadOptions{Dependents: true, Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Load(ctx, "vitpose_ensemble", LoadOptions{Wait: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := states(t, c); !strings.Contains(got, "vitpose:1:READY") || strings.Contains(got, "vitpose:2:") {
+		t.Errorf("index = %s, want only the pinned version of vitpose", got)
+	}
+}
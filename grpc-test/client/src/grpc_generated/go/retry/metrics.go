@@ -0,0 +1,105 @@
+package retry
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Stats 이름 하나 (gRPC 메서드 등) 의 호출과 재시도 수
+type Stats struct {
+	Calls int64 `json:"calls"`
+	// Retries 첫 시도를 뺀 시도 수
+	Retries int64 `json:"retries"`
+	// Recovered 다시 시도해서 성공한 호출 수
+	Recovered int64 `json:"recovered"`
+	// Failed 재시도까지 마치고도 실패한 호출 수
+	Failed int64 `json:"failed"`
+	// RetriedCodes 재시도를 일으킨 실패의 상태 코드별 수
+	RetriedCodes map[string]int64 `json:"retried_codes,omitempty"`
+}
+
+// Metrics 여러 고루틴이 함께 쓰는 재시도 통계. 0 값을 그대로 쓸 수 있다.
+type Metrics struct {
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+// NewMetrics 빈 통계
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// record 호출 하나를 센다. m 이 nil 이면 아무것도 하지 않는다.
+func (m *Metrics) record(name string, retried []codes.Code, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stats == nil {
+		m.stats = make(map[string]*Stats)
+	}
+	s, ok := m.stats[name]
+	if !ok {
+		s = &Stats{RetriedCodes: make(map[string]int64)}
+		m.stats[name] = s
+	}
+	s.Calls++
+	s.Retries += int64(len(retried))
+	for _, code := range retried {
+		s.RetriedCodes[code.String()]++
+	}
+	switch {
+	case err != nil:
+		s.Failed++
+	case len(retried) > 0:
+		s.Recovered++
+	}
+}
+
+// Snapshot 지금까지의 통계를 이름별로 복사한다.
+func (m *Metrics) Snapshot() map[string]Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]Stats, len(m.stats))
+	for name, s := range m.stats {
+		c := *s
+		c.RetriedCodes = make(map[string]int64, len(s.RetriedCodes))
+		for code, n := range s.RetriedCodes {
+			c.RetriedCodes[code] = n
+		}
+		snapshot[name] = c
+	}
+	return snapshot
+}
+
+// WriteText 이름마다 한 줄씩 쓴다. 예: "ModelInfer: 120 calls, 7 retries, 5 recovered, 1 failed (Unavailable 7)"
+func (m *Metrics) WriteText(w io.Writer) error {
+	snapshot := m.Snapshot()
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		s := snapshot[name]
+		line := fmt.Sprintf("%s: %d calls, %d retries, %d recovered, %d failed", name, s.Calls, s.Retries, s.Recovered, s.Failed)
+		if len(s.RetriedCodes) > 0 {
+			codes := make([]string, 0, len(s.RetriedCodes))
+			for code, n := range s.RetriedCodes {
+				codes = append(codes, fmt.Sprintf("%s %d", code, n))
+			}
+			slices.Sort(codes)
+			line += " (" + strings.Join(codes, ", ") + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+// Package retry 는 Triton gRPC 호출을 지수 백오프로 다시 시도하는 정책을 제공한다.
+//
+// 다시 시도하는 것은 서버가 잠시 받지 못한 요청 (Unavailable, ResourceExhausted, Aborted) 과
+// 시도 하나의 제한 시간이 지난 요청뿐이다. InvalidArgument 처럼 다시 보내도 같은 결과가 나올 요청은
+// 바로 실패한다. Policy 는 Do 로 직접 쓰거나 UnaryClientInterceptor 로 커넥션의 모든 unary 호출에 건다.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultCodes 기본으로 다시 시도하는 gRPC 상태 코드
+var DefaultCodes = []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted}
+
+// Policy 다시 시도하는 방법. 0 값 필드는 Default 의 값을 쓰지 않으므로 Default 에서 시작해 고친다.
+type Policy struct {
+	// MaxAttempts 첫 시도를 포함한 최대 시도 수. 1 이하면 다시 시도하지 않는다.
+	MaxAttempts int
+	// InitialBackoff 첫 재시도 전에 기다리는 시간. 재시도마다 Multiplier 배씩 늘어 MaxBackoff 에서 멈춘다.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter 기다리는 시간을 ±Jitter 비율 (0-1) 안에서 무작위로 흔들어 클라이언트들이 한꺼번에 다시 보내지 않게 한다.
+	Jitter float64
+	// AttemptTimeout 시도 하나의 제한 시간. 지나면 DeadlineExceeded 로 실패하고 다시 시도한다. 0 이면 없다.
+	AttemptTimeout time.Duration
+	// Timeout 재시도와 기다림을 모두 합친 제한 시간. 0 이면 ctx 의 데드라인만 따른다.
+	Timeout time.Duration
+	// Codes 다시 시도할 상태 코드. nil 이면 DefaultCodes 이다.
+	Codes []codes.Code
+	// Metrics nil 이 아니면 호출마다 재시도 수를 센다.
+	Metrics *Metrics
+	// Skip UnaryClientInterceptor 가 다시 시도하지 않고 한 번만 부를 메서드 이름 (예: "ModelInfer").
+	// Metrics 에는 재시도 없는 호출로 남는다.
+	Skip []string
+}
+
+// Default 3 번까지 시도하고 100ms 부터 2s 까지 두 배씩 기다리는 정책
+func Default() *Policy {
+	return &Policy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// Retryable err 가 다시 시도할 실패인지. 시도 하나의 제한 시간이 지난 것은 Do 가 따로 판단한다.
+func (p *Policy) Retryable(err error) bool {
+	codes := p.Codes
+	if codes == nil {
+		codes = DefaultCodes
+	}
+	return err != nil && slices.Contains(codes, status.Code(err))
+}
+
+// Backoff retry 번째 (1 부터) 재시도 전에 기다릴 시간. Jitter 가 있으면 부를 때마다 다르다.
+func (p *Policy) Backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 {
+		backoff = min(backoff, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		backoff *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(backoff)
+}
+
+// Do fn 을 성공하거나, 다시 시도할 수 없는 실패가 나오거나, 시도 수나 시간이 다 될 때까지 부른다.
+// name 은 Metrics 에 남길 이름 (예: gRPC 메서드) 이다. p 가 nil 이면 fn 을 한 번만 부른다.
+func (p *Policy) Do(ctx context.Context, name string, fn func(context.Context) error) error {
+	if p == nil {
+		return fn(ctx)
+	}
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	var retried []codes.Code
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = p.attempt(ctx, fn); err == nil || attempt >= p.MaxAttempts || !p.retryable(ctx, err) {
+			break
+		}
+		wait := p.Backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			// 기다리고 나면 시간이 없으므로 마지막 실패를 그대로 돌려준다.
+			break
+		}
+		retried = append(retried, status.Code(err))
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			p.Metrics.record(name, retried, err)
+			return err
+		}
+	}
+	p.Metrics.record(name, retried, err)
+	return err
+}
+
+func (p *Policy) attempt(ctx context.Context, fn func(context.Context) error) error {
+	if p.AttemptTimeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.AttemptTimeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// retryable ctx 가 아직 살아 있을 때만 다시 시도한다. AttemptTimeout 으로 끊긴 시도도 다시 시도한다.
+func (p *Policy) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if p.AttemptTimeout > 0 && status.Code(err) == codes.DeadlineExceeded {
+		return true
+	}
+	return p.Retryable(err)
+}
+
+// UnaryClientInterceptor 커넥션의 unary 호출 (ModelInfer, ServerReady 등) 을 p 로 다시 시도한다.
+// Metrics 에는 메서드 이름 (예: "ModelInfer") 으로 남는다.
+func (p *Policy) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		name := methodName(method)
+		if slices.Contains(p.Skip, name) {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			p.Metrics.record(name, nil, err)
+			return err
+		}
+		return p.Do(ctx, name, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// DialOption config.Dial 등에 넘겨 UnaryClientInterceptor 를 건다.
+func (p *Policy) DialOption() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(p.UnaryClientInterceptor())
+}
+
+// methodName "/inference.GRPCInferenceService/ModelInfer" 의 "ModelInfer"
+func methodName(method string) string {
+	for i := len(method) - 1; i >= 0; i-- {
+		if method[i] == '/' {
+			return method[i+1:]
+		}
+	}
+	return method
+}
+
+// ParseCode "Unavailable" 이나 "UNAVAILABLE" 같은 이름을 gRPC 상태 코드로 바꾼다.
+func ParseCode(name string) (codes.Code, error) {
+	var c codes.Code
+	if err := c.UnmarshalJSON([]byte(`"` + name + `"`)); err == nil {
+		return c, nil
+	}
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if c.String() == name {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("retry: unknown gRPC code %q", name)
+}
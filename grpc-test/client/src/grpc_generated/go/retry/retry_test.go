@@ -0,0 +1,180 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failing 처음 n 번은 code 로 실패하는 함수
+func failing(n int, code codes.Code, calls *int) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls <= n {
+			return status.Error(code, "fail")
+		}
+		return nil
+	}
+}
+
+func fastPolicy() *Policy {
+	p := Default()
+	p.InitialBackoff, p.MaxBackoff = time.Millisecond, 4*time.Millisecond
+	p.Metrics = NewMetrics()
+	return p
+}
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		code      codes.Code
+		wantCalls int
+		wantCode  codes.Code
+	}{
+		{"ok", 0, codes.OK, 1, codes.OK},
+		{"recovered", 2, codes.Unavailable, 3, codes.OK},
+		{"exhausted", 5, codes.ResourceExhausted, 3, codes.ResourceExhausted},
+		{"not retryable", 5, codes.InvalidArgument, 1, codes.InvalidArgument},
+		{"deadline without attempt timeout", 5, codes.DeadlineExceeded, 1, codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := fastPolicy().Do(context.Background(), "ModelInfer", failing(tt.failures, tt.code, &calls))
+			if calls != tt.wantCalls || status.Code(err) != tt.wantCode {
+				t.Errorf("calls = %d, err = %v; want %d calls and %s", calls, err, tt.wantCalls, tt.wantCode)
+			}
+		})
+	}
+
+	var nilPolicy *Policy
+	calls := 0
+	if err := nilPolicy.Do(context.Background(), "x", failing(1, codes.Unavailable, &calls)); err == nil || calls != 1 {
+		t.Errorf("nil policy: calls = %d, err = %v", calls, err)
+	}
+}
+
+func TestDoDeadlines(t *testing.T) {
+	p := fastPolicy()
+	p.AttemptTimeout = 10 * time.Millisecond
+	calls := 0
+	slowOnce := func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return nil
+	}
+	if err := p.Do(context.Background(), "x", slowOnce); err != nil || calls != 2 {
+		t.Errorf("attempt timeout: calls = %d, err = %v; want a retry after the slow attempt", calls, err)
+	}
+
+	// 기다릴 시간이 전체 제한 시간을 넘으면 다시 시도하지 않는다.
+	p = fastPolicy()
+	p.InitialBackoff, p.MaxBackoff, p.Timeout = time.Second, time.Second, 50*time.Millisecond
+	calls = 0
+	start := time.Now()
+	err := p.Do(context.Background(), "x", failing(5, codes.Unavailable, &calls))
+	if status.Code(err) != codes.Unavailable || calls != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("overall timeout: calls = %d, err = %v after %v", calls, err, time.Since(start))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	if err := fastPolicy().Do(ctx, "x", failing(5, codes.Unavailable, &calls)); err == nil || calls != 1 {
+		t.Errorf("cancelled: calls = %d, err = %v", calls, err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := &Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	for retry, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		if got := p.Backoff(retry + 1); got != want*time.Millisecond {
+			t.Errorf("Backoff(%d) = %v, want %v", retry+1, got, want*time.Millisecond)
+		}
+	}
+	p.Jitter = 0.5
+	for range 100 {
+		if got := p.Backoff(2); got < 100*time.Millisecond || got > 300*time.Millisecond {
+			t.Fatalf("Backoff(2) with jitter = %v, want within 200ms ± 50%%", got)
+		}
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	p := fastPolicy()
+	interceptor := p.UnaryClientInterceptor()
+	calls := 0
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return failing(1, codes.Unavailable, &calls)(ctx)
+	}
+	for range 2 {
+		calls = 0
+		if err := interceptor(context.Background(), "/inference.GRPCInferenceService/ModelInfer", nil, nil, nil, invoker); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bad := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.InvalidArgument, "bad shape")
+	}
+	interceptor(context.Background(), "/inference.GRPCInferenceService/ModelInfer", nil, nil, nil, bad)
+	interceptor(context.Background(), "/inference.GRPCInferenceService/ServerReady", nil, nil, nil, bad)
+
+	s := p.Metrics.Snapshot()["ModelInfer"]
+	if s.Calls != 3 || s.Retries != 2 || s.Recovered != 2 || s.Failed != 1 || s.RetriedCodes["Unavailable"] != 2 {
+		t.Errorf("ModelInfer stats = %+v", s)
+	}
+	var buf bytes.Buffer
+	p.Metrics.WriteText(&buf)
+	want := "ModelInfer: 3 calls, 2 retries, 2 recovered, 1 failed (Unavailable 2)\nServerReady: 1 calls, 0 retries, 0 recovered, 1 failed\n"
+	if buf.String() != want {
+		t.Errorf("WriteText =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestInterceptorSkip(t *testing.T) {
+	p := fastPolicy()
+	p.Skip = []string{"ModelInfer"}
+	interceptor := p.UnaryClientInterceptor()
+	calls := 0
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return failing(1, codes.Unavailable, &calls)(ctx)
+	}
+	if err := interceptor(context.Background(), "/inference.GRPCInferenceService/ModelInfer", nil, nil, nil, invoker); status.Code(err) != codes.Unavailable || calls != 1 {
+		t.Errorf("ModelInfer = %v after %d calls, want Unavailable after 1", err, calls)
+	}
+	calls = 0
+	if err := interceptor(context.Background(), "/inference.GRPCInferenceService/ServerReady", nil, nil, nil, invoker); err != nil || calls != 2 {
+		t.Errorf("ServerReady = %v after %d calls, want success after 2", err, calls)
+	}
+	if s := p.Metrics.Snapshot()["ModelInfer"]; s.Calls != 1 || s.Retries != 0 || s.Failed != 1 {
+		t.Errorf("ModelInfer stats = %+v", s)
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	for _, name := range []string{"Unavailable", "UNAVAILABLE"} {
+		if c, err := ParseCode(name); err != nil || c != codes.Unavailable {
+			t.Errorf("ParseCode(%q) = %v, %v", name, c, err)
+		}
+	}
+	if c, err := ParseCode("ResourceExhausted"); err != nil || c != codes.ResourceExhausted {
+		t.Errorf("ParseCode(ResourceExhausted) = %v, %v", c, err)
+	}
+	if _, err := ParseCode("Sometimes"); err == nil {
+		t.Error("ParseCode accepted an unknown code")
+	}
+	var e error = errors.New("plain")
+	if fastPolicy().Retryable(e) {
+		t.Error("a non-gRPC error is retryable")
+	}
+}
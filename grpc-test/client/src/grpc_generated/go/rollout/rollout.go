@@ -0,0 +1,439 @@
+// Package rollout 은 앙상블 단계 모델 (vitpose) 의 새 버전을 서버를 다시 띄우지 않고 카나리 방식으로 내보낸다.
+//
+// pose_model_zoo/vitpose/2 에 새 model.plan 을 넣으면:
+//  1. vitpose 를 지금 앙상블이 쓰는 버전과 새 버전을 함께 올린다 (version_policy specific).
+//  2. 같은 샘플 입력을 두 버전에 직접 보내 (shadow) 키포인트 차이와 지연 시간을 비교한다.
+//  3. 허용치 안이면 앙상블 설정의 model_version 을 새 버전으로 바꿔 RepositoryModelLoad 의 config override 로 다시 올린다.
+//  4. 바꾼 뒤 Verify 동안 앙상블에 추론을 보내 보고, 실패하면 원래 설정으로 되돌린다.
+//
+// 앙상블은 단계의 model_version 을 고정하고 있어야 한다. -1 (최신) 이면 새 버전을 올리는 순간 앙상블이 바로 쓰기 때문이다.
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/heatmap"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/repository"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+// Options Run 의 설정. Defaults 에서 시작해 Ensemble, Step, To, Samples 를 채운다.
+type Options struct {
+	// Ensemble 바꿀 앙상블 (vitpose_ensemble)
+	Ensemble string
+	// Step 새 버전을 내보낼 단계 모델 (vitpose)
+	Step string
+	// To 새 버전. 저장소에 있어야 한다. 지금 버전은 앙상블 설정의 model_version 에서 읽는다.
+	To string
+	// OutputName 단계 모델의 히트맵 출력 이름
+	OutputName string
+	// Samples 비교에 쓸 입력. 배치마다 Rounds 번씩 두 버전에 번갈아 보낸다.
+	Samples []*vitpose.Batch
+	Rounds  int
+
+	// MinScore 지금 버전의 신뢰도가 이보다 낮은 키포인트는 위치가 불안정하므로 비교하지 않는다.
+	MinScore float32
+	// MaxMeanDrift 두 버전 키포인트 거리의 평균 허용치 (픽셀)
+	MaxMeanDrift float64
+	// MaxDrift 키포인트 하나의 거리 허용치 (픽셀). 0 이면 보지 않는다.
+	MaxDrift float64
+	// MaxLatencyRatio 새 버전 p99 / 지금 버전 p99 의 허용치
+	MaxLatencyRatio float64
+	// MinLatencyIncrease p99 가 이만큼 넘게 늘어난 경우에만 MaxLatencyRatio 를 적용한다. 1ms 남짓한 지연 시간의 잡음을 무시한다.
+	MinLatencyIncrease time.Duration
+	// MinLatencySamples 새 버전의 성공한 요청이 이보다 적으면 p99 는 사실상 가장 느린 요청 하나이므로 p50 으로 MaxLatencyRatio 를 적용한다.
+	MinLatencySamples int
+
+	// Verify 앙상블을 바꾼 뒤 추론을 보내 확인할 시간. 0 이면 한 번만 보낸다.
+	Verify         time.Duration
+	VerifyInterval time.Duration
+	// Rollback Verify 에 실패하면 앙상블을 원래 설정으로 되돌린다.
+	Rollback bool
+	// Retire 바꾼 뒤 단계 모델에서 예전 버전을 내린다.
+	Retire bool
+	// DryRun 비교만 하고 앙상블은 바꾸지 않는다. 새 버전도 다시 내린다.
+	DryRun bool
+
+	// Logf 진행 상황을 남긴다. nil 이면 남기지 않는다.
+	Logf func(format string, args ...any)
+}
+
+// Defaults vitpose_ensemble 의 vitpose 를 바꾸는 기본 설정
+func Defaults() Options {
+	return Options{
+		Ensemble:           vitpose.DefaultModelName,
+		Step:               "vitpose",
+		OutputName:         heatmap.DefaultOutputName,
+		Rounds:             5,
+		MinScore:           0.3,
+		MaxMeanDrift:       2,
+		MaxDrift:           8,
+		MaxLatencyRatio:    1.2,
+		MinLatencyIncrease: 2 * time.Millisecond,
+		MinLatencySamples:  100,
+		Verify:             30 * time.Second,
+		VerifyInterval:     time.Second,
+		Rollback:           true,
+	}
+}
+
+// Outcome Run 의 결과
+type Outcome string
+
+const (
+	// Passed DryRun 에서 비교를 통과했다.
+	Passed Outcome = "passed"
+	// Switched 앙상블이 새 버전을 쓴다.
+	Switched Outcome = "switched"
+	// Rejected 비교에서 떨어져 앙상블을 바꾸지 않았다.
+	Rejected Outcome = "rejected"
+	// RolledBack 바꾼 뒤 확인에 실패해 원래 설정으로 되돌렸다.
+	RolledBack Outcome = "rolled_back"
+	// Failed 바꾼 뒤 확인에 실패했지만 되돌리지 않았다 (Rollback 이 false).
+	Failed Outcome = "failed"
+)
+
+// Latency 한 버전의 지연 시간 요약
+type Latency struct {
+	Count int64         `json:"count"`
+	Mean  time.Duration `json:"mean_ns"`
+	P50   time.Duration `json:"p50_ns"`
+	P99   time.Duration `json:"p99_ns"`
+}
+
+// Comparison shadow 비교 결과. 거리는 Batch.Boxes 가 있으면 원본 이미지, 없으면 256x192 crop 의 픽셀이다.
+type Comparison struct {
+	Requests  int     `json:"requests"`
+	Keypoints int     `json:"keypoints"`
+	MeanDrift float64 `json:"mean_drift"`
+	MaxDrift  float64 `json:"max_drift"`
+	// MaxDriftJoint MaxDrift 가 나온 관절
+	MaxDriftJoint string  `json:"max_drift_joint,omitempty"`
+	Baseline      Latency `json:"baseline"`
+	Candidate     Latency `json:"candidate"`
+	// LatencyRatio Candidate.P99 / Baseline.P99
+	LatencyRatio float64 `json:"latency_ratio"`
+	// LatencyPercentile MaxLatencyRatio 를 적용한 백분위. 요청이 MinLatencySamples 보다 적으면 99 가 아니라 50 이다.
+	LatencyPercentile int `json:"latency_percentile"`
+	// Errors 새 버전에 보낸 요청 중 실패한 수. 하나라도 있으면 떨어진다.
+	Errors    int    `json:"errors"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Verification 앙상블을 바꾼 뒤의 확인 결과
+type Verification struct {
+	Requests  int    `json:"requests"`
+	Errors    int    `json:"errors"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Result Run 이 한 일
+type Result struct {
+	Ensemble     string        `json:"ensemble"`
+	Step         string        `json:"step"`
+	From         string        `json:"from"`
+	To           string        `json:"to"`
+	Outcome      Outcome       `json:"outcome,omitempty"`
+	Comparison   *Comparison   `json:"comparison,omitempty"`
+	Verification *Verification `json:"verification,omitempty"`
+	// Reasons Rejected, RolledBack, Failed 인 이유
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// WriteText 사람이 읽을 요약을 w 에 쓴다.
+func (r *Result) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "%s: %s version %s -> %s\n", r.Ensemble, r.Step, r.From, r.To)
+	if c := r.Comparison; c != nil {
+		fmt.Fprintf(w, "  drift:    mean %.2fpx, max %.2fpx", c.MeanDrift, c.MaxDrift)
+		if c.MaxDriftJoint != "" {
+			fmt.Fprintf(w, " (%s)", c.MaxDriftJoint)
+		}
+		fmt.Fprintf(w, " over %d keypoints\n", c.Keypoints)
+		fmt.Fprintf(w, "  p50:      %v -> %v\n", c.Baseline.P50, c.Candidate.P50)
+		fmt.Fprintf(w, "  p99:      %v -> %v (x%.2f)\n", c.Baseline.P99, c.Candidate.P99, c.LatencyRatio)
+		fmt.Fprintf(w, "  requests: %d, %d failed on %s\n", c.Requests, c.Errors, r.To)
+		if c.LatencyPercentile != 99 {
+			fmt.Fprintf(w, "  latency:  insufficient samples for p99 (%d requests), checked p50\n", c.Candidate.Count)
+		}
+	}
+	if v := r.Verification; v != nil {
+		fmt.Fprintf(w, "  verify:   %d requests, %d failed\n", v.Requests, v.Errors)
+	}
+	for _, reason := range r.Reasons {
+		fmt.Fprintf(w, "  reason:   %s\n", reason)
+	}
+	if r.Outcome != "" {
+		fmt.Fprintln(w, strings.ToUpper(string(r.Outcome)))
+	}
+}
+
+// Rollout 저장소 API 와 추론 API 로 새 버전을 내보낸다.
+type Rollout struct {
+	triton triton.GRPCInferenceServiceClient
+	repo   *repository.Client
+	opts   Options
+}
+
+// New client 로 opts 대로 내보내는 Rollout. repo 는 client 와 같은 서버를 가리켜야 한다.
+func New(client triton.GRPCInferenceServiceClient, repo *repository.Client, opts Options) *Rollout {
+	return &Rollout{triton: client, repo: repo, opts: opts}
+}
+
+func (r *Rollout) logf(format string, args ...any) {
+	if r.opts.Logf != nil {
+		r.opts.Logf(format, args...)
+	}
+}
+
+// Run 새 버전을 올려 비교하고, 통과하면 앙상블을 바꾼다. 에러는 서버와의 통신이나 모델을 올리는 데 실패한 경우이고,
+// 비교나 확인에 떨어진 것은 Result.Outcome 으로 알린다. 에러가 나도 그때까지의 Result 를 돌려준다.
+func (r *Rollout) Run(ctx context.Context) (*Result, error) {
+	opts := r.opts
+	result := &Result{Ensemble: opts.Ensemble, Step: opts.Step, To: opts.To}
+	if len(opts.Samples) == 0 {
+		return result, fmt.Errorf("rollout: no samples to compare")
+	}
+	to, err := strconv.ParseInt(opts.To, 10, 64)
+	if err != nil || to <= 0 {
+		return result, fmt.Errorf("rollout: invalid version %q", opts.To)
+	}
+
+	original, err := r.repo.Config(ctx, opts.Ensemble)
+	if err != nil {
+		return result, fmt.Errorf("rollout: config of %s: %w", opts.Ensemble, err)
+	}
+	step := findStep(original, opts.Step)
+	if step == nil {
+		return result, fmt.Errorf("rollout: %s has no step %s", opts.Ensemble, opts.Step)
+	}
+	if step.ModelVersion <= 0 {
+		return result, fmt.Errorf("rollout: %s uses the latest version of %s; pin model_version first", opts.Ensemble, opts.Step)
+	}
+	result.From = strconv.FormatInt(step.ModelVersion, 10)
+	if result.From == opts.To {
+		return result, fmt.Errorf("rollout: %s already uses version %s of %s", opts.Ensemble, opts.To, opts.Step)
+	}
+
+	r.logf("Loading %s versions %s and %s", opts.Step, result.From, opts.To)
+	if err := r.repo.Load(ctx, opts.Step, repository.LoadOptions{Versions: []string{result.From, opts.To}, NoDeps: true, Wait: true}); err != nil {
+		return result, err
+	}
+
+	comparison, err := r.compare(ctx, result.From, opts.To)
+	result.Comparison = comparison
+	if err != nil {
+		return result, r.restoreStep(ctx, result.From, err)
+	}
+	r.logf("Compared %d requests: drift mean %.2fpx max %.2fpx, p99 %v -> %v",
+		comparison.Requests, comparison.MeanDrift, comparison.MaxDrift, comparison.Baseline.P99, comparison.Candidate.P99)
+	if result.Reasons = opts.check(comparison); len(result.Reasons) > 0 {
+		result.Outcome = Rejected
+		return result, r.restoreStep(ctx, result.From, nil)
+	}
+	if opts.DryRun {
+		result.Outcome = Passed
+		return result, r.restoreStep(ctx, result.From, nil)
+	}
+
+	switched := proto.Clone(original).(*triton.ModelConfig)
+	findStep(switched, opts.Step).ModelVersion = to
+	r.logf("Switching %s to %s version %s", opts.Ensemble, opts.Step, opts.To)
+	if err := r.loadEnsemble(ctx, switched); err != nil {
+		return result, r.rollback(ctx, result, original, err)
+	}
+
+	result.Verification = r.verify(ctx)
+	if result.Verification.Errors > 0 {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%d of %d requests to %s failed after the switch: %s",
+			result.Verification.Errors, result.Verification.Requests, opts.Ensemble, result.Verification.LastError))
+		if !opts.Rollback {
+			result.Outcome = Failed
+			return result, nil
+		}
+		return result, r.rollback(ctx, result, original, nil)
+	}
+	result.Outcome = Switched
+
+	if opts.Retire {
+		r.logf("Unloading %s version %s", opts.Step, result.From)
+		if err := r.repo.Load(ctx, opts.Step, repository.LoadOptions{Versions: []string{opts.To}, NoDeps: true, Wait: true}); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// check 허용치를 넘은 항목
+func (o *Options) check(c *Comparison) []string {
+	var reasons []string
+	if c.Errors > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d of %d requests to the new version failed: %s", c.Errors, c.Requests, c.LastError))
+	}
+	if c.MeanDrift > o.MaxMeanDrift {
+		reasons = append(reasons, fmt.Sprintf("mean keypoint drift %.2fpx > %.2fpx", c.MeanDrift, o.MaxMeanDrift))
+	}
+	if o.MaxDrift > 0 && c.MaxDrift > o.MaxDrift {
+		reasons = append(reasons, fmt.Sprintf("keypoint drift %.2fpx (%s) > %.2fpx", c.MaxDrift, c.MaxDriftJoint, o.MaxDrift))
+	}
+	base, cand, ratio := c.Baseline.P99, c.Candidate.P99, c.LatencyRatio
+	if c.LatencyPercentile == 50 {
+		base, cand, ratio = c.Baseline.P50, c.Candidate.P50, 0
+		if base > 0 {
+			ratio = float64(cand) / float64(base)
+		}
+	}
+	if ratio > o.MaxLatencyRatio && cand-base > o.MinLatencyIncrease {
+		reasons = append(reasons, fmt.Sprintf("p%d latency %v -> %v (x%.2f > x%.2f)", c.LatencyPercentile, base, cand, ratio, o.MaxLatencyRatio))
+	}
+	return reasons
+}
+
+// compare Samples 를 두 버전에 번갈아 보내 키포인트 거리와 지연 시간을 잰다. 지금 버전이 실패하면 비교할 수 없으므로 에러다.
+func (r *Rollout) compare(ctx context.Context, from, to string) (*Comparison, error) {
+	client := func(version string) *vitpose.Client {
+		return vitpose.NewClient(r.triton,
+			vitpose.WithModelName(r.opts.Step),
+			vitpose.WithModelVersion(version),
+			vitpose.WithOutputName(r.opts.OutputName),
+			vitpose.WithDecoder(heatmap.NewDecoder(heatmap.DefaultOptions)),
+		)
+	}
+	baseline, candidate := client(from), client(to)
+	baseLatency, candLatency := histogram.NewLatency(), histogram.NewLatency()
+	c := &Comparison{}
+	var sum float64
+	rounds := max(r.opts.Rounds, 1)
+	for round := range rounds {
+		for _, batch := range r.opts.Samples {
+			// 캐시나 워밍업의 영향이 한쪽에만 가지 않도록 먼저 보내는 쪽을 번갈아 바꾼다.
+			var base, cand *vitpose.Result
+			var baseErr, candErr error
+			if round%2 == 0 {
+				base, baseErr = baseline.Infer(ctx, batch)
+				cand, candErr = candidate.Infer(ctx, batch)
+			} else {
+				cand, candErr = candidate.Infer(ctx, batch)
+				base, baseErr = baseline.Infer(ctx, batch)
+			}
+			if baseErr != nil {
+				return c, fmt.Errorf("rollout: %s version %s: %w", r.opts.Step, from, baseErr)
+			}
+			c.Requests++
+			baseLatency.Record(base.Latency)
+			if candErr != nil {
+				c.Errors++
+				c.LastError = candErr.Error()
+				continue
+			}
+			candLatency.Record(cand.Latency)
+			for i := range base.Poses {
+				for k, kp := range base.Poses[i].Keypoints {
+					if kp.Score < r.opts.MinScore {
+						continue
+					}
+					other := cand.Poses[i].Keypoints[k]
+					d := math.Hypot(float64(kp.X-other.X), float64(kp.Y-other.Y))
+					sum += d
+					c.Keypoints++
+					if d > c.MaxDrift {
+						c.MaxDrift, c.MaxDriftJoint = d, vitpose.Joint(k).String()
+					}
+				}
+			}
+		}
+	}
+	if c.Keypoints > 0 {
+		c.MeanDrift = sum / float64(c.Keypoints)
+	}
+	c.Baseline, c.Candidate = summarize(baseLatency), summarize(candLatency)
+	if c.Baseline.P99 > 0 {
+		c.LatencyRatio = float64(c.Candidate.P99) / float64(c.Baseline.P99)
+	}
+	c.LatencyPercentile = 99
+	if c.Candidate.Count < int64(r.opts.MinLatencySamples) {
+		c.LatencyPercentile = 50
+	}
+	return c, nil
+}
+
+func summarize(h *histogram.Histogram) Latency {
+	return Latency{Count: h.Count(), Mean: h.Mean(), P50: h.Percentile(50), P99: h.Percentile(99)}
+}
+
+// verify 바뀐 앙상블에 Verify 동안 VerifyInterval 마다 첫 샘플을 보낸다. 처음 한 번은 기다리지 않고 보낸다.
+func (r *Rollout) verify(ctx context.Context) *Verification {
+	client := vitpose.NewClient(r.triton, vitpose.WithModelName(r.opts.Ensemble))
+	v := &Verification{}
+	deadline := time.Now().Add(r.opts.Verify)
+	for {
+		v.Requests++
+		if _, err := client.Infer(ctx, r.opts.Samples[(v.Requests-1)%len(r.opts.Samples)]); err != nil {
+			v.Errors++
+			v.LastError = err.Error()
+		}
+		if ctx.Err() != nil || !time.Now().Add(r.opts.VerifyInterval).Before(deadline) {
+			return v
+		}
+		select {
+		case <-ctx.Done():
+			return v
+		case <-time.After(r.opts.VerifyInterval):
+		}
+	}
+}
+
+// rollback 앙상블을 original 로 다시 올리고 단계 모델을 예전 버전만 남긴다. cause 가 있으면 그 에러를 돌려준다.
+func (r *Rollout) rollback(ctx context.Context, result *Result, original *triton.ModelConfig, cause error) error {
+	r.logf("Rolling back %s to %s version %s", r.opts.Ensemble, r.opts.Step, result.From)
+	result.Outcome = RolledBack
+	if err := r.loadEnsemble(ctx, original); err != nil {
+		result.Outcome = Failed
+		if cause != nil {
+			return fmt.Errorf("%w; rollback: %w", cause, err)
+		}
+		return fmt.Errorf("rollout: rollback: %w", err)
+	}
+	return r.restoreStep(ctx, result.From, cause)
+}
+
+// restoreStep 단계 모델에 from 버전만 남긴다. cause 가 있으면 복구에 성공해도 cause 를 돌려준다.
+func (r *Rollout) restoreStep(ctx context.Context, from string, cause error) error {
+	r.logf("Unloading %s version %s", r.opts.Step, r.opts.To)
+	err := r.repo.Load(ctx, r.opts.Step, repository.LoadOptions{Versions: []string{from}, NoDeps: true, Wait: true})
+	switch {
+	case cause != nil && err != nil:
+		return fmt.Errorf("%w; restoring %s: %w", cause, r.opts.Step, err)
+	case cause != nil:
+		return cause
+	}
+	return err
+}
+
+func (r *Rollout) loadEnsemble(ctx context.Context, config *triton.ModelConfig) error {
+	data, err := protojson.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("rollout: %w", err)
+	}
+	return r.repo.Load(ctx, r.opts.Ensemble, repository.LoadOptions{Config: string(data), NoDeps: true, Wait: true})
+}
+
+// findStep config 의 앙상블 단계 중 이름이 name 인 것
+func findStep(config *triton.ModelConfig, name string) *triton.ModelEnsembling_Step {
+	steps := config.GetEnsembleScheduling().GetStep()
+	if i := slices.IndexFunc(steps, func(s *triton.ModelEnsembling_Step) bool { return s.ModelName == name }); i >= 0 {
+		return steps[i]
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package rollout
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/repository"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+const repo = "../../../../../../pose_model_zoo"
+
+// startServer vitpose 저장소에 버전 1, 2 가 있고 앙상블이 버전 1 을 쓰는 서버
+func startServer(t *testing.T) (*tritontest.Server, triton.GRPCInferenceServiceClient, *repository.Client) {
+	t.Helper()
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository(repo); err != nil {
+		t.Fatal(err)
+	}
+	config, err := tritontest.LoadModelConfig(repo + "/vitpose/config.pbtxt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.AddRepositoryModel(config, "1", "2")
+	server.Start()
+	t.Cleanup(server.Close)
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := triton.NewGRPCInferenceServiceClient(conn)
+	repoClient := repository.NewClient(client)
+	repoClient.PollInterval = 10 * time.Millisecond
+	return server, client, repoClient
+}
+
+func options() Options {
+	opts := Defaults()
+	opts.To = "2"
+	opts.Samples = []*vitpose.Batch{vitpose.RandomBatch(2)}
+	opts.Rounds = 2
+	opts.Verify = 0
+	// 몇 번 안 되는 요청의 p99 는 -race 나 느린 CI 에서 흔들리므로 지연 시간은 "latency" 에서만 본다.
+	opts.MaxLatencyRatio = math.Inf(1)
+	return opts
+}
+
+// shifted 히트맵 최댓값을 오른쪽으로 dx 칸 옮긴 vitpose 출력. 히트맵 한 칸은 crop 에서 약 4px 이다.
+func shifted(dx int) tritontest.OutputFunc {
+	return func(model, output string, shape []int64) []float32 {
+		data := tritontest.SyntheticOutput(model, output, shape)
+		w := int(shape[len(shape)-1])
+		moved := make([]float32, len(data))
+		for i := range data {
+			if x := i%w + dx; x >= 0 && x < w {
+				moved[i-i%w+x] = data[i]
+			}
+		}
+		return moved
+	}
+}
+
+// state 앙상블이 쓰는 vitpose 버전과 올라가 있는 vitpose 버전들
+func state(t *testing.T, client triton.GRPCInferenceServiceClient) (pinned int64, loaded string) {
+	t.Helper()
+	ctx := context.Background()
+	resp, err := client.ModelConfig(ctx, &triton.ModelConfigRequest{Name: "vitpose_ensemble"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinned = findStep(resp.Config, "vitpose").ModelVersion
+	index, err := client.RepositoryIndex(ctx, &triton.RepositoryIndexRequest{Ready: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, m := range index.Models {
+		if m.Name == "vitpose" {
+			versions = append(versions, m.Version)
+		}
+	}
+	return pinned, strings.Join(versions, ",")
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		behavior tritontest.VersionBehavior
+		change   func(*Options)
+		want     Outcome
+		reason   string
+		// pinned, loaded 끝난 뒤 앙상블이 쓰는 버전과 올라가 있는 vitpose 버전
+		pinned int64
+		loaded string
+	}{
+		{name: "switched", want: Switched, pinned: 2, loaded: "1,2"},
+		{name: "retired", change: func(o *Options) { o.Retire = true }, want: Switched, pinned: 2, loaded: "2"},
+		{name: "dry run", change: func(o *Options) { o.DryRun = true }, want: Passed, pinned: 1, loaded: "1"},
+		{name: "drift", behavior: tritontest.VersionBehavior{Output: shifted(2)}, want: Rejected, reason: "mean keypoint drift 8.17px > 2.00px", pinned: 1, loaded: "1"},
+		{name: "latency", behavior: tritontest.VersionBehavior{Latency: 200 * time.Millisecond}, change: func(o *Options) { o.MaxLatencyRatio = 1.2 }, want: Rejected, reason: "p50 latency", pinned: 1, loaded: "1"},
+		{name: "latency p99", behavior: tritontest.VersionBehavior{Latency: 200 * time.Millisecond}, change: func(o *Options) { o.MaxLatencyRatio, o.MinLatencySamples = 1.2, 2 }, want: Rejected, reason: "p99 latency", pinned: 1, loaded: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client, repoClient := startServer(t)
+			server.SetVersionBehavior("vitpose", "2", tt.behavior)
+			opts := options()
+			if tt.change != nil {
+				tt.change(&opts)
+			}
+			result, err := New(client, repoClient, opts).Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Outcome != tt.want || result.From != "1" {
+				t.Errorf("outcome = %s from %s, want %s from 1 (reasons %v)", result.Outcome, result.From, tt.want, result.Reasons)
+			}
+			if tt.reason != "" && (len(result.Reasons) == 0 || !strings.Contains(result.Reasons[0], tt.reason)) {
+				t.Errorf("reasons = %q, want the first containing %q", result.Reasons, tt.reason)
+			}
+			if c := result.Comparison; c == nil || c.Requests != 2 || c.Keypoints != 2*2*vitpose.NumKeypoints {
+				t.Errorf("comparison = %+v, want 2 requests over 2 poses", c)
+			}
+			if pinned, loaded := state(t, client); pinned != tt.pinned || loaded != tt.loaded {
+				t.Errorf("ensemble uses vitpose %d with versions %s loaded, want %d with %s", pinned, loaded, tt.pinned, tt.loaded)
+			}
+		})
+	}
+}
+
+func TestCheckLatency(t *testing.T) {
+	opts := Defaults()
+	// 스무 번 중 한 번 느렸던 요청이 p99 가 된다.
+	c := &Comparison{
+		Baseline:     Latency{Count: 20, P50: 3 * time.Millisecond, P99: 4 * time.Millisecond},
+		Candidate:    Latency{Count: 20, P50: 3 * time.Millisecond, P99: 40 * time.Millisecond},
+		LatencyRatio: 10,
+	}
+	c.LatencyPercentile = 50
+	if reasons := opts.check(c); len(reasons) != 0 {
+		t.Errorf("reasons = %q with too few samples for p99, want none", reasons)
+	}
+	var text strings.Builder
+	(&Result{Comparison: c}).WriteText(&text)
+	if !strings.Contains(text.String(), "insufficient samples for p99 (20 requests)") {
+		t.Errorf("text = %q, want the insufficient samples note", text.String())
+	}
+
+	c.LatencyPercentile = 99
+	if reasons := opts.check(c); len(reasons) != 1 || !strings.Contains(reasons[0], "p99 latency 4ms -> 40ms") {
+		t.Errorf("reasons = %q, want the p99 latency", reasons)
+	}
+}
+
+func TestRunRollback(t *testing.T) {
+	server, client, repoClient := startServer(t)
+	opts := options()
+	// 앙상블을 바꾸자마자 추론이 실패하기 시작한다.
+	opts.Logf = func(format string, args ...any) {
+		if strings.HasPrefix(format, "Switching") {
+			server.SetErrorRate(1, codes.Internal)
+		}
+	}
+	result, err := New(client, repoClient, opts).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != RolledBack || result.Verification == nil || result.Verification.Errors != 1 {
+		t.Errorf("outcome = %s, verification = %+v; want a rollback after 1 failed request", result.Outcome, result.Verification)
+	}
+	if pinned, loaded := state(t, client); pinned != 1 || loaded != "1" {
+		t.Errorf("after rollback the ensemble uses vitpose %d with versions %s loaded", pinned, loaded)
+	}
+
+	server.SetErrorRate(0, codes.Internal)
+	opts.Rollback = false
+	result, err = New(client, repoClient, opts).Run(context.Background())
+	if err != nil || result.Outcome != Failed {
+		t.Errorf("without rollback: outcome = %s, err = %v", result.Outcome, err)
+	}
+	if pinned, _ := state(t, client); pinned != 2 {
+		t.Errorf("without rollback the ensemble uses vitpose %d, want 2", pinned)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	_, client, repoClient := startServer(t)
+	tests := []struct {
+		name   string
+		change func(*Options)
+		want   string
+	}{
+		{"same version", func(o *Options) { o.To = "1" }, "already uses version 1"},
+		{"missing version", func(o *Options) { o.To = "3" }, "not found in model repository"},
+		{"unknown step", func(o *Options) { o.Step = "detector" }, "has no step detector"},
+		{"no samples", func(o *Options) { o.Samples = nil }, "no samples"},
+	}
+	for _, tt := range tests {
+		opts := options()
+		tt.change(&opts)
+		if _, err := New(client, repoClient, opts).Run(context.Background()); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
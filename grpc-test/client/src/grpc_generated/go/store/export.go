@@ -0,0 +1,73 @@
+package store
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ParseTime "2024-10-01", "2024-10-01 09:00" 또는 RFC 3339 형식의 시각. 시간대가 없으면 UTC 로 본다.
+func ParseTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("store: invalid time %q: want 2006-01-02, 2006-01-02 15:04 or RFC 3339", s)
+}
+
+// FilterFromQuery run, model, version, server_version, batch, since, until 쿼리 값으로 조건을 만든다.
+func FilterFromQuery(q url.Values) (Filter, error) {
+	f := Filter{
+		Run:           q.Get("run"),
+		Model:         q.Get("model"),
+		Version:       q.Get("version"),
+		ServerVersion: q.Get("server_version"),
+	}
+	var err error
+	if v := q.Get("batch"); v != "" {
+		if f.BatchSize, err = strconv.Atoi(v); err != nil {
+			return f, fmt.Errorf("store: invalid batch size %q", v)
+		}
+	}
+	if v := q.Get("since"); v != "" {
+		if f.Since, err = ParseTime(v); err != nil {
+			return f, err
+		}
+	}
+	if v := q.Get("until"); v != "" {
+		if f.Until, err = ParseTime(v); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+var entryHeader = []string{
+	"id", "run", "started_at", "finished_at", "server_version", "model", "model_version", "batch_size", "concurrency", "load_mode",
+	"count", "errors", "error_rate", "throughput", "mean_ms", "p50_ms", "p90_ms", "p95_ms", "p99_ms", "p999_ms", "max_ms",
+}
+
+// WriteCSV 실행마다 실행 조건과 전체 요약을 한 줄씩 CSV 로 쓴다. 레이턴시는 ms 단위다.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	cw.Write(entryHeader)
+	for _, e := range entries {
+		m, s := e.Metadata, e.Total
+		record := []string{
+			e.ID, m.RunName, m.StartedAt.Format(time.RFC3339), m.FinishedAt.Format(time.RFC3339),
+			m.ServerVersion, m.ModelName, m.ModelVersion, strconv.Itoa(m.BatchSize), strconv.Itoa(m.Concurrency), m.LoadMode,
+			strconv.FormatInt(s.Count, 10), strconv.FormatInt(s.Errors, 10),
+			strconv.FormatFloat(s.ErrorRate(), 'f', 4, 64), strconv.FormatFloat(s.Throughput, 'f', 3, 64),
+		}
+		for _, d := range []time.Duration{s.Mean, s.P50, s.P90, s.P95, s.P99, s.P999, s.Max} {
+			record = append(record, strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64))
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,217 @@
+// Package store 는 끝난 부하 테스트 보고서를 로컬 bbolt 데이터베이스에 보관하고 조건으로 찾는다.
+//
+// 보고서는 히스토그램을 포함한 JSON (report.Report) 그대로 저장하므로 나중에 꺼내서
+// report.Compare 로 비교하거나 다시 내보낼 수 있다. 키는 시작 시각으로 시작하므로 시간순으로 읽힌다.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+)
+
+var (
+	// reportsBucket ID → 보고서 JSON
+	reportsBucket = []byte("reports")
+	// summariesBucket ID → Entry JSON. 목록을 볼 때 히스토그램을 읽지 않도록 따로 둔다.
+	summariesBucket = []byte("summaries")
+)
+
+// ErrNotFound Get 이나 Delete 할 ID 가 없다.
+var ErrNotFound = errors.New("store: run not found")
+
+// Store 보고서 데이터베이스. 여러 고루틴에서 함께 써도 된다.
+//
+// 파일은 연 동안 잠기므로 쓰는 쪽 (aggregator) 은 저장할 때만 열고 닫는다.
+// 읽기만 하는 쪽은 OpenReadOnly 로 열면 서로 기다리지 않는다.
+type Store struct {
+	db *bolt.DB
+}
+
+// Entry 목록에 보여 줄 실행 하나. Total 에는 히스토그램이 없다.
+type Entry struct {
+	ID       string          `json:"id"`
+	Metadata report.Metadata `json:"metadata"`
+	Total    report.Summary  `json:"total"`
+}
+
+// Filter 실행을 고르는 조건. 비어 있는 항목은 보지 않는다.
+type Filter struct {
+	Run           string
+	Model         string
+	Version       string
+	ServerVersion string
+	BatchSize     int
+	// Since, Until 실행 시작 시각의 범위. Until 은 포함하지 않는다.
+	Since time.Time
+	Until time.Time
+}
+
+// Match m 이 조건에 맞는지
+func (f Filter) Match(m report.Metadata) bool {
+	switch {
+	case f.Run != "" && m.RunName != f.Run:
+		return false
+	case f.Model != "" && m.ModelName != f.Model:
+		return false
+	case f.Version != "" && m.ModelVersion != f.Version:
+		return false
+	case f.ServerVersion != "" && m.ServerVersion != f.ServerVersion:
+		return false
+	case f.BatchSize != 0 && m.BatchSize != f.BatchSize:
+		return false
+	case !f.Since.IsZero() && m.StartedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !m.StartedAt.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Open path 의 데이터베이스를 연다. 없으면 만든다. 다른 프로세스가 열고 있으면 timeout 만큼 기다린다.
+func Open(path string, timeout time.Duration) (*Store, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: timeout})
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{reportsBucket, summariesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// OpenReadOnly 이미 있는 path 의 데이터베이스를 읽기 전용으로 연다. 쓰는 쪽이 열고 있으면 timeout 만큼 기다린다.
+// 파일이 없으면 os.ErrNotExist 를 감싼 에러를 돌려준다. bbolt 는 읽기 전용이어도 없는 파일을 만들므로 먼저 확인한다.
+func OpenReadOnly(path string, timeout time.Duration) (*Store, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	db, err := bolt.Open(path, 0o444, &bolt.Options{Timeout: timeout, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	err = db.View(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{reportsBucket, summariesBucket} {
+			if tx.Bucket(name) == nil {
+				return fmt.Errorf("%s is not a results database", path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close 데이터베이스를 닫는다.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save r 을 저장하고 ID 를 돌려준다. ID 는 "20241001T090000Z-<실행 이름>" 꼴이고 겹치면 뒤에 번호를 붙인다.
+func (s *Store) Save(r *report.Report) (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("store: %w", err)
+	}
+	entry := Entry{Metadata: r.Metadata, Total: r.Total}
+	entry.Total.Histogram = nil
+
+	name := r.Metadata.RunName
+	if name == "" {
+		name = "run"
+	}
+	base := r.Metadata.StartedAt.UTC().Format("20060102T150405Z") + "-" + name
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		reports := tx.Bucket(reportsBucket)
+		entry.ID = base
+		for i := 2; reports.Get([]byte(entry.ID)) != nil; i++ {
+			entry.ID = fmt.Sprintf("%s-%d", base, i)
+		}
+		summary, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if err := reports.Put([]byte(entry.ID), data); err != nil {
+			return err
+		}
+		return tx.Bucket(summariesBucket).Put([]byte(entry.ID), summary)
+	})
+	if err != nil {
+		return "", fmt.Errorf("store: save: %w", err)
+	}
+	return entry.ID, nil
+}
+
+// Get id 의 보고서
+func (s *Store) Get(id string) (*report.Report, error) {
+	var r report.Report
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(reportsBucket).Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return json.Unmarshal(data, &r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// List 조건에 맞는 실행을 오래된 것부터 돌려준다.
+func (s *Store) List(f Filter) ([]Entry, error) {
+	var entries []Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(summariesBucket).Cursor()
+		k, v := c.First()
+		// 키가 초 단위 시작 시각으로 시작하므로 Since 가 있으면 그 초부터 읽는다.
+		if !f.Since.IsZero() {
+			k, v = c.Seek([]byte(f.Since.UTC().Format("20060102T150405Z")))
+		}
+		for ; k != nil; k, v = c.Next() {
+			var e Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			if f.Match(e.Metadata) {
+				entries = append(entries, e)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store: list: %w", err)
+	}
+	return entries, nil
+}
+
+// Delete id 의 실행을 지운다.
+func (s *Store) Delete(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(reportsBucket).Get([]byte(id)) == nil {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		if err := tx.Bucket(reportsBucket).Delete([]byte(id)); err != nil {
+			return err
+		}
+		return tx.Bucket(summariesBucket).Delete([]byte(id))
+	})
+}
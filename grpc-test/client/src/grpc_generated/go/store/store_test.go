@@ -0,0 +1,142 @@
+package store
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/histogram"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/report"
+)
+
+func testReport(started time.Time, version string, batch int) *report.Report {
+	h := histogram.NewLatency()
+	for i := 1; i <= 100; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	return &report.Report{
+		Metadata: report.Metadata{
+			RunName:       "nightly",
+			StartedAt:     started,
+			FinishedAt:    started.Add(10 * time.Second),
+			ServerVersion: "2.46.0",
+			ModelName:     "vitpose_ensemble",
+			ModelVersion:  version,
+			BatchSize:     batch,
+		},
+		Total: report.NewSummary("ALL", h, 10*time.Second),
+	}
+}
+
+func TestSaveListGet(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "results.db"), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	day := time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC)
+	var ids []string
+	for _, r := range []*report.Report{
+		testReport(day.Add(48*time.Hour), "2", 4),
+		testReport(day, "1", 4),
+		testReport(day.Add(24*time.Hour), "1", 8),
+		testReport(day, "1", 4), // 같은 시각, 같은 이름
+	} {
+		id, err := s.Save(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if ids[1] != "20241001T090000Z-nightly" || ids[3] != "20241001T090000Z-nightly-2" {
+		t.Errorf("ids = %v", ids)
+	}
+
+	all, err := s.List(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[0].ID != ids[1] || all[3].ID != ids[0] || all[0].Total.Histogram != nil || all[0].Total.Count != 100 {
+		t.Errorf("List() = %+v, want 4 entries oldest first without histograms", all)
+	}
+	for _, c := range []struct {
+		filter Filter
+		want   int
+	}{
+		{Filter{Version: "1"}, 3},
+		{Filter{BatchSize: 8}, 1},
+		{Filter{Since: day.Add(time.Hour)}, 2},
+		{Filter{Until: day.Add(24 * time.Hour)}, 2},
+		{Filter{Model: "resnet"}, 0},
+		{Filter{ServerVersion: "2.46.0", Version: "2"}, 1},
+	} {
+		got, err := s.List(c.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != c.want {
+			t.Errorf("List(%+v) returned %d entries, want %d", c.filter, len(got), c.want)
+		}
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.db")
+	ro, err := OpenReadOnly(missing, time.Second)
+	if err == nil {
+		ro.Close()
+		t.Error("OpenReadOnly created a missing database")
+	} else if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenReadOnly(missing) error = %v, want os.ErrNotExist", err)
+	}
+	if _, err := os.Stat(missing); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenReadOnly left a file behind: %v", err)
+	}
+
+	r, err := s.Get(ids[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Metadata.BatchSize != 8 || len(r.Total.Histogram) == 0 {
+		t.Errorf("Get = %+v, want the batch 8 report with its histogram", r.Metadata)
+	}
+	if err := s.Delete(ids[2]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ids[2]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: %v, want ErrNotFound", err)
+	}
+}
+
+func TestFilterFromQuery(t *testing.T) {
+	q, _ := url.ParseQuery("model=vitpose&batch=4&since=2024-10-01&until=2024-10-02T00:00:00Z")
+	f, err := FilterFromQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Model != "vitpose" || f.BatchSize != 4 || !f.Since.Equal(time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)) || f.Until.Day() != 2 {
+		t.Errorf("filter = %+v", f)
+	}
+	if _, err := FilterFromQuery(url.Values{"since": {"yesterday"}}); err == nil {
+		t.Error("FilterFromQuery accepted since=yesterday")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	r := testReport(time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC), "1", 4)
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, []Entry{{ID: "a", Metadata: r.Metadata, Total: r.Total}}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1][0] != "a" || records[1][5] != "vitpose_ensemble" || records[1][10] != "100" {
+		t.Errorf("csv = %v", records)
+	}
+}
@@ -0,0 +1,90 @@
+package tritontest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+// LoadModelConfig config.pbtxt 를 읽는다. name 이 비어 있으면 Triton 처럼 디렉터리 이름을 쓴다.
+func LoadModelConfig(path string) (*triton.ModelConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &triton.ModelConfig{}
+	if err := prototext.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("tritontest: parse %s: %w", path, err)
+	}
+	if config.Name == "" {
+		config.Name = filepath.Base(filepath.Dir(path))
+	}
+	return config, nil
+}
+
+// LoadModelRepository dir 아래 <model>/config.pbtxt 를 모두 등록한다 (예: pose_model_zoo).
+// 숫자 이름의 하위 디렉터리를 버전으로 보고, 없으면 "1" 버전을 쓴다.
+func (s *Server) LoadModelRepository(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		modelDir := filepath.Join(dir, entry.Name())
+		config, err := LoadModelConfig(filepath.Join(modelDir, "config.pbtxt"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		s.AddModel(config, modelVersions(modelDir)...)
+	}
+	return nil
+}
+
+// modelVersions 모델 디렉터리 안의 숫자 이름 하위 디렉터리
+func modelVersions(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); entry.IsDir() && err == nil {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions
+}
+
+// datatype config 의 DataType 을 추론 프로토콜의 datatype 문자열로 바꾼다 (TYPE_FP32 -> FP32).
+func datatype(dt triton.DataType) string {
+	if dt == triton.DataType_TYPE_STRING {
+		return "BYTES"
+	}
+	return strings.TrimPrefix(dt.String(), "TYPE_")
+}
+
+// elementSize datatype 한 원소의 바이트 수. BYTES 처럼 가변 길이면 0 이다.
+func elementSize(dt string) int {
+	switch dt {
+	case "BOOL", "INT8", "UINT8":
+		return 1
+	case "INT16", "UINT16", "FP16", "BF16":
+		return 2
+	case "INT32", "UINT32", "FP32":
+		return 4
+	case "INT64", "UINT64", "FP64":
+		return 8
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+package tritontest
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+)
+
+// healthServiceDesc triton.Health_ServiceDesc 에 Watch 를 더한 것. Triton 의 health.proto 에는 Check 만 있다.
+var healthServiceDesc = grpc.ServiceDesc{
+	ServiceName: triton.Health_ServiceDesc.ServiceName,
+	HandlerType: triton.Health_ServiceDesc.HandlerType,
+	Methods:     triton.Health_ServiceDesc.Methods,
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Watch",
+		ServerStreams: true,
+		Handler: func(srv any, stream grpc.ServerStream) error {
+			req := new(triton.HealthCheckRequest)
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			return srv.(*Server).watchHealth(req, stream)
+		},
+	}},
+	Metadata: triton.Health_ServiceDesc.Metadata,
+}
+
+// SetHealthWatch grpc.health.v1 Watch 를 지원할지 정한다. 기본은 Triton 처럼 Unimplemented 로 실패한다.
+func (s *Server) SetHealthWatch(enabled bool) {
+	s.mu.Lock()
+	s.watch = enabled
+	s.mu.Unlock()
+}
+
+// notifyLocked live/ready 가 바뀌었음을 Watch 에 알린다. s.mu 를 잡은 채로 부른다.
+func (s *Server) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// servingLocked Check 와 Watch 가 돌려줄 상태
+func (s *Server) servingLocked() triton.HealthCheckResponse_ServingStatus {
+	if s.live && s.ready {
+		return triton.HealthCheckResponse_SERVING
+	}
+	return triton.HealthCheckResponse_NOT_SERVING
+}
+
+// Check implements triton.HealthServer.
+func (s *Server) Check(context.Context, *triton.HealthCheckRequest) (*triton.HealthCheckResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &triton.HealthCheckResponse{Status: s.servingLocked()}, nil
+}
+
+// watchHealth 지금 상태를 보내고, 바뀔 때마다 다시 보낸다.
+func (s *Server) watchHealth(_ *triton.HealthCheckRequest, stream grpc.ServerStream) error {
+	sent := triton.HealthCheckResponse_ServingStatus(-1)
+	for {
+		s.mu.Lock()
+		watch, current, changed := s.watch, s.servingLocked(), s.changed
+		s.mu.Unlock()
+		if !watch {
+			return status.Error(codes.Unimplemented, "method Watch not implemented")
+		}
+		if current != sent {
+			if err := stream.SendMsg(&triton.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			sent = current
+		}
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
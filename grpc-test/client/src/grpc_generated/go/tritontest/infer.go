@@ -0,0 +1,266 @@
+package tritontest
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OutputFunc model 의 output 출력 텐서를 shape 에 맞춰 만든다. 반환 길이는 shape 원소 수와 같아야 한다.
+type OutputFunc func(model, output string, shape []int64) []float32
+
+// SetOutputFunc FP32 출력 텐서를 만드는 함수를 바꾼다. nil 이면 SyntheticOutput 을 쓴다.
+func (s *Server) SetOutputFunc(fn OutputFunc) {
+	s.mu.Lock()
+	s.outputFunc = fn
+	s.mu.Unlock()
+}
+
+// VersionBehavior 모델의 한 버전에만 적용할 동작. 새 엔진 버전이 기존 버전과 다르게 동작하는 경우를 흉내 낸다.
+type VersionBehavior struct {
+	// Latency 0 이 아니면 SetLatency 의 latency 대신 쓴다. jitter 는 그대로 더한다.
+	Latency time.Duration
+	// Output nil 이 아니면 SetOutputFunc 의 함수 대신 쓴다.
+	Output OutputFunc
+}
+
+// SetVersionBehavior model 의 version 에 대한 요청에 b 를 적용한다. 빈 VersionBehavior 면 원래대로 돌린다.
+func (s *Server) SetVersionBehavior(model, version string, b VersionBehavior) {
+	s.mu.Lock()
+	s.behaviors[model+":"+version] = b
+	s.mu.Unlock()
+}
+
+// SyntheticOutput 기본 출력 생성 함수.
+//   - [N, K, 3]: 관절 k 를 (48+6k, 64+8k), 신뢰도 0.9 로 둔 키포인트 (post_output)
+//   - [N, K, H, W]: (W/2, H/2) 에 최댓값 0.9, sigma 2 인 Gaussian 히트맵 (vitpose output)
+//   - 그 외: 0
+func SyntheticOutput(_, _ string, shape []int64) []float32 {
+	data := make([]float32, numElements(shape))
+	switch {
+	case len(shape) == 3 && shape[2] == 3:
+		for i := 0; i < len(data)/3; i++ {
+			k := i % int(shape[1])
+			data[3*i] = float32(48 + 6*k)
+			data[3*i+1] = float32(64 + 8*k)
+			data[3*i+2] = 0.9
+		}
+	case len(shape) == 4:
+		h, w := int(shape[2]), int(shape[3])
+		cx, cy := float64(w/2), float64(h/2)
+		for i := range data {
+			x, y := float64(i%w), float64(i/w%h)
+			data[i] = float32(0.9 * math.Exp(-((x-cx)*(x-cx)+(y-cy)*(y-cy))/8))
+		}
+	}
+	return data
+}
+
+// ModelInfer implements triton.GRPCInferenceServiceServer.
+// 요청을 config 에 맞춰 검사한 뒤 설정된 지연 시간만큼 기다리고 합성 출력을 돌려준다.
+func (s *Server) ModelInfer(ctx context.Context, req *triton.ModelInferRequest) (response *triton.ModelInferResponse, err error) {
+	s.mu.Lock()
+	s.requests++
+	m := s.models[req.ModelName]
+	version := req.ModelVersion
+	if m != nil && version == "" {
+		version = m.latestReady()
+	}
+	modelReady := m != nil && m.versions[version]
+	behavior := s.behaviors[req.ModelName+":"+version]
+	delay := s.latency
+	if behavior.Latency > 0 {
+		delay = behavior.Latency
+	}
+	if s.jitter > 0 {
+		delay += time.Duration(s.rand.Int63n(int64(s.jitter)))
+	}
+	inject := s.errorRate > 0 && s.rand.Float64() < s.errorRate
+	code := s.errorCode
+	outputFunc := s.outputFunc
+	if behavior.Output != nil {
+		outputFunc = behavior.Output
+	}
+	ready := s.live && s.ready
+	s.mu.Unlock()
+
+	defer func() {
+		if err != nil {
+			s.mu.Lock()
+			s.failures++
+			s.mu.Unlock()
+		}
+	}()
+
+	if !ready {
+		return nil, status.Error(codes.Unavailable, "Server not ready")
+	}
+	if m == nil {
+		return nil, status.Errorf(codes.NotFound, "Request for unknown model: '%s' is not found", req.ModelName)
+	}
+	if !modelReady {
+		return nil, status.Errorf(codes.Unavailable, "Request for unknown model: '%s' version %s is not at ready state", req.ModelName, req.ModelVersion)
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if inject {
+		return nil, status.Error(code, "tritontest: injected error")
+	}
+
+	batchSize, err := validateInputs(m.config, req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if outputFunc == nil {
+		outputFunc = SyntheticOutput
+	}
+	return buildResponse(m.config, req, version, batchSize, outputFunc)
+}
+
+// validateInputs 요청 입력의 이름, datatype, shape, raw 크기를 config 와 비교하고 배치 크기를 반환한다.
+func validateInputs(config *triton.ModelConfig, req *triton.ModelInferRequest) (int64, error) {
+	inputs := make(map[string]*triton.ModelInput, len(config.Input))
+	for _, input := range config.Input {
+		inputs[input.Name] = input
+	}
+	if len(req.RawInputContents) > 0 && len(req.RawInputContents) != len(req.Inputs) {
+		return 0, fmt.Errorf("expected %d raw input contents, got %d", len(req.Inputs), len(req.RawInputContents))
+	}
+
+	batchSize := int64(-1)
+	seen := make(map[string]bool)
+	for i, tensor := range req.Inputs {
+		input, ok := inputs[tensor.Name]
+		if !ok {
+			return 0, fmt.Errorf("unexpected inference input '%s' for model '%s'", tensor.Name, config.Name)
+		}
+		seen[tensor.Name] = true
+		if want := datatype(input.DataType); tensor.Datatype != want {
+			return 0, fmt.Errorf("inference input '%s' data-type is '%s', but model '%s' expects '%s'",
+				tensor.Name, tensor.Datatype, config.Name, want)
+		}
+		dims := expectedDims(config, input.Dims)
+		if !shapeMatches(tensor.Shape, dims) {
+			return 0, fmt.Errorf("unexpected shape for input '%s' for model '%s'. Expected %v, got %v",
+				tensor.Name, config.Name, dims, tensor.Shape)
+		}
+		if len(dims) > 0 && dims[0] == -1 {
+			if batchSize >= 0 && tensor.Shape[0] != batchSize {
+				return 0, fmt.Errorf("input '%s' has batch size %d, but other inputs have %d", tensor.Name, tensor.Shape[0], batchSize)
+			}
+			batchSize = tensor.Shape[0]
+		}
+		if len(req.RawInputContents) > 0 {
+			size := elementSize(tensor.Datatype)
+			if want := numElements(tensor.Shape) * size; size > 0 && len(req.RawInputContents[i]) != want {
+				return 0, fmt.Errorf("input '%s' got %d bytes, expected %d for shape %v",
+					tensor.Name, len(req.RawInputContents[i]), want, tensor.Shape)
+			}
+		}
+	}
+	for _, input := range config.Input {
+		if !input.Optional && !seen[input.Name] {
+			return 0, fmt.Errorf("expected input '%s' for model '%s' is missing", input.Name, config.Name)
+		}
+	}
+	if batchSize < 0 {
+		batchSize = 1
+	}
+	return batchSize, nil
+}
+
+// buildResponse 요청한 출력(없으면 config 의 모든 출력)을 outputFunc 로 채운 응답을 만든다.
+func buildResponse(config *triton.ModelConfig, req *triton.ModelInferRequest, version string, batchSize int64, outputFunc OutputFunc) (*triton.ModelInferResponse, error) {
+	outputs := make(map[string]*triton.ModelOutput, len(config.Output))
+	for _, output := range config.Output {
+		outputs[output.Name] = output
+	}
+	var names []string
+	for _, requested := range req.Outputs {
+		if _, ok := outputs[requested.Name]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unexpected inference output '%s' for model '%s'", requested.Name, config.Name)
+		}
+		names = append(names, requested.Name)
+	}
+	if len(names) == 0 {
+		for _, output := range config.Output {
+			names = append(names, output.Name)
+		}
+	}
+
+	response := &triton.ModelInferResponse{ModelName: config.Name, ModelVersion: version, Id: req.Id}
+	for _, name := range names {
+		output := outputs[name]
+		dims := expectedDims(config, output.Dims)
+		shape := make([]int64, len(dims))
+		for i, d := range dims {
+			switch {
+			case d >= 0:
+				shape[i] = d
+			case i == 0:
+				shape[i] = batchSize
+			default:
+				shape[i] = 1
+			}
+		}
+		dt := datatype(output.DataType)
+		if dt != "FP32" {
+			return nil, status.Errorf(codes.Unimplemented, "tritontest: output '%s' has unsupported datatype %s", name, dt)
+		}
+		data := outputFunc(config.Name, name, shape)
+		if len(data) != numElements(shape) {
+			return nil, status.Errorf(codes.Internal, "tritontest: output '%s' got %d values for shape %v", name, len(data), shape)
+		}
+		raw := make([]byte, 4*len(data))
+		for i, v := range data {
+			binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(v))
+		}
+		response.Outputs = append(response.Outputs, &triton.ModelInferResponse_InferOutputTensor{
+			Name: name, Datatype: dt, Shape: shape,
+		})
+		response.RawOutputContents = append(response.RawOutputContents, raw)
+	}
+	return response, nil
+}
+
+// expectedDims max_batch_size 가 있으면 배치 축(-1)을 앞에 붙인 dims
+func expectedDims(config *triton.ModelConfig, dims []int64) []int64 {
+	if config.MaxBatchSize > 0 {
+		return append([]int64{-1}, dims...)
+	}
+	return dims
+}
+
+func shapeMatches(shape, dims []int64) bool {
+	if len(shape) != len(dims) {
+		return false
+	}
+	for i, d := range dims {
+		if shape[i] < 0 || (d >= 0 && shape[i] != d) {
+			return false
+		}
+	}
+	return true
+}
+
+func numElements(shape []int64) int {
+	n := 1
+	for _, d := range shape {
+		n *= int(d)
+	}
+	return n
+}
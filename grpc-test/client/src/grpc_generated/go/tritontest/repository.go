@@ -0,0 +1,139 @@
+package tritontest
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+)
+
+// AddRepositoryModel 모델을 저장소에만 등록한다. --model-control-mode=explicit 인 Triton 처럼 RepositoryModelLoad 로 올려야 쓸 수 있다.
+func (s *Server) AddRepositoryModel(config *triton.ModelConfig, versions ...string) {
+	s.mu.Lock()
+	s.repository[config.Name] = newModel(config, versions)
+	s.mu.Unlock()
+}
+
+func (m *model) clone() *model {
+	c := &model{config: m.config, versions: make(map[string]bool, len(m.versions))}
+	for version, ready := range m.versions {
+		c.versions[version] = ready
+	}
+	return c
+}
+
+// RepositoryIndex implements triton.GRPCInferenceServiceServer. 올라간 모델은 버전마다, 내려간 모델은 버전 없이 한 줄이다.
+func (s *Server) RepositoryIndex(_ context.Context, req *triton.RepositoryIndexRequest) (*triton.RepositoryIndexResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.repository))
+	for name := range s.repository {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	response := &triton.RepositoryIndexResponse{}
+	for _, name := range names {
+		m, ok := s.models[name]
+		if !ok {
+			if !req.Ready {
+				response.Models = append(response.Models, &triton.RepositoryIndexResponse_ModelIndex{Name: name, State: "UNAVAILABLE", Reason: "unloaded"})
+			}
+			continue
+		}
+		for _, version := range m.sortedVersions() {
+			state := "READY"
+			if !m.versions[version] {
+				state = "UNAVAILABLE"
+			}
+			if req.Ready && state != "READY" {
+				continue
+			}
+			response.Models = append(response.Models, &triton.RepositoryIndexResponse_ModelIndex{Name: name, Version: version, State: state})
+		}
+	}
+	return response, nil
+}
+
+// RepositoryModelLoad implements triton.GRPCInferenceServiceServer. "config" 파라미터가 있으면 저장소의 설정 대신 쓰고,
+// Triton 처럼 version_policy 대로 버전을 올린다 (specific 은 그 버전, all 은 모두, 없거나 latest 면 최신 num_versions 개).
+// 앙상블은 단계 모델이 모두 올라가 있어야 올릴 수 있다.
+func (s *Server) RepositoryModelLoad(_ context.Context, req *triton.RepositoryModelLoadRequest) (*triton.RepositoryModelLoadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.repository[req.ModelName]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to load '%s', failed to poll from model repository", req.ModelName)
+	}
+	config := stored.config
+	if p, ok := req.Parameters["config"]; ok {
+		config = &triton.ModelConfig{}
+		if err := protojson.Unmarshal([]byte(p.GetStringParam()), config); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to load '%s', failed to parse config override: %v", req.ModelName, err)
+		}
+		if config.Name == "" {
+			config.Name = req.ModelName
+		}
+		if config.Name != req.ModelName {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to load '%s', config override has name '%s'", req.ModelName, config.Name)
+		}
+	}
+
+	m := &model{config: config, versions: make(map[string]bool)}
+	policy := config.GetVersionPolicy()
+	switch {
+	case policy.GetSpecific() != nil:
+		for _, v := range policy.GetSpecific().Versions {
+			version := strconv.FormatInt(v, 10)
+			if _, ok := stored.versions[version]; !ok {
+				return nil, status.Errorf(codes.InvalidArgument, "failed to load '%s' version %s: not found in model repository", req.ModelName, version)
+			}
+			m.versions[version] = true
+		}
+	case policy.GetAll() != nil:
+		m.versions = stored.clone().versions
+	default:
+		// Triton 의 기본 정책은 latest { num_versions: 1 } 이다.
+		n := int(policy.GetLatest().GetNumVersions())
+		if n < 1 {
+			n = 1
+		}
+		versions := stored.sortedVersions()
+		for _, version := range versions[max(0, len(versions)-n):] {
+			m.versions[version] = true
+		}
+	}
+
+	for _, step := range config.GetEnsembleScheduling().GetStep() {
+		dep, ok := s.models[step.ModelName]
+		ready := ok && dep.latestReady() != ""
+		if ok && step.ModelVersion > 0 {
+			ready = dep.versions[strconv.FormatInt(step.ModelVersion, 10)]
+		}
+		if !ready {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to load '%s', ensemble depends on '%s' which has no loaded version", req.ModelName, step.ModelName)
+		}
+	}
+	s.models[req.ModelName] = m
+	return &triton.RepositoryModelLoadResponse{}, nil
+}
+
+// RepositoryModelUnload implements triton.GRPCInferenceServiceServer. "unload_dependents" 가 true 면 앙상블의 단계 모델도 내린다.
+func (s *Server) RepositoryModelUnload(_ context.Context, req *triton.RepositoryModelUnloadRequest) (*triton.RepositoryModelUnloadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.repository[req.ModelName]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unload '%s', model is not in the repository", req.ModelName)
+	}
+	if m, ok := s.models[req.ModelName]; ok && req.Parameters["unload_dependents"].GetBoolParam() {
+		for _, step := range m.config.GetEnsembleScheduling().GetStep() {
+			delete(s.models, step.ModelName)
+		}
+	}
+	delete(s.models, req.ModelName)
+	return &triton.RepositoryModelUnloadResponse{}, nil
+}
@@ -0,0 +1,302 @@
+// Package tritontest 는 테스트용 가짜 Triton gRPC 서버를 제공한다.
+// GRPCInferenceService 와 Health 서비스를 프로세스 안(bufconn) 또는 localhost 에 띄우고,
+// config.pbtxt 로 입력 shape 을 검사하며, 지연 시간·에러율·준비 상태를 조절할 수 있다.
+package tritontest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 16 << 20
+
+// model 로드된 모델 설정과 버전별 준비 상태
+type model struct {
+	config   *triton.ModelConfig
+	versions map[string]bool
+}
+
+// latestReady 준비된 버전 중 가장 높은 버전. 없으면 빈 문자열이다.
+func (m *model) latestReady() string {
+	latest, latestNum := "", -1
+	for version, ready := range m.versions {
+		if !ready {
+			continue
+		}
+		if n, err := strconv.Atoi(version); err == nil && n > latestNum {
+			latest, latestNum = version, n
+		}
+	}
+	return latest
+}
+
+// sortedVersions 숫자 순으로 정렬한 버전 목록
+func (m *model) sortedVersions() []string {
+	versions := make([]string, 0, len(m.versions))
+	for version := range m.versions {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		a, _ := strconv.Atoi(versions[i])
+		b, _ := strconv.Atoi(versions[j])
+		return a < b
+	})
+	return versions
+}
+
+// Server 가짜 Triton 서버. 필드는 mu 로 보호되며 테스트 도중에도 설정을 바꿀 수 있다.
+type Server struct {
+	triton.UnimplementedGRPCInferenceServiceServer
+	triton.UnimplementedHealthServer
+
+	mu     sync.Mutex
+	models map[string]*model
+	// repository 올리거나 내릴 수 있는 모델. RepositoryModelLoad 가 여기서 설정과 버전을 가져온다.
+	repository map[string]*model
+	live       bool
+	ready      bool
+	// watch 가 false 면 Health Watch 가 Unimplemented 로 실패한다 (Triton 과 같다).
+	watch bool
+	// changed live/ready 가 바뀌면 닫고 새로 만든다. Watch 가 기다린다.
+	changed   chan struct{}
+	latency   time.Duration
+	jitter    time.Duration
+	errorRate float64
+	errorCode codes.Code
+	rand      *rand.Rand
+	requests  int
+	failures  int
+
+	outputFunc OutputFunc
+	// behaviors "model:version" 별로 지연 시간과 출력을 바꾼다.
+	behaviors map[string]VersionBehavior
+
+	grpcServer *grpc.Server
+	listener   net.Listener
+	bufconn    *bufconn.Listener
+}
+
+// NewServer 모델이 없고 live/ready 상태인 서버를 만든다. Start 또는 StartTCP 로 띄운다.
+func NewServer() *Server {
+	return &Server{
+		models:     make(map[string]*model),
+		repository: make(map[string]*model),
+		behaviors:  make(map[string]VersionBehavior),
+		live:       true,
+		ready:      true,
+		errorCode:  codes.Internal,
+		rand:       rand.New(rand.NewSource(1)),
+		changed:    make(chan struct{}),
+	}
+}
+
+// Start bufconn 위에서 서버를 띄운다. Dial 로 연결한다.
+func (s *Server) Start() {
+	s.bufconn = bufconn.Listen(bufSize)
+	s.serve(s.bufconn)
+}
+
+// StartTCP addr (예: "127.0.0.1:0") 에서 서버를 띄우고 실제 주소를 반환한다.
+func (s *Server) StartTCP(addr string) (string, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	s.serve(lis)
+	return lis.Addr().String(), nil
+}
+
+func (s *Server) serve(lis net.Listener) {
+	s.listener = lis
+	s.grpcServer = grpc.NewServer()
+	triton.RegisterGRPCInferenceServiceServer(s.grpcServer, s)
+	s.grpcServer.RegisterService(&healthServiceDesc, s)
+	go s.grpcServer.Serve(lis)
+}
+
+// Dial 서버에 연결한 클라이언트 커넥션을 만든다.
+func (s *Server) Dial() (*grpc.ClientConn, error) {
+	if s.bufconn == nil {
+		return grpc.NewClient(s.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	return grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.bufconn.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+}
+
+// Close 서버를 멈춘다.
+func (s *Server) Close() {
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+}
+
+// AddModel 설정으로 모델을 저장소에 등록하고 올린다. versions 가 없으면 "1" 버전 하나를 준비 상태로 둔다.
+func (s *Server) AddModel(config *triton.ModelConfig, versions ...string) {
+	m := newModel(config, versions)
+	s.mu.Lock()
+	s.repository[config.Name] = m
+	s.models[config.Name] = m.clone()
+	s.mu.Unlock()
+}
+
+func newModel(config *triton.ModelConfig, versions []string) *model {
+	if len(versions) == 0 {
+		versions = []string{"1"}
+	}
+	m := &model{config: config, versions: make(map[string]bool)}
+	for _, version := range versions {
+		m.versions[version] = true
+	}
+	return m
+}
+
+// SetLive ServerLive 응답을 정한다.
+func (s *Server) SetLive(live bool) {
+	s.mu.Lock()
+	s.live = live
+	s.notifyLocked()
+	s.mu.Unlock()
+}
+
+// SetReady ServerReady 와 Health Check 응답을 정한다.
+func (s *Server) SetReady(ready bool) {
+	s.mu.Lock()
+	s.ready = ready
+	s.notifyLocked()
+	s.mu.Unlock()
+}
+
+// SetModelReady 모델의 모든 버전 (version 이 주어지면 해당 버전만) 준비 상태를 정한다.
+func (s *Server) SetModelReady(name string, ready bool, version ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.models[name]
+	if !ok {
+		return fmt.Errorf("tritontest: unknown model %q", name)
+	}
+	if len(version) == 0 {
+		for v := range m.versions {
+			m.versions[v] = ready
+		}
+		return nil
+	}
+	for _, v := range version {
+		m.versions[v] = ready
+	}
+	return nil
+}
+
+// SetLatency ModelInfer 가 응답하기 전 기다릴 시간. [latency, latency+jitter) 에서 균등하게 뽑는다.
+func (s *Server) SetLatency(latency, jitter time.Duration) {
+	s.mu.Lock()
+	s.latency, s.jitter = latency, jitter
+	s.mu.Unlock()
+}
+
+// SetErrorRate ModelInfer 요청 중 rate 비율을 code 에러로 실패시킨다.
+func (s *Server) SetErrorRate(rate float64, code codes.Code) {
+	s.mu.Lock()
+	s.errorRate, s.errorCode = rate, code
+	s.mu.Unlock()
+}
+
+// Requests 지금까지 받은 ModelInfer 요청 수와 그중 실패로 응답한 수
+func (s *Server) Requests() (total, failed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests, s.failures
+}
+
+// ServerLive implements triton.GRPCInferenceServiceServer.
+func (s *Server) ServerLive(context.Context, *triton.ServerLiveRequest) (*triton.ServerLiveResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &triton.ServerLiveResponse{Live: s.live}, nil
+}
+
+// ServerReady implements triton.GRPCInferenceServiceServer.
+func (s *Server) ServerReady(context.Context, *triton.ServerReadyRequest) (*triton.ServerReadyResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &triton.ServerReadyResponse{Ready: s.live && s.ready}, nil
+}
+
+// ModelReady implements triton.GRPCInferenceServiceServer.
+func (s *Server) ModelReady(_ context.Context, req *triton.ModelReadyRequest) (*triton.ModelReadyResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.models[req.Name]
+	if !ok {
+		return &triton.ModelReadyResponse{Ready: false}, nil
+	}
+	if req.Version == "" {
+		return &triton.ModelReadyResponse{Ready: m.latestReady() != ""}, nil
+	}
+	return &triton.ModelReadyResponse{Ready: m.versions[req.Version]}, nil
+}
+
+// ServerMetadata implements triton.GRPCInferenceServiceServer.
+func (s *Server) ServerMetadata(context.Context, *triton.ServerMetadataRequest) (*triton.ServerMetadataResponse, error) {
+	return &triton.ServerMetadataResponse{
+		Name:       "tritontest",
+		Version:    "2.46.0",
+		Extensions: []string{"classification", "model_repository", "model_configuration", "statistics"},
+	}, nil
+}
+
+// ModelMetadata implements triton.GRPCInferenceServiceServer.
+func (s *Server) ModelMetadata(_ context.Context, req *triton.ModelMetadataRequest) (*triton.ModelMetadataResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.models[req.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Request for unknown model: '%s' is not found", req.Name)
+	}
+	response := &triton.ModelMetadataResponse{
+		Name:     m.config.Name,
+		Versions: m.sortedVersions(),
+		Platform: m.config.Platform,
+	}
+	if response.Platform == "" {
+		response.Platform = m.config.Backend
+	}
+	for _, input := range m.config.Input {
+		response.Inputs = append(response.Inputs, &triton.ModelMetadataResponse_TensorMetadata{
+			Name: input.Name, Datatype: datatype(input.DataType), Shape: input.Dims,
+		})
+	}
+	for _, output := range m.config.Output {
+		response.Outputs = append(response.Outputs, &triton.ModelMetadataResponse_TensorMetadata{
+			Name: output.Name, Datatype: datatype(output.DataType), Shape: output.Dims,
+		})
+	}
+	return response, nil
+}
+
+// ModelConfig implements triton.GRPCInferenceServiceServer.
+func (s *Server) ModelConfig(_ context.Context, req *triton.ModelConfigRequest) (*triton.ModelConfigResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.models[req.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Request for unknown model: '%s' is not found", req.Name)
+	}
+	return &triton.ModelConfigResponse{Config: m.config}, nil
+}
@@ -0,0 +1,210 @@
+package tritontest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const modelRepository = "../../../../../../pose_model_zoo"
+
+func startServer(t *testing.T) (*Server, triton.GRPCInferenceServiceClient) {
+	t.Helper()
+	server := NewServer()
+	if err := server.LoadModelRepository(modelRepository); err != nil {
+		t.Fatal(err)
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return server, triton.NewGRPCInferenceServiceClient(conn)
+}
+
+func inferRequest(model string, batchSize int64) *triton.ModelInferRequest {
+	return &triton.ModelInferRequest{
+		ModelName: model,
+		Inputs: []*triton.ModelInferRequest_InferInputTensor{
+			{Name: "input", Datatype: "FP32", Shape: []int64{batchSize, 3, 256, 192}},
+		},
+		RawInputContents: [][]byte{make([]byte, batchSize*3*256*192*4)},
+	}
+}
+
+func TestLoadModelRepository(t *testing.T) {
+	server, client := startServer(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"vitpose_ensemble", "vitpose", "postprocess"} {
+		ready, err := client.ModelReady(ctx, &triton.ModelReadyRequest{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ready.Ready {
+			t.Errorf("model %s is not ready", name)
+		}
+	}
+
+	if err := server.SetModelReady("vitpose", false); err != nil {
+		t.Fatal(err)
+	}
+	ready, err := client.ModelReady(ctx, &triton.ModelReadyRequest{Name: "vitpose"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready.Ready {
+		t.Error("vitpose is still ready after SetModelReady(false)")
+	}
+}
+
+func TestModelInfer(t *testing.T) {
+	_, client := startServer(t)
+
+	response, err := client.ModelInfer(context.Background(), inferRequest("vitpose_ensemble", 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Outputs) != 1 || response.Outputs[0].Name != "post_output" {
+		t.Fatalf("unexpected outputs %v", response.Outputs)
+	}
+	if got, want := response.Outputs[0].Shape, []int64{4, 17, 3}; !equalShape(got, want) {
+		t.Errorf("post_output shape = %v, want %v", got, want)
+	}
+	if got, want := len(response.RawOutputContents[0]), 4*17*3*4; got != want {
+		t.Errorf("post_output has %d bytes, want %d", got, want)
+	}
+
+	response, err = client.ModelInfer(context.Background(), inferRequest("vitpose", 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := response.Outputs[0].Shape, []int64{2, 17, 64, 48}; !equalShape(got, want) {
+		t.Errorf("output shape = %v, want %v", got, want)
+	}
+}
+
+func TestModelInferValidation(t *testing.T) {
+	_, client := startServer(t)
+
+	tests := []struct {
+		name   string
+		modify func(*triton.ModelInferRequest)
+		code   codes.Code
+	}{
+		{"unknown model", func(r *triton.ModelInferRequest) { r.ModelName = "resnet" }, codes.NotFound},
+		{"wrong shape", func(r *triton.ModelInferRequest) { r.Inputs[0].Shape = []int64{1, 3, 224, 224} }, codes.InvalidArgument},
+		{"wrong datatype", func(r *triton.ModelInferRequest) { r.Inputs[0].Datatype = "FP16" }, codes.InvalidArgument},
+		{"short raw input", func(r *triton.ModelInferRequest) { r.RawInputContents[0] = r.RawInputContents[0][:8] }, codes.InvalidArgument},
+		{"unknown input", func(r *triton.ModelInferRequest) { r.Inputs[0].Name = "image" }, codes.InvalidArgument},
+		{"center batch mismatch", func(r *triton.ModelInferRequest) {
+			r.Inputs = append(r.Inputs, &triton.ModelInferRequest_InferInputTensor{Name: "center", Datatype: "FP32", Shape: []int64{2, 2}})
+			r.RawInputContents = append(r.RawInputContents, make([]byte, 16))
+		}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := inferRequest("vitpose_ensemble", 1)
+			tt.modify(req)
+			_, err := client.ModelInfer(context.Background(), req)
+			if got := status.Code(err); got != tt.code {
+				t.Errorf("ModelInfer error = %v, want code %v", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestModelInferScalarInput(t *testing.T) {
+	// max_batch_size 가 없는 모델의 스칼라 입력은 shape 가 비어 있다.
+	server, client := startServer(t)
+	server.AddModel(&triton.ModelConfig{
+		Name:   "threshold",
+		Input:  []*triton.ModelInput{{Name: "value", DataType: triton.DataType_TYPE_FP32}},
+		Output: []*triton.ModelOutput{{Name: "result", DataType: triton.DataType_TYPE_FP32, Dims: []int64{1}}},
+	})
+	response, err := client.ModelInfer(context.Background(), &triton.ModelInferRequest{
+		ModelName:        "threshold",
+		Inputs:           []*triton.ModelInferRequest_InferInputTensor{{Name: "value", Datatype: "FP32"}},
+		RawInputContents: [][]byte{make([]byte, 4)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := response.Outputs[0].Shape, []int64{1}; !equalShape(got, want) {
+		t.Errorf("result shape = %v, want %v", got, want)
+	}
+}
+
+func TestLatencyAndErrors(t *testing.T) {
+	server, client := startServer(t)
+
+	server.SetLatency(200*time.Millisecond, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.ModelInfer(ctx, inferRequest("vitpose_ensemble", 1)); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("ModelInfer with short deadline = %v, want DeadlineExceeded", err)
+	}
+
+	server.SetLatency(0, 0)
+	server.SetErrorRate(1, codes.ResourceExhausted)
+	if _, err := client.ModelInfer(context.Background(), inferRequest("vitpose_ensemble", 1)); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("ModelInfer with error rate 1 = %v, want ResourceExhausted", err)
+	}
+
+	server.SetErrorRate(0, codes.OK)
+	server.SetReady(false)
+	if _, err := client.ModelInfer(context.Background(), inferRequest("vitpose_ensemble", 1)); status.Code(err) != codes.Unavailable {
+		t.Errorf("ModelInfer on unready server = %v, want Unavailable", err)
+	}
+
+	// 마감 시간이 지난 첫 요청은 서버에 도달하지 않을 수도 있다.
+	if total, failed := server.Requests(); total < 2 || failed != total {
+		t.Errorf("Requests() = %d, %d, want every request to fail", total, failed)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	server, _ := startServer(t)
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	health := triton.NewHealthClient(conn)
+
+	response, err := health.Check(context.Background(), &triton.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != triton.HealthCheckResponse_SERVING {
+		t.Errorf("status = %v, want SERVING", response.Status)
+	}
+
+	server.SetReady(false)
+	response, err = health.Check(context.Background(), &triton.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != triton.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status = %v, want NOT_SERVING", response.Status)
+	}
+}
+
+func equalShape(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
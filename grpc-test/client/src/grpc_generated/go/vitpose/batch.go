@@ -0,0 +1,45 @@
+package vitpose
+
+import "math/rand"
+
+// Batch "input" 텐서로 보낼 이미지 묶음. Data 는 [N, 3, 256, 192] 순서의 float32 이다.
+// Boxes 가 있으면 각 이미지가 잘려 나온 원본 이미지의 박스로, 키포인트를 원본 좌표로 돌려받는 데 쓴다.
+type Batch struct {
+	Data  []float32
+	Boxes []Box
+}
+
+// NewBatch n 장의 이미지를 담을 수 있는 빈 Batch 를 만든다.
+func NewBatch(n int) *Batch {
+	return &Batch{Data: make([]float32, n*ImageSize)}
+}
+
+// RandomBatch 부하 테스트용으로 [0, 1) 난수로 채운 Batch 를 만든다.
+func RandomBatch(n int) *Batch {
+	batch := NewBatch(n)
+	for i := range batch.Data {
+		batch.Data[i] = rand.Float32()
+	}
+	return batch
+}
+
+// Len 배치에 담긴 이미지 수
+func (b *Batch) Len() int {
+	return len(b.Data) / ImageSize
+}
+
+// Image i 번째 이미지의 CHW 데이터. 반환된 슬라이스에 쓰면 Batch 에 반영된다.
+func (b *Batch) Image(i int) []float32 {
+	return b.Data[i*ImageSize : (i+1)*ImageSize]
+}
+
+// centerScale Boxes 를 "center", "scale" 텐서용 [N, 2] float32 두 개로 펼친다.
+func (b *Batch) centerScale() (center, scale []float32) {
+	center = make([]float32, 0, 2*len(b.Boxes))
+	scale = make([]float32, 0, 2*len(b.Boxes))
+	for _, box := range b.Boxes {
+		center = append(center, box.Center[0], box.Center[1])
+		scale = append(scale, box.Scale[0], box.Scale[1])
+	}
+	return center, scale
+}
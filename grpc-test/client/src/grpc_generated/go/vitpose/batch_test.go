@@ -0,0 +1,33 @@
+package vitpose_test
+
+import (
+	"testing"
+
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+func TestBatch(t *testing.T) {
+	batch := vitpose.NewBatch(3)
+	if batch.Len() != 3 || len(batch.Data) != 3*vitpose.ImageSize {
+		t.Fatalf("NewBatch(3) has %d images in %d values", batch.Len(), len(batch.Data))
+	}
+	// Image 는 Data 의 한 구간이므로 쓰면 Batch 에 반영된다.
+	batch.Image(1)[0] = 7
+	batch.Image(2)[vitpose.ImageSize-1] = 9
+	if batch.Data[vitpose.ImageSize] != 7 || batch.Data[len(batch.Data)-1] != 9 {
+		t.Error("writes through Image are not reflected in Data")
+	}
+	if n := len(batch.Image(0)); n != vitpose.ImageSize {
+		t.Errorf("Image(0) has %d values, want %d", n, vitpose.ImageSize)
+	}
+
+	random := vitpose.RandomBatch(2)
+	if random.Len() != 2 {
+		t.Fatalf("RandomBatch(2) has %d images", random.Len())
+	}
+	for i, v := range random.Data {
+		if v < 0 || v >= 1 {
+			t.Fatalf("RandomBatch value %d = %v, want [0, 1)", i, v)
+		}
+	}
+}
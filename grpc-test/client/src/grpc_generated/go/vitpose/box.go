@@ -0,0 +1,77 @@
+package vitpose
+
+// Box 사람 바운딩 박스. postprocess/util.py 의 transform_preds 와 같은 규약을 쓴다.
+//   - Center: 박스 중심 (x, y), 원본 이미지 픽셀 단위
+//   - Scale: 박스 크기 (width, height), 원본 이미지 픽셀 단위 (200 으로 나누지 않는다)
+type Box struct {
+	Center [2]float32
+	Scale  [2]float32
+}
+
+// BoxFromXYWH 좌상단 (x, y) 와 크기 (w, h) 로 주어진 박스를 입력 비율(192:256)에 맞게 넓히고
+// padding 배만큼 키운 Box 를 만든다. padding 이 0 이하이면 1 로 본다.
+func BoxFromXYWH(x, y, w, h, padding float32) Box {
+	if padding <= 0 {
+		padding = 1
+	}
+	center := [2]float32{x + w*0.5, y + h*0.5}
+	const aspect = float32(InputWidth) / float32(InputHeight)
+	if w > aspect*h {
+		h = w / aspect
+	} else if w < aspect*h {
+		w = h * aspect
+	}
+	return Box{
+		Center: center,
+		Scale:  [2]float32{w * padding, h * padding},
+	}
+}
+
+// Left 박스의 왼쪽 경계 x 좌표
+func (b Box) Left() float32 { return b.Center[0] - b.Scale[0]*0.5 }
+
+// Top 박스의 위쪽 경계 y 좌표
+func (b Box) Top() float32 { return b.Center[1] - b.Scale[1]*0.5 }
+
+// InputBox 입력 crop(192x256) 전체를 가리키는 박스. 요청에 center/scale 이 없을 때 postprocess 가 쓰는 기본값이다.
+var InputBox = Box{
+	Center: [2]float32{InputWidth * 0.5, InputHeight * 0.5},
+	Scale:  [2]float32{InputWidth, InputHeight},
+}
+
+// ToImage width x height 격자 위의 좌표 (x, y) 를 원본 이미지 좌표로 옮긴다.
+// postprocess/util.py 의 transform_preds 와 같은 계산이다.
+func (b Box) ToImage(x, y float32, width, height int, udp bool) (float32, float32) {
+	var scaleX, scaleY float32
+	if udp {
+		scaleX = b.Scale[0] / float32(width-1)
+		scaleY = b.Scale[1] / float32(height-1)
+	} else {
+		scaleX = b.Scale[0] / float32(width)
+		scaleY = b.Scale[1] / float32(height)
+	}
+	return x*scaleX + b.Left(), y*scaleY + b.Top()
+}
+
+// ToImage 모든 키포인트를 width x height 격자 좌표에서 box 기준 원본 이미지 좌표로 옮긴 Pose 를 반환한다.
+func (p Pose) ToImage(box Box, width, height int, udp bool) Pose {
+	for k := range p.Keypoints {
+		kp := &p.Keypoints[k]
+		kp.X, kp.Y = box.ToImage(kp.X, kp.Y, width, height, udp)
+	}
+	return p
+}
+
+// FromCrop 입력 crop (192x256) 의 픽셀 좌표 (u, v) 에 대응하는 원본 이미지 좌표.
+// preprocess.Crop 이 샘플링에 쓰는 격자로, crop 의 첫 픽셀과 마지막 픽셀을 박스의 양 끝에 맞춘다
+// (한 칸 = Scale/(192-1), Scale/(256-1)). 키포인트를 옮길 때는 Pose.FromCrop 을 쓴다.
+func (b Box) FromCrop(u, v float32) (float32, float32) {
+	return b.ToImage(u, v, InputWidth, InputHeight, true)
+}
+
+// FromCrop InputBox 기준으로 돌려받은 crop 좌표의 Pose 를 box 기준 원본 이미지 좌표로 옮긴다.
+// postprocess 는 InputBox 에 대해 히트맵 좌표 x 를 x*192/(48-1) 로 돌려주므로 crop 폭 192 로 나눠야
+// 서버에 box 를 보냈을 때 (x*w/(48-1)) 와 같은 좌표가 된다. Box.FromCrop 의 격자와는 다르다.
+func (p Pose) FromCrop(box Box) Pose {
+	return p.ToImage(box, InputWidth, InputHeight, false)
+}
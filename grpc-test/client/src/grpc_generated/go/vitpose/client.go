@@ -0,0 +1,208 @@
+// Package vitpose 는 Triton 에 올라간 vitpose_ensemble 모델을 호출하는 클라이언트를 제공한다.
+package vitpose
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+)
+
+const (
+	DefaultModelName  = "vitpose_ensemble"
+	DefaultInputName  = "input"
+	DefaultOutputName = "post_output"
+	DefaultCenterName = "center"
+	DefaultScaleName  = "scale"
+	DefaultTimeout    = 10 * time.Second
+
+	// 모델 입력 텐서 크기 (CHW)
+	InputChannels = 3
+	InputHeight   = 256
+	InputWidth    = 192
+	ImageSize     = InputChannels * InputHeight * InputWidth
+)
+
+// Option Client 설정을 바꾸는 함수
+type Option func(*Client)
+
+// WithModelName 호출할 모델 이름을 지정한다.
+func WithModelName(name string) Option {
+	return func(c *Client) { c.modelName = name }
+}
+
+// WithModelVersion 호출할 모델 버전을 지정한다. 빈 문자열이면 최신 버전을 사용한다.
+func WithModelVersion(version string) Option {
+	return func(c *Client) { c.modelVersion = version }
+}
+
+// WithTimeout 요청 하나에 적용할 타임아웃을 지정한다. 0 이면 ctx 의 데드라인만 따른다.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.timeout = timeout }
+}
+
+// WithInputName 입력 텐서 이름을 지정한다.
+func WithInputName(name string) Option {
+	return func(c *Client) { c.inputName = name }
+}
+
+// WithOutputName 출력 텐서 이름을 지정한다.
+func WithOutputName(name string) Option {
+	return func(c *Client) { c.outputName = name }
+}
+
+// WithBoxInputNames Batch.Boxes 를 보낼 center/scale 입력 텐서 이름을 지정한다.
+func WithBoxInputNames(center, scale string) Option {
+	return func(c *Client) { c.centerName, c.scaleName = center, scale }
+}
+
+// WithLocalTransform center/scale 을 서버로 보내지 않고, 서버가 돌려준 crop 좌표를
+// Batch.Boxes 기준 원본 좌표로 클라이언트에서 직접 옮긴다. center/scale 입력이 없는 모델 설정에 쓴다.
+func WithLocalTransform() Option {
+	return func(c *Client) { c.localTransform = true }
+}
+
+// Decoder 응답의 name 출력 텐서를 batch 에 대한 Pose 로 바꾼다.
+type Decoder func(response *triton.ModelInferResponse, name string, batch *Batch) ([]Pose, error)
+
+// WithDecoder post_output 대신 d 로 응답을 해석한다. heatmap.NewDecoder 와 함께 raw vitpose 모델을 직접 호출할 때 쓴다.
+// d 가 Batch.Boxes 좌표 변환까지 맡으므로 center/scale 은 서버로 보내지 않는다.
+func WithDecoder(d Decoder) Option {
+	return func(c *Client) {
+		c.decoder = d
+		c.localTransform = true
+	}
+}
+
+// Client triton.GRPCInferenceServiceClient 를 감싸 vitpose 추론 요청을 보내는 클라이언트
+type Client struct {
+	triton       triton.GRPCInferenceServiceClient
+	modelName    string
+	modelVersion string
+	inputName    string
+	outputName   string
+	centerName   string
+	scaleName    string
+	timeout      time.Duration
+
+	localTransform bool
+	decoder        Decoder
+}
+
+// NewClient 기본 설정에 opts 를 적용한 Client 를 만든다.
+func NewClient(client triton.GRPCInferenceServiceClient, opts ...Option) *Client {
+	c := &Client{
+		triton:     client,
+		modelName:  DefaultModelName,
+		inputName:  DefaultInputName,
+		outputName: DefaultOutputName,
+		centerName: DefaultCenterName,
+		scaleName:  DefaultScaleName,
+		timeout:    DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ModelName 설정된 모델 이름
+func (c *Client) ModelName() string { return c.modelName }
+
+// ModelVersion 설정된 모델 버전
+func (c *Client) ModelVersion() string { return c.modelVersion }
+
+// Result 추론 결과. Latency 는 ModelInfer 호출에 걸린 시간이다. 커넥션의 retry 정책이 ModelInfer 를
+// 다시 시도하면 재시도와 그 사이에 기다린 시간도 들어가므로, 부하 테스트는 ModelInfer 를 다시 시도하지 않는
+// config.LoadRetryPolicy 를 쓴다.
+type Result struct {
+	Poses   []Pose
+	Latency time.Duration
+}
+
+// Infer batch 를 모델에 보내고 사람별 키포인트를 돌려준다.
+// batch.Boxes 가 있으면 키포인트는 원본 이미지 좌표, 없으면 256x192 crop 좌표이다.
+func (c *Client) Infer(ctx context.Context, batch *Batch) (*Result, error) {
+	if batch == nil || batch.Len() == 0 {
+		return nil, fmt.Errorf("vitpose: empty batch")
+	}
+	if batch.Boxes != nil && len(batch.Boxes) != batch.Len() {
+		return nil, fmt.Errorf("vitpose: batch has %d images but %d boxes", batch.Len(), len(batch.Boxes))
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	request := &triton.ModelInferRequest{
+		ModelName:    c.modelName,
+		ModelVersion: c.modelVersion,
+		Inputs: []*triton.ModelInferRequest_InferInputTensor{
+			{
+				Name:     c.inputName,
+				Datatype: "FP32",
+				Shape:    []int64{int64(batch.Len()), InputChannels, InputHeight, InputWidth},
+			},
+		},
+		Outputs: []*triton.ModelInferRequest_InferRequestedOutputTensor{
+			{Name: c.outputName},
+		},
+		RawInputContents: [][]byte{encodeFP32(batch.Data)},
+	}
+	if batch.Boxes != nil && !c.localTransform {
+		center, scale := batch.centerScale()
+		shape := []int64{int64(batch.Len()), 2}
+		request.Inputs = append(request.Inputs,
+			&triton.ModelInferRequest_InferInputTensor{Name: c.centerName, Datatype: "FP32", Shape: shape},
+			&triton.ModelInferRequest_InferInputTensor{Name: c.scaleName, Datatype: "FP32", Shape: shape},
+		)
+		request.RawInputContents = append(request.RawInputContents, encodeFP32(center), encodeFP32(scale))
+	}
+
+	startTime := time.Now()
+	response, err := c.triton.ModelInfer(ctx, request)
+	latency := time.Since(startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	decode := c.decoder
+	if decode == nil {
+		decode = c.decodePostOutput
+	}
+	poses, err := decode(response, c.outputName, batch)
+	if err != nil {
+		return nil, err
+	}
+	if len(poses) != batch.Len() {
+		return nil, fmt.Errorf("vitpose: got %d poses for a batch of %d", len(poses), batch.Len())
+	}
+	return &Result{Poses: poses, Latency: latency}, nil
+}
+
+// decodePostOutput 기본 Decoder. WithLocalTransform 이면 crop 좌표를 Batch.Boxes 기준으로 옮긴다.
+func (c *Client) decodePostOutput(response *triton.ModelInferResponse, name string, batch *Batch) ([]Pose, error) {
+	poses, err := DecodePoses(response, name)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Boxes != nil && c.localTransform && len(poses) == len(batch.Boxes) {
+		for i := range poses {
+			poses[i] = poses[i].FromCrop(batch.Boxes[i])
+		}
+	}
+	return poses, nil
+}
+
+// encodeFP32 float32 슬라이스를 little-endian 바이트로 변환한다.
+func encodeFP32(data []float32) []byte {
+	buf := make([]byte, 4*len(data))
+	for i, v := range data {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
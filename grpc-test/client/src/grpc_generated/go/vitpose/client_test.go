@@ -0,0 +1,222 @@
+package vitpose_test
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/heatmap"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/tritontest"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+func newClient(t *testing.T, opts ...vitpose.Option) (*tritontest.Server, *vitpose.Client) {
+	t.Helper()
+	server := tritontest.NewServer()
+	if err := server.LoadModelRepository("../../../../../../pose_model_zoo"); err != nil {
+		t.Fatal(err)
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return server, vitpose.NewClient(triton.NewGRPCInferenceServiceClient(conn), opts...)
+}
+
+// recorder 받은 ModelInfer 요청을 기록하고 post_output 형식의 응답을 돌려주는 가짜 서버
+type recorder struct {
+	triton.GRPCInferenceServiceClient
+	outputName  string
+	request     *triton.ModelInferRequest
+	deadline    time.Duration
+	hasDeadline bool
+}
+
+func (r *recorder) ModelInfer(ctx context.Context, in *triton.ModelInferRequest, _ ...grpc.CallOption) (*triton.ModelInferResponse, error) {
+	r.request = in
+	if d, ok := ctx.Deadline(); ok {
+		r.deadline, r.hasDeadline = time.Until(d), true
+	}
+	return postOutput(r.outputName, int(in.Inputs[0].Shape[0])), nil
+}
+
+// postOutput n 명의 [n, 17, 3] FP32 응답. 사람 n 의 관절 k 는 (n, k, 0.5) 이다.
+func postOutput(name string, n int) *triton.ModelInferResponse {
+	raw := make([]byte, n*vitpose.NumKeypoints*3*4)
+	for i := 0; i < n; i++ {
+		for k := 0; k < vitpose.NumKeypoints; k++ {
+			offset := (i*vitpose.NumKeypoints + k) * 12
+			binary.LittleEndian.PutUint32(raw[offset:], math.Float32bits(float32(i)))
+			binary.LittleEndian.PutUint32(raw[offset+4:], math.Float32bits(float32(k)))
+			binary.LittleEndian.PutUint32(raw[offset+8:], math.Float32bits(0.5))
+		}
+	}
+	return &triton.ModelInferResponse{
+		Outputs:           []*triton.ModelInferResponse_InferOutputTensor{{Name: name, Datatype: "FP32", Shape: []int64{int64(n), vitpose.NumKeypoints, 3}}},
+		RawOutputContents: [][]byte{raw},
+	}
+}
+
+func TestOptions(t *testing.T) {
+	box := vitpose.BoxFromXYWH(0, 0, 96, 128, 1)
+	tests := []struct {
+		name          string
+		opts          []vitpose.Option
+		model         string
+		version       string
+		output        string
+		inputs        []string
+		timeout       time.Duration // 0 이면 데드라인이 없어야 한다.
+		wantBoxPoints bool
+	}{
+		{"default", nil, "vitpose_ensemble", "", "post_output", []string{"input", "center", "scale"}, vitpose.DefaultTimeout, false},
+		{"model", []vitpose.Option{vitpose.WithModelName("vitpose_udp"), vitpose.WithModelVersion("2")},
+			"vitpose_udp", "2", "post_output", []string{"input", "center", "scale"}, vitpose.DefaultTimeout, false},
+		{"tensor names", []vitpose.Option{vitpose.WithInputName("pixels"), vitpose.WithOutputName("keypoints"), vitpose.WithBoxInputNames("c", "s")},
+			"vitpose_ensemble", "", "keypoints", []string{"pixels", "c", "s"}, vitpose.DefaultTimeout, false},
+		{"timeout", []vitpose.Option{vitpose.WithTimeout(time.Second)}, "vitpose_ensemble", "", "post_output", []string{"input", "center", "scale"}, time.Second, false},
+		{"no timeout", []vitpose.Option{vitpose.WithTimeout(0)}, "vitpose_ensemble", "", "post_output", []string{"input", "center", "scale"}, 0, false},
+		// center/scale 을 보내지 않고 crop 좌표를 직접 옮긴다.
+		{"local transform", []vitpose.Option{vitpose.WithLocalTransform()}, "vitpose_ensemble", "", "post_output", []string{"input"}, vitpose.DefaultTimeout, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{outputName: tt.output}
+			client := vitpose.NewClient(r, tt.opts...)
+			if client.ModelName() != tt.model || client.ModelVersion() != tt.version {
+				t.Errorf("model = %s:%s, want %s:%s", client.ModelName(), client.ModelVersion(), tt.model, tt.version)
+			}
+
+			batch := vitpose.RandomBatch(2)
+			batch.Boxes = []vitpose.Box{box, box}
+			result, err := client.Infer(context.Background(), batch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := r.request
+			if req.ModelName != tt.model || req.ModelVersion != tt.version {
+				t.Errorf("request model = %s:%s, want %s:%s", req.ModelName, req.ModelVersion, tt.model, tt.version)
+			}
+			var inputs []string
+			for _, in := range req.Inputs {
+				inputs = append(inputs, in.Name)
+			}
+			if !slices.Equal(inputs, tt.inputs) || len(req.RawInputContents) != len(tt.inputs) {
+				t.Errorf("inputs = %v with %d raw contents, want %v", inputs, len(req.RawInputContents), tt.inputs)
+			}
+			if want := []int64{2, 3, 256, 192}; !slices.Equal(req.Inputs[0].Shape, want) || len(req.RawInputContents[0]) != 2*vitpose.ImageSize*4 {
+				t.Errorf("input shape = %v with %d bytes, want %v", req.Inputs[0].Shape, len(req.RawInputContents[0]), want)
+			}
+			if len(req.Outputs) != 1 || req.Outputs[0].Name != tt.output {
+				t.Errorf("requested outputs = %v, want %s", req.Outputs, tt.output)
+			}
+			if tt.timeout == 0 && r.hasDeadline {
+				t.Errorf("request has a deadline in %v, want none", r.deadline)
+			}
+			if tt.timeout > 0 && (!r.hasDeadline || r.deadline > tt.timeout || r.deadline < tt.timeout-time.Second/2) {
+				t.Errorf("request deadline in %v, want about %v", r.deadline, tt.timeout)
+			}
+
+			// 서버가 돌려준 좌표는 local transform 일 때만 박스 기준으로 옮긴다.
+			kp := result.Poses[1].Joint(vitpose.Nose)
+			want := vitpose.Keypoint{X: 1, Y: 0, Score: 0.5}
+			if tt.wantBoxPoints {
+				var pose vitpose.Pose
+				pose.Keypoints[vitpose.Nose] = want
+				want = pose.FromCrop(box).Joint(vitpose.Nose)
+			}
+			if kp != want {
+				t.Errorf("person 1 nose = %+v, want %+v", kp, want)
+			}
+		})
+	}
+}
+
+func TestInferEmptyBatch(t *testing.T) {
+	client := vitpose.NewClient(&recorder{outputName: vitpose.DefaultOutputName})
+	for _, batch := range []*vitpose.Batch{nil, vitpose.NewBatch(0)} {
+		if _, err := client.Infer(context.Background(), batch); err == nil {
+			t.Errorf("Infer(%v) succeeded", batch)
+		}
+	}
+}
+
+func TestInfer(t *testing.T) {
+	_, client := newClient(t)
+
+	result, err := client.Infer(context.Background(), vitpose.RandomBatch(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Poses) != 3 {
+		t.Fatalf("got %d poses, want 3", len(result.Poses))
+	}
+	// tritontest.SyntheticOutput: 관절 k 는 (48+6k, 64+8k), 신뢰도 0.9
+	kp := result.Poses[2].Joint(vitpose.LeftShoulder)
+	if kp.X != 78 || kp.Y != 104 || kp.Score != 0.9 {
+		t.Errorf("left_shoulder = %+v, want {78 104 0.9}", kp)
+	}
+}
+
+func TestInferWithBoxes(t *testing.T) {
+	box := vitpose.BoxFromXYWH(100, 40, 96, 128, 1)
+
+	// center/scale 을 보내면 서버가 좌표를 옮기므로 클라이언트는 그대로 돌려준다.
+	_, client := newClient(t)
+	batch := vitpose.RandomBatch(1)
+	batch.Boxes = []vitpose.Box{box}
+	result, err := client.Infer(context.Background(), batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kp := result.Poses[0].Joint(vitpose.Nose); kp.X != 48 || kp.Y != 64 {
+		t.Errorf("nose = %+v, want server coordinates (48, 64)", kp)
+	}
+
+	// WithLocalTransform 이면 crop 좌표를 직접 옮긴다: Left + x * w / 192
+	_, client = newClient(t, vitpose.WithLocalTransform())
+	result, err = client.Infer(context.Background(), batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantX, wantY := float32(100+48*96.0/192), float32(40+64*128.0/256)
+	if kp := result.Poses[0].Joint(vitpose.Nose); math.Abs(float64(kp.X-wantX)) > 1e-3 || math.Abs(float64(kp.Y-wantY)) > 1e-3 {
+		t.Errorf("nose = %+v, want (%v, %v)", kp, wantX, wantY)
+	}
+
+	batch.Boxes = append(batch.Boxes, box)
+	if _, err := client.Infer(context.Background(), batch); err == nil {
+		t.Error("Infer with more boxes than images succeeded")
+	}
+}
+
+func TestInferRawModel(t *testing.T) {
+	_, client := newClient(t,
+		vitpose.WithModelName("vitpose"),
+		vitpose.WithOutputName(heatmap.DefaultOutputName),
+		vitpose.WithDecoder(heatmap.NewDecoder(heatmap.DefaultOptions)),
+	)
+
+	result, err := client.Infer(context.Background(), vitpose.RandomBatch(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SyntheticOutput 히트맵의 최댓값은 (24, 32) 이고 UDP 변환으로 (24*192/47, 32*256/63) 이 된다.
+	kp := result.Poses[1].Joint(vitpose.RightAnkle)
+	if d := kp.X - 24*192.0/47; d > 0.05 || d < -0.05 {
+		t.Errorf("right_ankle x = %v, want %v", kp.X, 24*192.0/47)
+	}
+	if d := kp.Y - 32*256.0/63; d > 0.05 || d < -0.05 {
+		t.Errorf("right_ankle y = %v, want %v", kp.Y, 32*256.0/63)
+	}
+}
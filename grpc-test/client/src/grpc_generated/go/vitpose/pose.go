@@ -0,0 +1,151 @@
+package vitpose
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+)
+
+// NumKeypoints 사람 한 명당 키포인트 수 (COCO)
+const NumKeypoints = 17
+
+// Joint COCO-17 관절 인덱스. post_output 의 두 번째 축 순서와 같다.
+type Joint int
+
+const (
+	Nose Joint = iota
+	LeftEye
+	RightEye
+	LeftEar
+	RightEar
+	LeftShoulder
+	RightShoulder
+	LeftElbow
+	RightElbow
+	LeftWrist
+	RightWrist
+	LeftHip
+	RightHip
+	LeftKnee
+	RightKnee
+	LeftAnkle
+	RightAnkle
+)
+
+var jointNames = [NumKeypoints]string{
+	"nose",
+	"left_eye",
+	"right_eye",
+	"left_ear",
+	"right_ear",
+	"left_shoulder",
+	"right_shoulder",
+	"left_elbow",
+	"right_elbow",
+	"left_wrist",
+	"right_wrist",
+	"left_hip",
+	"right_hip",
+	"left_knee",
+	"right_knee",
+	"left_ankle",
+	"right_ankle",
+}
+
+// String COCO 관절 이름 (예: "left_shoulder")
+func (j Joint) String() string {
+	if j < 0 || int(j) >= NumKeypoints {
+		return fmt.Sprintf("joint(%d)", int(j))
+	}
+	return jointNames[j]
+}
+
+// Keypoint 이미지 좌표계의 관절 위치와 신뢰도. Score 는 히트맵 최댓값(post_output 의 세 번째 열)이다.
+type Keypoint struct {
+	X     float32
+	Y     float32
+	Score float32
+}
+
+// Pose 사람 한 명의 키포인트. 인덱스는 Joint 순서를 따른다.
+type Pose struct {
+	Keypoints [NumKeypoints]Keypoint
+}
+
+// Visible Score 가 threshold 이상인지 여부
+func (k Keypoint) Visible(threshold float32) bool {
+	return k.Score >= threshold
+}
+
+// Joint j 관절의 키포인트
+func (p Pose) Joint(j Joint) Keypoint {
+	return p.Keypoints[j]
+}
+
+// Score 키포인트 신뢰도의 평균
+func (p Pose) Score() float32 {
+	var sum float32
+	for _, kp := range p.Keypoints {
+		sum += kp.Score
+	}
+	return sum / NumKeypoints
+}
+
+// VisibleJoints Score 가 threshold 이상인 관절 목록
+func (p Pose) VisibleJoints(threshold float32) []Joint {
+	var joints []Joint
+	for k, kp := range p.Keypoints {
+		if kp.Visible(threshold) {
+			joints = append(joints, Joint(k))
+		}
+	}
+	return joints
+}
+
+// DecodePoses 응답에서 name 출력 텐서를 찾아 [N, 17, 3] FP32 로 해석한다.
+// 출력 메타데이터의 datatype 과 shape 이 맞지 않으면 에러를 반환한다.
+func DecodePoses(response *triton.ModelInferResponse, name string) ([]Pose, error) {
+	index := -1
+	for i, output := range response.GetOutputs() {
+		if output.GetName() == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("vitpose: output %q not found in response from model %q", name, response.GetModelName())
+	}
+	output := response.Outputs[index]
+
+	if output.Datatype != "FP32" {
+		return nil, fmt.Errorf("vitpose: output %q has datatype %s, want FP32", name, output.Datatype)
+	}
+	shape := output.Shape
+	if len(shape) != 3 || shape[0] < 0 || shape[1] != NumKeypoints || shape[2] != 3 {
+		return nil, fmt.Errorf("vitpose: output %q has shape %v, want [N %d 3]", name, shape, NumKeypoints)
+	}
+	if index >= len(response.RawOutputContents) {
+		return nil, fmt.Errorf("vitpose: output %q has no raw contents (%d raw outputs for %d outputs)",
+			name, len(response.RawOutputContents), len(response.Outputs))
+	}
+	raw := response.RawOutputContents[index]
+	numPoses := int(shape[0])
+	if want := numPoses * NumKeypoints * 3 * 4; len(raw) != want {
+		return nil, fmt.Errorf("vitpose: output %q has %d bytes, want %d for shape %v", name, len(raw), want, shape)
+	}
+
+	poses := make([]Pose, numPoses)
+	for n := range poses {
+		for k := 0; k < NumKeypoints; k++ {
+			offset := (n*NumKeypoints + k) * 12
+			poses[n].Keypoints[k] = Keypoint{
+				X:     math.Float32frombits(binary.LittleEndian.Uint32(raw[offset:])),
+				Y:     math.Float32frombits(binary.LittleEndian.Uint32(raw[offset+4:])),
+				Score: math.Float32frombits(binary.LittleEndian.Uint32(raw[offset+8:])),
+			}
+		}
+	}
+	return poses, nil
+}
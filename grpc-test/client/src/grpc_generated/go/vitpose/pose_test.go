@@ -0,0 +1,75 @@
+package vitpose_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	triton "github.com/triton-inference-server/client/src/grpc_generated/go/grpc-client"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/vitpose"
+)
+
+func TestDecodePoses(t *testing.T) {
+	poses, err := vitpose.DecodePoses(postOutput("post_output", 2), "post_output")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(poses) != 2 {
+		t.Fatalf("got %d poses, want 2", len(poses))
+	}
+	if kp := poses[1].Joint(vitpose.RightAnkle); kp != (vitpose.Keypoint{X: 1, Y: 16, Score: 0.5}) {
+		t.Errorf("person 1 right_ankle = %+v, want {1 16 0.5}", kp)
+	}
+
+	// 사람이 0 명인 배치도 올바른 응답이다.
+	if poses, err := vitpose.DecodePoses(postOutput("post_output", 0), "post_output"); err != nil || len(poses) != 0 {
+		t.Errorf("DecodePoses of an empty batch = %v, %v", poses, err)
+	}
+}
+
+func TestDecodePosesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(r *triton.ModelInferResponse)
+		output string
+		want   string
+	}{
+		{"missing output", func(r *triton.ModelInferResponse) {}, "output", "not found"},
+		{"datatype", func(r *triton.ModelInferResponse) { r.Outputs[0].Datatype = "FP16" }, "post_output", "datatype FP16"},
+		{"two columns", func(r *triton.ModelInferResponse) { r.Outputs[0].Shape = []int64{1, 17, 2} }, "post_output", "shape"},
+		{"16 keypoints", func(r *triton.ModelInferResponse) { r.Outputs[0].Shape = []int64{1, 16, 3} }, "post_output", "shape"},
+		{"rank", func(r *triton.ModelInferResponse) { r.Outputs[0].Shape = []int64{17, 3} }, "post_output", "shape"},
+		{"negative batch", func(r *triton.ModelInferResponse) { r.Outputs[0].Shape = []int64{-1, 17, 3} }, "post_output", "shape"},
+		{"short contents", func(r *triton.ModelInferResponse) { r.Outputs[0].Shape = []int64{2, 17, 3} }, "post_output", "bytes"},
+		{"no contents", func(r *triton.ModelInferResponse) { r.RawOutputContents = nil }, "post_output", "no raw contents"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := postOutput("post_output", 1)
+			tt.change(response)
+			_, err := vitpose.DecodePoses(response, tt.output)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodePoses() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPose(t *testing.T) {
+	var pose vitpose.Pose
+	for k := range pose.Keypoints {
+		pose.Keypoints[k].Score = 0.1
+	}
+	pose.Keypoints[vitpose.LeftWrist].Score = 0.9
+	pose.Keypoints[vitpose.Nose].Score = 0.5
+
+	if got := pose.VisibleJoints(0.5); !slices.Equal(got, []vitpose.Joint{vitpose.Nose, vitpose.LeftWrist}) {
+		t.Errorf("VisibleJoints(0.5) = %v, want [nose left_wrist]", got)
+	}
+	if got, want := pose.Score(), float32(0.1*15+0.9+0.5)/17; got-want > 1e-6 || want-got > 1e-6 {
+		t.Errorf("Score() = %v, want %v", got, want)
+	}
+	if vitpose.LeftWrist.String() != "left_wrist" || vitpose.Joint(17).String() != "joint(17)" {
+		t.Errorf("joint names = %s, %s", vitpose.LeftWrist, vitpose.Joint(17))
+	}
+}
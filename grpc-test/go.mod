@@ -11,9 +11,14 @@ require (
 	golang.org/x/sys v0.26.0 // indirect
 	golang.org/x/text v0.19.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
 	golang.org/x/net v0.30.0 // indirect
-	google.golang.org/grpc v1.67.1
+	google.golang.org/grpc v1.67.1 // indirect
 )
+
+require github.com/triton-inference-server/client/src/grpc_generated/go v0.0.0
+
+replace github.com/triton-inference-server/client/src/grpc_generated/go => ./client/src/grpc_generated/go
@@ -2,55 +2,32 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
-	"time"
+	"os"
 
-	pb "grpc_test/gen"
-	genconnect "grpc_test/gen/genconnect"
-
-	"google.golang.org/grpc"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/config"
+	"github.com/triton-inference-server/client/src/grpc_generated/go/health"
 )
 
+// 서버와 모델 (-m, 앙상블이면 각 단계까지) 의 상태를 확인하고 실패 종류를 종료 코드로 돌려준다.
+// 출력 형식과 종료 코드는 cmd/health 와 같다.
 func main() {
-	// gRPC 서버 주소 설정
-	address := "34.47.107.11:8001"
+	// gRPC 서버 주소 설정 (-u, TRITON_URL 또는 -config)
+	cfg := config.MustLoad(config.ServerFlags, config.ModelFlags)
 
-	// gRPC 연결 설정
-	conn, err := grpc.Dial(address, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(5*time.Second))
+	// gRPC 연결 설정. 일시적인 실패는 retry 정책 (-retry-attempts 등) 으로 다시 시도한다.
+	conn, err := cfg.Dial(cfg.RetryPolicy().DialOption())
 	if err != nil {
-		log.Fatalf("Failed to connect to gRPC server: %v", err)
+		log.Printf("Failed to connect to gRPC server: %v", err)
+		os.Exit(int(health.Unreachable))
 	}
 	defer conn.Close()
 
-	// 헬스 체크 클라이언트 생성
-	healthClient := genconnect.NewHealthClient(conn)
-
-	// 헬스 체크 요청 생성
-	request := &pb.HealthCheckRequest{
-		Service: "", // 기본 서비스 헬스 체크를 수행하기 위해 빈 문자열 사용
-	}
-
-	// 헬스 체크 요청 수행
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.Timeout)
+	report := health.Check(ctx, conn, cfg.Server.URL, cfg.Model.Name, cfg.Model.Version)
+	cancel()
 
-	response, err := healthClient.Check(ctx, request)
-	if err != nil {
-		log.Fatalf("Health check failed: %v", err)
-	}
-
-	// 헬스 체크 응답 상태 출력
-	switch response.Status {
-	case pb.HealthCheckResponse_SERVING:
-		fmt.Println("The server is serving.")
-	case pb.HealthCheckResponse_NOT_SERVING:
-		fmt.Println("The server is not serving.")
-	case pb.HealthCheckResponse_UNKNOWN:
-		fmt.Println("The server status is unknown.")
-	case pb.HealthCheckResponse_SERVICE_UNKNOWN:
-		fmt.Println("The specified service is unknown.")
-	default:
-		fmt.Println("Unknown status received.")
-	}
+	report.WriteText(os.Stdout)
+	conn.Close()
+	os.Exit(report.ExitCode)
 }
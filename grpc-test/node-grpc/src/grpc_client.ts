@@ -115,7 +115,7 @@ async function main() {
     const protoDescriptor = grpc.loadPackageDefinition(packageDefinition);
     const inferenceService = (protoDescriptor.inference as any).GRPCInferenceService as grpc.ServiceClientConstructor;
 
-    const client = new inferenceService('34.47.107.11:8001', grpc.credentials.createInsecure());
+    const client = new inferenceService(process.env.TRITON_URL ?? 'localhost:8001', grpc.credentials.createInsecure());
 
     await simulateUser(client);
 }
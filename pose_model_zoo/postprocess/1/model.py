@@ -5,6 +5,10 @@ import triton_python_backend_utils as pb_utils
 
 from util import keypoints_from_heatmaps
 
+# 입력 crop(폭 192, 높이 256) 전체를 가리키는 박스. center 는 (x, y), scale 은 (w, h)
+DEFAULT_CENTER = np.array([96., 128.], dtype=np.float32)
+DEFAULT_SCALE = np.array([192., 256.], dtype=np.float32)
+
 # import
 class TritonPythonModel:
     """Your Python model must use the same class name. Every Python model
@@ -59,14 +63,21 @@ class TritonPythonModel:
             input = pb_utils.get_input_tensor_by_name(request, "post_input")
             input = input.as_numpy()
             batch_size = input.shape[0]
-    
-            # 배치 크기에 맞게 center와 scale 생성
-            center = np.tile(np.array([128., 96.]), (batch_size, 1))
-            scale = np.tile(np.array([192., 256.]), (batch_size, 1))
 
-            # HARD CODING
-            keypoints, prob = keypoints_from_heatmaps(heatmaps=input, center=center, scale=scale, use_udp=True)
+            # 요청에 center/scale 이 없으면 256x192 입력 crop 좌표계를 기본값으로 사용
+            center = self._get_box_input(request, "center", DEFAULT_CENTER, batch_size)
+            scale = self._get_box_input(request, "scale", DEFAULT_SCALE, batch_size)
+            if center is None or scale is None:
+                responses.append(pb_utils.InferenceResponse(
+                    output_tensors=[],
+                    error=pb_utils.TritonError(
+                        "center and scale must have shape [{}, 2]".format(batch_size))))
+                continue
+
+            preds, maxvals = keypoints_from_heatmaps(heatmaps=input, center=center, scale=scale, use_udp=True)
 
+            # [N, 17, 3]: x, y, score
+            keypoints = np.concatenate((preds, maxvals), axis=2).astype(np.float32)
             post_output = pb_utils.Tensor("post_output", keypoints)
 
             inference_response = pb_utils.InferenceResponse(
@@ -76,6 +87,18 @@ class TritonPythonModel:
 
         return responses
 
+    def _get_box_input(self, request, name, default, batch_size):
+        """Read an optional [N, 2] box input, falling back to `default`.
+        Returns None if the tensor is present but has the wrong shape.
+        """
+        tensor = pb_utils.get_input_tensor_by_name(request, name)
+        if tensor is None:
+            return np.tile(default, (batch_size, 1))
+        value = tensor.as_numpy().astype(np.float32)
+        if value.shape != (batch_size, 2):
+            return None
+        return value
+
     def finalize(self):
         """`finalize` is called only once when the model is being unloaded.
         Implementing `finalize` function is OPTIONAL. This function allows